@@ -157,6 +157,29 @@ func TestCLI_DeparturesCommand_WithModes(t *testing.T) {
 	}
 }
 
+func TestCLI_DeparturesCommand_JourneyIDOnly(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping API call in short mode")
+	}
+
+	stdout, _, exitCode := runCommand(t, "departures", "8000105:A=1@O=Frankfurt(Main)Hbf@X=8663003@Y=50107145@U=80@L=8000105@", "--modes", "SBAHN", "--limit", "3", "--journey-id-only", "--color", "always")
+
+	if exitCode != 0 {
+		t.Errorf("Expected exit code 0, got %d", exitCode)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) == 0 || len(lines) > 3 {
+		t.Fatalf("Expected 1-3 lines, got %d: %q", len(lines), stdout)
+	}
+	for _, line := range lines {
+		// Journey IDs are opaque (and may contain spaces) but never colored
+		if line == "" || strings.ContainsAny(line, "\t\x1b") {
+			t.Errorf("Expected a bare journey ID, got %q", line)
+		}
+	}
+}
+
 func TestCLI_ArrivalsCommand_Help(t *testing.T) {
 	stdout, _, exitCode := runCommand(t, "arrivals", "--help")
 
@@ -1,16 +1,35 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
+	_ "time/tzdata" // embed zone database for minimal containers without tzdata
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+	"github.com/mattn/go-isatty"
 	"github.com/mobil-koeln/moko-cli/internal/api"
+	"github.com/mobil-koeln/moko-cli/internal/cache"
+	"github.com/mobil-koeln/moko-cli/internal/config"
+	"github.com/mobil-koeln/moko-cli/internal/favorites"
 	"github.com/mobil-koeln/moko-cli/internal/models"
 	"github.com/mobil-koeln/moko-cli/internal/output"
 	"github.com/mobil-koeln/moko-cli/internal/tui"
@@ -20,7 +39,10 @@ import (
 var version = "0.4.0"
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	closeClients()
+	discardOutput()
+	if err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -36,48 +58,223 @@ Features:
   - Departure and arrival boards at any station
   - Journey/trip details with all stops
   - Station search by name or geographic coordinates
+  - Station details: IDs, coordinates and products served
   - Train carriage formation (Wagenreihung)
   - Filter by transport modes (ICE, EC/IC, Regional, S-Bahn, etc.)
   - JSON output for scripting
   - Response caching for faster repeated queries
 
 Quick Start:
-  1. Launch TUI:               moko (or moko tui)
+  1. Launch TUI:               moko (or moko tui; needs a terminal)
   2. Search for a station:     moko search "Frankfurt Hbf"
   3. Show departures:          moko departures <eva>:<station_id>
   4. Show arrivals:            moko arrivals <eva>:<station_id>
   5. Find nearby stations:     moko nearby 50.107:8.663
   6. Get journey details:      moko journey <journey_id>
   7. Show train formation:     moko formation <eva> ICE 623`,
-	Version: version,
+	Version:            version,
+	PersistentPreRunE:  setupRun,
+	PersistentPostRunE: closeOutput,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// If no subcommand is provided, launch TUI
-		if len(args) == 0 {
+		// If no subcommand is provided, run the configured default command
+		// or launch the TUI — unless disabled or not attached to a terminal
+		// (CI, cron, pipes)
+		if len(args) == 0 && !flagNoTUI && isInteractive() {
+			cfg, err := config.Load(config.DefaultPath())
+			if err != nil {
+				return err
+			}
+			if cfg.DefaultCommand != "" {
+				return runDefaultCommand(cmd, cfg.DefaultCommand)
+			}
 			return runTUI(cmd, args)
 		}
 		return cmd.Help()
 	},
 }
 
+// runDefaultCommand runs the default_command from the config as if its words
+// had been given to root on the command line
+func runDefaultCommand(root *cobra.Command, line string) error {
+	words, err := splitCommandLine(line)
+	if err != nil {
+		return fmt.Errorf("invalid default_command %q: %w", line, err)
+	}
+	sub, subArgs, err := root.Find(words)
+	if err != nil || sub == root || sub.RunE == nil {
+		return fmt.Errorf("invalid default_command %q: it must start with a command such as 'departures'", line)
+	}
+	if err := sub.ParseFlags(subArgs); err != nil {
+		return fmt.Errorf("invalid default_command %q: %w", line, err)
+	}
+	args := sub.Flags().Args()
+	if err := sub.ValidateArgs(args); err != nil {
+		return fmt.Errorf("invalid default_command %q: %w", line, err)
+	}
+	return sub.RunE(sub, args)
+}
+
+// splitCommandLine splits a command line into words at spaces. Single or
+// double quotes group words, e.g. for a station ID containing spaces.
+func splitCommandLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// isInteractive reports whether stdin and stdout are both terminals.
+// Replaceable in tests.
+var isInteractive = func() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+}
+
+// clock returns the current time: the real time, or the --as-of time set up
+// by setupClock. Replaceable in tests.
+var clock = time.Now
+
+// startTUI runs the full-screen TUI program. Replaceable in tests.
+var startTUI = func(model tea.Model) error {
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
 // Global flags
 var (
-	flagDate    string
-	flagTime    string
-	flagJSON    bool
-	flagRawJSON bool
-	flagColor   string
-	flagNoCache bool
-	flagShowVia bool
+	flagDate     string
+	flagTime     string
+	flagJSON     bool
+	flagRawJSON  bool
+	flagColor    string
+	flagNoCache  bool
+	flagMemCache bool
+	flagOffline  bool
+	flagMaxAge   time.Duration
+	flagDualTZ   string
+	flagAsOf     string
+	flagHeaders  []string
+	flagStrict   bool
+	flagNoEmoji  bool
+	flagBare     bool
+	flagShowVia  bool
+	flagMessages bool
+	flagNoTUI    bool
+	flagOut      string
+	flagTheme    string
+)
+
+// theme is the palette chosen with --theme or in the config
+var theme = output.DefaultTheme
+
+// outWriter is where command output goes: stdout, or the file given by --out
+var (
+	outWriter io.Writer = os.Stdout
+	outFile   *os.File
 )
 
 // Departures/Arrivals flags
 var (
-	flagNumVias   int
-	flagModes     []string
-	flagLine      string
-	flagDirection string
-	flagWatch     bool
-	flagJourney   bool
+	flagNumVias    int
+	flagModes      []string
+	flagLine       string
+	flagDirection  string
+	flagWatch      bool
+	flagInterval   time.Duration
+	flagJourney    bool
+	flagWindow     int
+	flagLegend     bool
+	flagGroupBy    string
+	flagCompact    bool
+	flagSeparator  bool
+	flagPreferRT   bool
+	flagPrefSched  bool
+	flagColsAuto   bool
+	flagLead       int
+	flagLimit      int
+	flagDirExact   bool
+	flagDelayStyle string
+	flagEmptyRetry int
+	flagRetryDelay time.Duration
+	flagAutoModes  bool
+	flagDedupe     bool
+	flagHomeMark   bool
+	flagTemplate   string
+	flagIDsOnly    bool
+	flagSort       string
+	flagAggregate  bool
+	flagAccOnly    bool
+	flagAccStrict  bool
+)
+
+// Search flags
+var (
+	flagNoRank      bool
+	flagSaveAs      string
+	flagInteractive bool
+)
+
+// Nearby flags
+var (
+	flagHere   bool
+	flagRadius int
+	flagMaxNo  int
+)
+
+// Formation flags
+var (
+	flagSVG bool
+)
+
+// Connections flags
+var (
+	flagRepeat   bool
+	flagSaveTrip string
+)
+
+// Favorites flags
+var (
+	flagFavForce bool
+)
+
+// Journey flags
+var (
+	flagShare     bool
+	flagSummary   bool
+	flagStep      bool
+	flagTimetable bool
+	flagGroupLegs bool
+	flagEstimate  bool
+	flagHideCanc  bool
+	flagConnAt    []int64
+	flagFormat    string
+	flagPolyline  bool
 )
 
 func init() {
@@ -86,55 +283,411 @@ func init() {
 	rootCmd.AddCommand(arrivalsCmd)
 	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(nearbyCmd)
+	rootCmd.AddCommand(stationCmd)
 	rootCmd.AddCommand(journeyCmd)
 	rootCmd.AddCommand(formationCmd)
+	rootCmd.AddCommand(connectionsCmd)
+	rootCmd.AddCommand(favCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(tuiCmd)
+	favCmd.AddCommand(favAddCmd, favListCmd, favRmCmd)
+
+	// Complete station arguments from the station search
+	departuresCmd.ValidArgsFunction = completeStation
+	arrivalsCmd.ValidArgsFunction = completeStation
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVarP(&flagDate, "date", "d", "", "Date (DD.MM.YYYY or YYYY-MM-DD)")
 	rootCmd.PersistentFlags().StringVarP(&flagTime, "time", "t", "", "Time (HH:MM)")
 	rootCmd.PersistentFlags().BoolVar(&flagJSON, "json", false, "Output as JSON")
 	rootCmd.PersistentFlags().BoolVar(&flagRawJSON, "raw-json", false, "Output raw API response")
-	rootCmd.PersistentFlags().StringVar(&flagColor, "color", "auto", "Color output: auto, always, never")
+	rootCmd.PersistentFlags().StringVar(&flagColor, "color", "auto", "Color output: auto, always, never (auto honors NO_COLOR and FORCE_COLOR)")
 	rootCmd.PersistentFlags().BoolVar(&flagNoCache, "no-cache", false, "Disable response caching")
+	rootCmd.PersistentFlags().BoolVar(&flagMemCache, "memory-cache", false, "Cache responses in memory only, without writing to disk")
+	rootCmd.PersistentFlags().BoolVar(&flagOffline, "offline", false, "Serve responses from the cache only, of any age, without network access")
+	rootCmd.PersistentFlags().DurationVar(&flagMaxAge, "max-age", 0, "Refetch cached responses older than this (e.g. 20s)")
+	rootCmd.PersistentFlags().StringVar(&flagDualTZ, "dual-tz", "", "Also show times in this zone, e.g. Europe/London or Local")
+	rootCmd.PersistentFlags().BoolVar(&flagNoEmoji, "no-emoji", false, "Use ASCII instead of Unicode glyphs")
+	rootCmd.PersistentFlags().BoolVar(&flagBare, "bare", false, "Omit headers and blank lines, e.g. for grep")
+	rootCmd.PersistentFlags().BoolVar(&flagStrict, "strict-time", false, "Reject --date/--time values in the past")
+	rootCmd.PersistentFlags().StringVar(&flagAsOf, "as-of", "", "Pretend it is this time (HH:MM or YYYY-MM-DD HH:MM), e.g. for demos")
+	rootCmd.PersistentFlags().StringArrayVar(&flagHeaders, "header", nil, "Set a request header, e.g. 'Referer: https://www.bahn.de/' (repeatable; 'Name:' removes it)")
+	rootCmd.PersistentFlags().StringVarP(&flagOut, "out", "o", "", "Write output to a file instead of stdout")
+	rootCmd.PersistentFlags().StringVar(&flagTheme, "theme", "", "Color theme: "+strings.Join(output.ThemeNames(), ", ")+" (default from the config, else default)")
+	rootCmd.Flags().BoolVar(&flagNoTUI, "no-tui", false, "Print help instead of launching the TUI")
+	rootCmd.Flags().DurationVar(&flagInterval, "interval", tui.DefaultRefreshInterval, "TUI auto-refresh interval (at least 5s)")
+	tuiCmd.Flags().DurationVar(&flagInterval, "interval", tui.DefaultRefreshInterval, "Auto-refresh interval (at least 5s)")
+	rootCmd.Flags().BoolVar(&flagEstimate, "estimate-delays", false, "Carry the last known delay to journey stops without real-time data")
+	tuiCmd.Flags().BoolVar(&flagEstimate, "estimate-delays", false, "Carry the last known delay to journey stops without real-time data")
+
+	// Favorites flags
+	favAddCmd.Flags().BoolVarP(&flagFavForce, "force", "f", false, "Replace a favorite of the same name")
 
 	// Departures-specific flags
 	departuresCmd.Flags().IntVar(&flagNumVias, "vias", 5, "Number of intermediate stops to show")
-	departuresCmd.Flags().StringSliceVarP(&flagModes, "modes", "m", nil, "Filter by transport modes (ICE,EC_IC,REGIONAL,SBAHN,BUS,UBAHN,TRAM)")
+	departuresCmd.Flags().StringSliceVarP(&flagModes, "modes", "m", nil, "Filter by transport modes (ICE,EC_IC,REGIONAL,SBAHN,BUS,UBAHN,TRAM); exclude with all,-BUS")
+	departuresCmd.Flags().BoolVar(&flagAutoModes, "auto-modes", false, "Pick modes by station type (ignored with --modes)")
+	departuresCmd.Flags().BoolVar(&flagDedupe, "dedupe-journeys", false, "Show each departure only once when the board lists it twice")
+	departuresCmd.Flags().BoolVar(&flagHomeMark, "highlight-home", false, "Mark trains that also call at home_eva from the config (extra requests)")
+	addTemplateFlag(departuresCmd, "departure", "{{hhmm .Dep}} {{.Line}}")
+	departuresCmd.Flags().BoolVar(&flagIDsOnly, "journey-id-only", false, "Print only the journey ID of each departure, one per line")
 	departuresCmd.Flags().BoolVarP(&flagShowVia, "via", "v", false, "Show intermediate stops")
+	departuresCmd.Flags().BoolVarP(&flagMessages, "messages", "M", false, "Show disruption messages below each departure")
 	departuresCmd.Flags().StringVarP(&flagLine, "line", "l", "", "Filter by line number (exact match)")
 	departuresCmd.Flags().StringVar(&flagDirection, "direction", "", "Filter by destination (substring match)")
-	departuresCmd.Flags().BoolVarP(&flagWatch, "watch", "w", false, "Watch mode: refresh every 30 seconds")
+	departuresCmd.Flags().BoolVar(&flagDirExact, "direction-exact", false, "Match --direction against the full destination name")
+	departuresCmd.Flags().BoolVar(&flagAccOnly, "accessible-only", false, "Hide trains known not to be wheelchair accessible (unknown ones are kept)")
+	departuresCmd.Flags().BoolVar(&flagAccStrict, "accessible-strict", false, "Show only trains known to be wheelchair accessible (implies --accessible-only)")
+	departuresCmd.Flags().BoolVarP(&flagWatch, "watch", "w", false, "Watch mode: refresh every --interval (default 30s)")
+	departuresCmd.Flags().DurationVar(&flagInterval, "interval", tui.DefaultRefreshInterval, "Refresh interval for --watch (at least 5s)")
 	departuresCmd.Flags().BoolVarP(&flagJourney, "journey", "j", false, "Show journey ID for each departure")
+	departuresCmd.Flags().IntVar(&flagWindow, "window", 0, "Only show departures within the next N minutes")
+	departuresCmd.Flags().IntVar(&flagLead, "lead", 0, "Hide departures leaving sooner than N minutes from now")
+	departuresCmd.Flags().IntVar(&flagLimit, "limit", 0, "Show at most N departures after filtering (0 = all)")
+	departuresCmd.Flags().BoolVar(&flagLegend, "legend", false, "Print a legend explaining delay colors")
+	departuresCmd.Flags().BoolVar(&flagPreferRT, "prefer-rt", false, "Show real-time estimates and delays (default)")
+	departuresCmd.Flags().BoolVar(&flagPrefSched, "prefer-sched", false, "Show scheduled times without delays")
+	departuresCmd.MarkFlagsMutuallyExclusive("prefer-rt", "prefer-sched")
+	departuresCmd.Flags().StringVar(&flagGroupBy, "group-by", "", "Group rows: mode (rail vs. local transit)")
+	departuresCmd.Flags().StringVar(&flagSort, "sort", sortByTime, "Order rows by time (as listed), delay (most delayed first), line or destination")
+	departuresCmd.Flags().StringVar(&flagFormat, "format", "", "Output format: text (default) or gtfs-json (versioned JSON schema for integrations)")
+	departuresCmd.Flags().BoolVar(&flagAggregate, "aggregate", false, "Summarize punctuality (on time, cancelled, delays) per mode instead of listing departures")
+	departuresCmd.Flags().BoolVar(&flagCompact, "compact", false, "Use single spaces between columns")
+	departuresCmd.Flags().BoolVar(&flagSeparator, "separators", false, "Draw │ separators between columns")
+	departuresCmd.Flags().StringVar(&flagDelayStyle, "delay-style", output.DelayNumeric, "Delay column: numeric (+5) or compact (·/↑/↑↑/✕)")
+	departuresCmd.Flags().BoolVar(&flagColsAuto, "columns-auto", false, "Drop platform, delay and via columns to fit the terminal width")
+	departuresCmd.Flags().IntVar(&flagEmptyRetry, "retry-on-empty", 0, "Refetch an empty board up to N times before reporting none")
+	departuresCmd.Flags().Lookup("retry-on-empty").NoOptDefVal = "2"
+	departuresCmd.Flags().DurationVar(&flagRetryDelay, "retry-delay", 2*time.Second, "Wait between --retry-on-empty attempts")
 
 	// Arrivals-specific flags (same as departures)
 	arrivalsCmd.Flags().IntVar(&flagNumVias, "vias", 5, "Number of intermediate stops to show")
-	arrivalsCmd.Flags().StringSliceVarP(&flagModes, "modes", "m", nil, "Filter by transport modes (ICE,EC_IC,REGIONAL,SBAHN,BUS,UBAHN,TRAM)")
+	arrivalsCmd.Flags().StringSliceVarP(&flagModes, "modes", "m", nil, "Filter by transport modes (ICE,EC_IC,REGIONAL,SBAHN,BUS,UBAHN,TRAM); exclude with all,-BUS")
+	arrivalsCmd.Flags().BoolVar(&flagAutoModes, "auto-modes", false, "Pick modes by station type (ignored with --modes)")
+	arrivalsCmd.Flags().BoolVar(&flagDedupe, "dedupe-journeys", false, "Show each arrival only once when the board lists it twice")
+	arrivalsCmd.Flags().BoolVar(&flagHomeMark, "highlight-home", false, "Mark trains that also call at home_eva from the config (extra requests)")
+	addTemplateFlag(arrivalsCmd, "arrival", "{{hhmm .Dep}} {{.Line}}")
+	arrivalsCmd.Flags().BoolVar(&flagIDsOnly, "journey-id-only", false, "Print only the journey ID of each arrival, one per line")
 	arrivalsCmd.Flags().BoolVarP(&flagShowVia, "via", "v", false, "Show intermediate stops")
+	arrivalsCmd.Flags().BoolVarP(&flagMessages, "messages", "M", false, "Show disruption messages below each arrival")
 	arrivalsCmd.Flags().StringVarP(&flagLine, "line", "l", "", "Filter by line number (exact match)")
 	arrivalsCmd.Flags().StringVar(&flagDirection, "direction", "", "Filter by destination (substring match)")
-	arrivalsCmd.Flags().BoolVarP(&flagWatch, "watch", "w", false, "Watch mode: refresh every 30 seconds")
+	arrivalsCmd.Flags().BoolVar(&flagDirExact, "direction-exact", false, "Match --direction against the full destination name")
+	arrivalsCmd.Flags().BoolVar(&flagAccOnly, "accessible-only", false, "Hide trains known not to be wheelchair accessible (unknown ones are kept)")
+	arrivalsCmd.Flags().BoolVar(&flagAccStrict, "accessible-strict", false, "Show only trains known to be wheelchair accessible (implies --accessible-only)")
+	arrivalsCmd.Flags().BoolVarP(&flagWatch, "watch", "w", false, "Watch mode: refresh every --interval (default 30s)")
+	arrivalsCmd.Flags().DurationVar(&flagInterval, "interval", tui.DefaultRefreshInterval, "Refresh interval for --watch (at least 5s)")
 	arrivalsCmd.Flags().BoolVarP(&flagJourney, "journey", "j", false, "Show journey ID for each arrival")
+	arrivalsCmd.Flags().IntVar(&flagWindow, "window", 0, "Only show arrivals within the next N minutes")
+	arrivalsCmd.Flags().IntVar(&flagLimit, "limit", 0, "Show at most N arrivals after filtering (0 = all)")
+	arrivalsCmd.Flags().BoolVar(&flagLegend, "legend", false, "Print a legend explaining delay colors")
+	arrivalsCmd.Flags().BoolVar(&flagPreferRT, "prefer-rt", false, "Show real-time estimates and delays (default)")
+	arrivalsCmd.Flags().BoolVar(&flagPrefSched, "prefer-sched", false, "Show scheduled times without delays")
+	arrivalsCmd.MarkFlagsMutuallyExclusive("prefer-rt", "prefer-sched")
+	arrivalsCmd.Flags().StringVar(&flagGroupBy, "group-by", "", "Group rows: mode (rail vs. local transit)")
+	arrivalsCmd.Flags().StringVar(&flagSort, "sort", sortByTime, "Order rows by time (as listed), delay (most delayed first), line or destination")
+	arrivalsCmd.Flags().BoolVar(&flagCompact, "compact", false, "Use single spaces between columns")
+	arrivalsCmd.Flags().BoolVar(&flagSeparator, "separators", false, "Draw │ separators between columns")
+	arrivalsCmd.Flags().StringVar(&flagDelayStyle, "delay-style", output.DelayNumeric, "Delay column: numeric (+5) or compact (·/↑/↑↑/✕)")
+	arrivalsCmd.Flags().BoolVar(&flagColsAuto, "columns-auto", false, "Drop platform, delay and via columns to fit the terminal width")
+	arrivalsCmd.Flags().IntVar(&flagEmptyRetry, "retry-on-empty", 0, "Refetch an empty board up to N times before reporting none")
+	arrivalsCmd.Flags().Lookup("retry-on-empty").NoOptDefVal = "2"
+	arrivalsCmd.Flags().DurationVar(&flagRetryDelay, "retry-delay", 2*time.Second, "Wait between --retry-on-empty attempts")
+
+	// Search-specific flags
+	searchCmd.Flags().BoolVar(&flagNoRank, "no-rank", false, "Keep the API order instead of listing main stations first")
+	searchCmd.Flags().StringVar(&flagSaveAs, "save-as", "", "Save the best match as favorite @NAME instead of listing results")
+	searchCmd.Flags().BoolVarP(&flagInteractive, "interactive", "i", false, "With --save-as, choose among equally good matches")
+	searchCmd.Flags().BoolVarP(&flagFavForce, "force", "f", false, "With --save-as, replace a favorite of the same name")
+
+	// Nearby-specific flags
+	nearbyCmd.Flags().BoolVar(&flagHere, "here", false, "Use configured home location or approximate IP location")
+	nearbyCmd.Flags().IntVar(&flagRadius, "radius", 0, "Search radius in meters (default 9999)")
+	nearbyCmd.Flags().IntVar(&flagMaxNo, "max", 0, "Maximum number of stations (default 100)")
+	nearbyCmd.Flags().StringSliceVarP(&flagModes, "modes", "m", nil, "Only show stations served by these modes (ICE,EC_IC,REGIONAL,SBAHN,BUS,UBAHN,TRAM)")
+
+	// Formation-specific flags
+	formationCmd.Flags().BoolVar(&flagSVG, "svg", false, "Draw the formation as an SVG image (use with -o)")
+
+	// Connections-specific flags
+	connectionsCmd.Flags().StringSliceVarP(&flagModes, "modes", "m", nil, "Filter by transport modes (ICE,EC_IC,REGIONAL,SBAHN,BUS,UBAHN,TRAM); exclude with all,-BUS")
+	connectionsCmd.Flags().BoolVar(&flagPreferRT, "prefer-rt", false, "Show real-time estimates and delays (default)")
+	connectionsCmd.Flags().BoolVar(&flagPrefSched, "prefer-sched", false, "Show scheduled times without delays")
+	connectionsCmd.MarkFlagsMutuallyExclusive("prefer-rt", "prefer-sched")
+	connectionsCmd.Flags().BoolVar(&flagRepeat, "repeat", false, "Run the last query, or the trip named by the argument, for the current time")
+	connectionsCmd.Flags().StringVar(&flagSaveTrip, "save-trip", "", "Also remember this query under a name for --repeat")
 
 	// Journey-specific flags
-	journeyCmd.Flags().BoolVarP(&flagWatch, "watch", "w", false, "Watch mode: refresh every 30 seconds")
+	journeyCmd.Flags().BoolVarP(&flagWatch, "watch", "w", false, "Watch mode: refresh every --interval (default 30s)")
+	journeyCmd.Flags().DurationVar(&flagInterval, "interval", tui.DefaultRefreshInterval, "Refresh interval for --watch (at least 5s)")
+	journeyCmd.Flags().BoolVar(&flagLegend, "legend", false, "Print a legend explaining delay colors")
+	journeyCmd.Flags().BoolVar(&flagShare, "share", false, "Print a one-paragraph plain-text summary to share")
+	journeyCmd.Flags().BoolVar(&flagStep, "step", false, "Page through the stops one screen at a time")
+	journeyCmd.Flags().Int64SliceVar(&flagConnAt, "connections-at", nil, "List onward departures at these stops (EVA numbers, at most 3)")
+	journeyCmd.Flags().BoolVar(&flagGroupLegs, "group-legs", false, "Split the route into legs where the train or operator changes")
+	journeyCmd.Flags().BoolVar(&flagEstimate, "estimate-delays", false, "Show the last known delay, marked (est.), at later stops without real-time data")
+	journeyCmd.Flags().BoolVar(&flagHideCanc, "hide-cancelled-stops", false, "Collapse cancelled stops into a line counting them")
+	journeyCmd.Flags().BoolVar(&flagTimetable, "timetable", false, "Print a printable timetable with scheduled times only")
+	journeyCmd.Flags().StringVar(&flagFormat, "format", "", "Output format: text (default) or ics")
+	journeyCmd.Flags().BoolVar(&flagPolyline, "polyline", false, "Include the route geometry as coordinates (with --json or --raw-json)")
+	addTemplateFlag(journeyCmd, "stop", "{{hhmm .Arr}} {{.Name}}")
+	journeyCmd.Flags().BoolVar(&flagSummary, "summary", false, "Show the current stop, next stop and ETA above the route")
+	journeyCmd.Flags().BoolVar(&flagPreferRT, "prefer-rt", false, "Show real-time estimates and delays (default)")
+	journeyCmd.Flags().BoolVar(&flagPrefSched, "prefer-sched", false, "Show scheduled times without delays")
+	journeyCmd.MarkFlagsMutuallyExclusive("prefer-rt", "prefer-sched")
 }
 
+// Retries of transient API failures in watch mode
+const (
+	watchRetryAttempts = 3
+	watchRetryDelay    = time.Second
+)
+
+// minRefreshInterval is the shortest --interval accepted for watch mode and
+// the TUI auto-refresh
+const minRefreshInterval = 5 * time.Second
+
+// Size and lifetime of the --memory-cache cache
+const (
+	memoryCacheEntries = 256
+	memoryCacheTTL     = 90 * time.Second
+)
+
 // createClient creates an API client with common options
 func createClient() (*api.Client, error) {
 	opts := []api.ClientOption{}
 
+	if flagMaxAge < 0 {
+		return nil, fmt.Errorf("--max-age must not be negative")
+	}
+
+	if flagInterval < minRefreshInterval {
+		return nil, fmt.Errorf("--interval must be at least %s to avoid hammering the API", minRefreshInterval)
+	}
+
+	if flagOffline && (flagNoCache || flagMemCache || flagMaxAge > 0) {
+		return nil, fmt.Errorf("--offline reads the disk cache and cannot be combined with --no-cache, --memory-cache or --max-age")
+	}
+	if flagOffline {
+		opts = append(opts, api.WithOffline())
+	}
+
+	if flagEmptyRetry < 0 || flagEmptyRetry > api.MaxEmptyRetries {
+		return nil, fmt.Errorf("--retry-on-empty must be between 0 and %d", api.MaxEmptyRetries)
+	}
+	if flagEmptyRetry > 0 {
+		opts = append(opts, api.WithEmptyRetry(flagEmptyRetry, flagRetryDelay))
+	}
+
+	// Watch mode keeps running through the occasional 429 or 503
+	if flagWatch {
+		opts = append(opts, api.WithRetry(watchRetryAttempts, watchRetryDelay))
+	}
+
+	for _, h := range flagHeaders {
+		key, value, err := parseHeader(h)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, api.WithHeader(key, value))
+	}
+
 	// Enable caching unless disabled
 	if !flagNoCache {
-		opts = append(opts, api.WithDefaultCache())
+		if flagMemCache {
+			opts = append(opts, api.WithMemoryCache(memoryCacheEntries, memoryCacheTTL))
+		} else {
+			opts = append(opts, api.WithDefaultCache(), api.WithPersistentCookies(cookiePath()))
+		}
+		if flagMaxAge > 0 {
+			opts = append(opts, api.WithMaxAge(flagMaxAge))
+		}
+	}
+
+	client, err := api.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	openClients = append(openClients, client)
+	if client.TimezoneFallback() {
+		_, _ = fmt.Fprintln(os.Stderr, "Warning: Europe/Berlin timezone data not found, using fixed CET/CEST offset")
+	}
+	return client, nil
+}
+
+// openClients are the clients created by createClient, closed on exit so
+// that their session cookies are saved
+var openClients []*api.Client
+
+// cookiePath is where session cookies are kept between invocations, next to
+// the response cache
+func cookiePath() string {
+	return filepath.Join(cache.DefaultCacheDir(), "cookies.json")
+}
+
+// closeClients closes the clients created during the command. Failing to
+// save cookies only costs the next invocation a fresh session.
+func closeClients() {
+	for _, client := range openClients {
+		if err := client.Close(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 	}
+	openClients = nil
+}
 
-	return api.NewClient(opts...)
+// parseHeader splits a --header value of the form "Name: value"
+func parseHeader(s string) (string, string, error) {
+	key, value, ok := strings.Cut(s, ":")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" || strings.ContainsAny(key, " \t") {
+		return "", "", fmt.Errorf("invalid --header %q: use 'Name: value'", s)
+	}
+	return key, strings.TrimSpace(value), nil
 }
 
-// getColorMode returns the color mode based on flag
+// getColorMode returns the color mode based on the --color flag, --out and
+// the color environment variables
 func getColorMode() output.ColorMode {
-	return output.ParseColorMode(flagColor)
+	mode := output.ParseColorMode(flagColor)
+	// Files never get colors unless explicitly requested
+	if mode == output.ColorAuto && flagOut != "" {
+		return output.ColorNever
+	}
+	// NO_COLOR and FORCE_COLOR only apply when --color is left at auto
+	return output.ColorModeFromEnv(mode)
+}
+
+// newColors returns the color functions for the current color mode and theme
+func newColors() *output.Colors {
+	return output.NewColorsWithTheme(getColorMode(), theme)
+}
+
+// setupRun prepares any command: it picks the theme and opens the --out file
+func setupRun(cmd *cobra.Command, args []string) error {
+	if err := loadTheme(); err != nil {
+		return err
+	}
+	if err := setupClock(); err != nil {
+		return err
+	}
+	return openOutput(cmd, args)
+}
+
+// setupClock pins clock to the --as-of time, read in the API's timezone, so
+// that everything after setupRun sees the same time.
+func setupClock() error {
+	if flagAsOf == "" {
+		return nil
+	}
+	asOf, err := parseAsOf(flagAsOf, api.Timezone(time.Now()))
+	if err != nil {
+		return err
+	}
+	clock = func() time.Time { return asOf }
+	return nil
+}
+
+// loadTheme selects the theme named by --theme, falling back to the config,
+// and applies the marker overrides of the config. It runs before every
+// command, so config problems only print a warning and fall back to the
+// defaults: a broken config must not lock the user out of help or
+// completion. An unknown --theme is still an error.
+func loadTheme() error {
+	cfg, err := config.Load(config.DefaultPath())
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: %v; using the default theme\n", err)
+		cfg = &config.Config{}
+	}
+	t, err := output.LookupTheme(flagTheme)
+	if err != nil {
+		return err
+	}
+	if flagTheme == "" && cfg.Theme != "" {
+		if t, err = output.LookupTheme(cfg.Theme); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: %v in config; using the default theme\n", err)
+			t = output.DefaultTheme
+		}
+	}
+	if m := cfg.Markers; m != nil {
+		marked, err := t.WithMarkers(output.MarkerOverrides{
+			Current:       m.Current,
+			Scroll:        m.Scroll,
+			Bookmark:      m.Bookmark,
+			CurrentColor:  m.CurrentColor,
+			BoardColor:    m.BoardColor,
+			BookmarkColor: m.BookmarkColor,
+		})
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: invalid markers in config: %v; using the theme's markers\n", err)
+		} else {
+			t = marked
+		}
+	}
+	theme = t
+	return nil
+}
+
+// openOutput opens the --out file, creating parent directories as needed.
+// It runs before any command so an unwritable path fails before API calls.
+// Output goes to a temporary file next to it, which closeOutput moves into
+// place, so a failing command leaves an existing file untouched.
+func openOutput(cmd *cobra.Command, args []string) error {
+	if flagOut == "" {
+		return nil
+	}
+	if flagWatch {
+		return fmt.Errorf("--out cannot be combined with --watch")
+	}
+	if !cmd.HasParent() || cmd.Name() == "tui" {
+		return fmt.Errorf("--out is not supported by the TUI")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(flagOut), 0750); err != nil {
+		return fmt.Errorf("cannot create output directory: %w", err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(flagOut), "."+filepath.Base(flagOut)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot open output file: %w", err)
+	}
+	// Temporary files are private; the output is a regular file
+	_ = f.Chmod(0644) // #nosec G302 -- output is meant to be read
+	outFile = f
+	outWriter = f
+	return nil
+}
+
+// closeOutput moves the finished --out file into place, if any, and
+// restores stdout. It only runs after the command succeeded.
+func closeOutput(cmd *cobra.Command, args []string) error {
+	if outFile == nil {
+		return nil
+	}
+	f := outFile
+	outFile = nil
+	outWriter = os.Stdout
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := os.Rename(f.Name(), flagOut); err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// discardOutput drops the temporary --out file of a failed command, if any,
+// and restores stdout
+func discardOutput() {
+	if outFile == nil {
+		return
+	}
+	_ = outFile.Close()
+	_ = os.Remove(outFile.Name())
+	outFile = nil
+	outWriter = os.Stdout
 }
 
 var departuresCmd = &cobra.Command{
@@ -145,6 +698,12 @@ var departuresCmd = &cobra.Command{
 The station must be specified as EVA:ID format, e.g.:
   moko departures 8000105:A=1@O=Frankfurt(Main)Hbf@X=8663003@Y=50107145@U=80@L=8000105@B=1@p=1234567890
 
+A bare EVA number works as well; the station ID is looked up:
+  moko departures 8000105
+
+Coordinates (LAT:LON) are accepted too; the nearest station is used:
+  moko departures 50.107:8.663
+
 Use 'moko search <name>' to find station IDs.
 
 Available transport modes for --modes flag:
@@ -162,22 +721,74 @@ Available transport modes for --modes flag:
 Filtering:
   --line, -l <line>      Filter by line number (exact match, e.g., S1, 623)
   --direction <dest>     Filter by destination (substring match)
+  --window <minutes>     Only show departures within the next N minutes
+  --accessible-only      Hide trains known not to be wheelchair accessible
+  --accessible-strict    Also hide trains that don't report accessibility
+
+Trains reported as step-free are marked ♿ ([wc] with --no-emoji). Many
+trains don't report it at all, so --accessible-only keeps them.
+
+The API always returns a time-bounded board, about an hour at busy
+stations. --window filters that board to the near-term entries after it is
+fetched; it does not make the request smaller, and a window longer than the
+board shows the whole board. --window cannot be combined with --raw-json.
 
 Additional Output:
   --journey, -j          Show journey ID (use with 'moko journey <id>')
   --watch, -w            Refresh every 30 seconds (full-screen mode)
+  --interval <duration>  Refresh interval for --watch, e.g. 15s (at least 5s)
+  --highlight-home       Mark trains that also stop at home_eva from the
+                         config file with [home]. Trains whose via list
+                         doesn't name home cost one journey request each
+                         (at most 10 per refresh), so this is opt-in.
+
+Templates:
+  --template <tmpl>      Print each departure with a Go text/template instead
+                         of the table, one line per departure. Fields: .Line,
+                         .Train, .Type, .Destination, .Origin, .Via, .Platform,
+                         .RTPlatform, .SchedDep, .RTDep, .Dep, .Delay,
+                         .IsCancelled, .JourneyID, .Messages; methods:
+                         .EffectivePlatform, .ArrivalOrigin. Functions: hhmm
+                         (time as 15:04), join, upper, lower.
+
+Punctuality:
+  --aggregate            Print summary stats instead of the departures:
+                         share on time (less than 6 min late), share
+                         cancelled, average and worst delay, in total and
+                         per mode. Stats cover the departures left after
+                         --modes, --line, --direction and --limit, so
+                         '--modes ICE --aggregate' summarizes ICEs only.
+                         Works with --json and --watch.
+
+Integrations:
+  --format gtfs-json     Print the board in a documented, versioned JSON
+                         schema modeled on GTFS-Realtime instead of the
+                         internal layout of --json: {"version":1,"stop":
+                         {"id","name"},"departures":[{"tripId","route",
+                         "routeType","headsign","scheduled","realtime",
+                         "delayMinutes","platform","scheduledPlatform",
+                         "cancelled"}]}. Times are RFC 3339 with offset;
+                         "realtime" is null without a live estimate.
 
 Examples:
   moko departures 8000105:...                    # All departures
   moko departures 8000105:... --modes ICE,EC_IC  # Only long-distance trains
   moko departures 8000105:... --modes SBAHN      # Only S-Bahn
   moko departures 8000105:... --via              # Show intermediate stops
+  moko departures 8000105:... --messages         # Show disruption messages
+  moko departures 8000105:... --limit 5          # Next five trains only
+  moko departures 8000105:... --sort delay       # Most delayed first
+  moko departures 8000105:... --aggregate        # Punctuality summary
+  moko departures 8000105:... --format gtfs-json # Stable JSON for integrations
   moko departures 8000105:... --line S1          # Only S1 line
   moko departures 8000105:... --direction Frankfurt  # Going to Frankfurt
   moko departures 8000105:... -l ICE --direction München
   moko departures 8000105:... --journey          # Show journey IDs
+  moko departures 8000105:... --line S1 --journey-id-only | head -1 | xargs -I{} moko journey {}
   moko departures 8000105:... --watch            # Watch mode with 30s refresh
-  moko departures 8000105:... --line S1 --watch  # Watch only S1 line`,
+  moko departures 8000105:... --line S1 --watch  # Watch only S1 line
+  moko departures 8000105:... --window 20 --watch  # Watch the next 20 minutes
+  moko departures 8000105:... --template '{{hhmm .Dep}} {{.Line}} {{.Destination}}'`,
 	Args: cobra.ExactArgs(1),
 	RunE: runDepartures,
 }
@@ -190,15 +801,25 @@ var arrivalsCmd = &cobra.Command{
 The station must be specified as EVA:ID format, e.g.:
   moko arrivals 8000105:A=1@O=Frankfurt(Main)Hbf@X=8663003@Y=50107145@U=80@L=8000105@B=1@p=1234567890
 
+A bare EVA number works as well; the station ID is looked up:
+  moko arrivals 8000105
+
+Coordinates (LAT:LON) are accepted too; the nearest station is used:
+  moko arrivals 50.107:8.663
+
 Use 'moko search <name>' to find station IDs.
 
 Filtering:
   --line, -l <line>      Filter by line number (exact match, e.g., S1, 623)
   --direction <dest>     Filter by origin (substring match)
+  --window <minutes>     Only show arrivals within the next N minutes
+  --accessible-only      Hide trains known not to be wheelchair accessible
+  --accessible-strict    Also hide trains that don't report accessibility
 
 Additional Output:
   --journey, -j          Show journey ID (use with 'moko journey <id>')
   --watch, -w            Refresh every 30 seconds (full-screen mode)
+  --interval <duration>  Refresh interval for --watch, e.g. 15s (at least 5s)
 
 Examples:
   moko arrivals 8000105:...                    # All arrivals
@@ -215,6 +836,9 @@ var searchCmd = &cobra.Command{
 	Short: "Search for stations by name",
 	Long: `Search for stations by name.
 
+Main stations (Hbf, Hauptbahnhof) and stations with long-distance trains are
+listed first; --no-rank keeps the order returned by the API.
+
 Example:
   moko search "Frankfurt Hbf"
   moko search München`,
@@ -222,17 +846,48 @@ Example:
 	RunE: runSearch,
 }
 
+var stationCmd = &cobra.Command{
+	Use:   "station <station>",
+	Short: "Show details for one station",
+	Long: `Show the details bahn.de lists for a station: name, EVA number, Hafas ID,
+coordinates and the transport products it serves.
+
+The station is given as EVA:ID, a bare EVA number, @favorite or LAT:LON (the
+nearest station). Use it to check an EVA:ID copied from elsewhere, or to get
+coordinates for 'moko nearby'.
+
+Example:
+  moko station 8000105
+  moko station @home --json`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runStation,
+	ValidArgsFunction: completeStation,
+}
+
 var nearbyCmd = &cobra.Command{
-	Use:   "nearby <lat>:<lon>",
+	Use:   "nearby [<lat>:<lon>]",
 	Short: "Search for stations near a location",
 	Long: `Search for stations near a geographic location.
 
-The location must be specified as latitude:longitude in decimal degrees.
+The location must be specified as latitude:longitude in decimal degrees,
+or determined automatically with --here. --here uses the "home" coordinate
+from the config file if set, otherwise an approximate location via IP
+geolocation (city level).
+
+Stations are listed nearest first with their distance. --radius (meters,
+default 9999) and --max (default 100) narrow the search.
+
+--modes keeps only stations serving at least one of the given modes, judged
+by the products the API lists for each station. When it lists none, all
+stations are shown with a warning.
 
 Example:
   moko nearby 50.107:8.663
-  moko nearby 52.520:13.405`,
-	Args: cobra.ExactArgs(1),
+  moko nearby 52.520:13.405
+  moko nearby --here
+  moko nearby --here --modes TRAM
+  moko nearby 50.943:6.959 --radius 500 --max 5`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runNearby,
 }
 
@@ -245,10 +900,53 @@ The journey ID can be obtained from the departures output using --journey or --j
 
 Watch Mode:
   --watch, -w            Refresh every 30 seconds (full-screen mode)
+  --interval <duration>  Refresh interval for --watch, e.g. 15s (at least 5s)
+
+Sharing:
+  --share                Print a plain-text summary (position, delay, next
+                         major stop, arrival) to paste into a message
+  --summary              Show a one-line glance (current stop, next stop, ETA)
+                         above the route
+
+Connections:
+  --connections-at EVA   List the next departures at a stop from the train's
+                         arrival there (repeatable, at most 3 stops)
+
+Legs:
+  --group-legs           Add a sub-header wherever the train number or
+                         operator changes (through services, portions)
+
+Printing:
+  --timetable            Print a color-free timetable (scheduled arrival,
+                         departure and platform per stop) with train, date
+                         and operator, for printing or pasting into a document
+  --format ics           Write an iCalendar event (first departure to last
+                         arrival) to import into a calendar app
+
+Route geometry:
+  --polyline             Also fetch the track geometry; --json adds it as a
+                         "polyline" list of {"lat","lon"} points, --raw-json
+                         keeps the API's polylineGroup
+
+Templates:
+  --template <tmpl>      Print each stop with a Go text/template, e.g.
+                         '{{.Name}} {{hhmm .Arr}}'. Fields: .Name, .EVA,
+                         .Platform, .RTPlatform, .SchedArr, .Arr, .SchedDep,
+                         .Dep, .Delay, .IsCancelled, .Train, .Operator.
+                         Functions: hhmm, join, upper, lower.
+
+Stepping:
+  --step                 Page through the stops one screen at a time (space:
+                         next, b: back, q: quit); prints everything when not
+                         attached to a terminal
 
 Examples:
   moko journey "2|#VN#1#ST#..."
-  moko journey "2|#VN#1#ST#..." --watch    # Track journey in real-time`,
+  moko journey "2|#VN#1#ST#..." --watch    # Track journey in real-time
+  moko journey "2|#VN#1#ST#..." --share    # "ICE 623 to München Hbf is currently at ..."
+  moko journey "2|#VN#1#ST#..." --timetable -o trip.txt
+  moko journey "2|#VN#1#ST#..." --format ics -o trip.ics
+  moko journey "2|#VN#1#ST#..." --json --polyline | jq '.polyline | length'`,
 	Args: cobra.ExactArgs(1),
 	RunE: runJourney,
 }
@@ -261,6 +959,7 @@ var formationCmd = &cobra.Command{
 Example:
   moko formation 8000105 ICE 623
   moko formation 8000105 ICE 623 -d 28.12.2025 -t 12:00
+  moko formation 8000105 ICE 623 --svg -o ice623.svg
 
 The train must depart from the station within the next ~60 minutes,
 or use -d and -t to specify the departure time.`,
@@ -268,6 +967,116 @@ or use -d and -t to specify the departure time.`,
 	RunE: runFormation,
 }
 
+var connectionsCmd = &cobra.Command{
+	Use:   "connections <from> <to> | --repeat [trip]",
+	Short: "Search for connections between two stations",
+	Long: `Search for connections from one station to another.
+
+Stations are given like for departures: EVA:ID, EVA or LAT:LON (the
+nearest station). Each connection is shown with its departure, arrival,
+travel time and number of changes, followed by one line per leg.
+
+Every query is remembered, so --repeat runs the last one again for the
+current time with the same stations, --modes and --prefer-sched. Save
+queries under a name with --save-trip to repeat them later; stations
+given as @favorites are looked up again on every run.
+
+Example:
+  moko connections 8000207 8000105
+  moko connections 8000207 8000105 -d 28.12.2025 -t 12:00
+  moko connections 8000207 8000105 --modes REGIONAL,SBAHN
+  moko connections 8000207 8000105 --json
+  moko connections @home @work --save-trip work
+  moko connections --repeat                       # Same as the last query
+  moko connections --repeat work                  # The trip saved as work`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if flagRepeat {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	RunE: runConnections,
+}
+
+var favCmd = &cobra.Command{
+	Use:   "fav",
+	Short: "Manage favorite stations",
+	Long: `Save stations under a short name and use them as @name wherever a
+station is expected, e.g. 'moko departures @home'. Saved stations are also
+offered in the TUI command palette.
+
+Favorites are stored in favorites.json in the user config directory
+(~/.config/moko on Linux).
+
+Example:
+  moko fav add home 8000207:A=1@O=Köln Hbf@...
+  moko fav add work 8003368      # the station ID is looked up
+  moko fav list
+  moko departures @home
+  moko fav rm work`,
+}
+
+var favAddCmd = &cobra.Command{
+	Use:   "add <name> <eva>:<id>",
+	Short: "Save a station under a name",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runFavAdd,
+}
+
+var favListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List favorite stations",
+	Args:  cobra.NoArgs,
+	RunE:  runFavList,
+}
+
+var favRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove a favorite station",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFavRm,
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check whether the bahn.de API is reachable",
+	Long: `Probe the bahn.de API with a single small station search and report
+whether it answered, the HTTP status and the latency. No transit data is
+shown and the cache is bypassed, so the command suits uptime checks.
+
+The exit status is non-zero when the API does not answer with HTTP 200;
+with --json the result is still printed, including the error.
+
+Example:
+  moko status
+  moko status --json`,
+	Args: cobra.NoArgs,
+	RunE: runStatus,
+	// A failed probe is not a usage error
+	SilenceUsage: true,
+}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for bash, zsh, fish or PowerShell.
+
+Besides commands and flags, the station argument of departures and arrivals
+is completed: favorites for @, otherwise a station search for the typed text
+(cached like other searches; --no-cache skips the cache).
+
+Load it in the current shell, or save it where your shell looks for
+completions:
+  source <(moko completion bash)
+  moko completion zsh > "${fpath[1]}/_moko"
+  moko completion fish > ~/.config/fish/completions/moko.fish
+  moko completion powershell | Out-String | Invoke-Expression`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
 var tuiCmd = &cobra.Command{
 	Use:   "tui",
 	Short: "Launch interactive full-screen TUI",
@@ -280,128 +1089,1181 @@ Keyboard:
   Enter        Select / confirm
   Esc          Go back
   /            Jump to search
+  : or Ctrl+P  Command palette
   q            Quit`,
 	RunE: runTUI,
 }
 
-func runTUI(cmd *cobra.Command, args []string) error {
-	client, err := createClient()
-	if err != nil {
-		return fmt.Errorf("failed to create API client: %w", err)
+func runFavAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := favorites.ValidateName(name); err != nil {
+		return err
 	}
 
-	model := tui.New(client)
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	_, err = p.Run()
-	return err
-}
-
-// filterDepartures filters departures by line and/or direction
-func filterDepartures(deps []models.Departure, line, direction string) []models.Departure {
-	if line == "" && direction == "" {
-		return deps
+	eva, stationID, err := parseStationArg(args[1])
+	if err != nil {
+		return err
 	}
-
-	filtered := make([]models.Departure, 0, len(deps))
-	for _, d := range deps {
-		// Line filter: exact match (case-insensitive)
-		if line != "" && !strings.EqualFold(d.Line, line) {
-			continue
+	if stationID == "" {
+		client, err := createClient()
+		if err != nil {
+			return fmt.Errorf("failed to create API client: %w", err)
 		}
-		// Direction filter: substring match (case-insensitive)
-		if direction != "" && !strings.Contains(strings.ToLower(d.Destination), strings.ToLower(direction)) {
-			continue
+		if stationID, err = lookupStationID(context.Background(), client, eva); err != nil {
+			return err
 		}
-		filtered = append(filtered, d)
 	}
-	return filtered
-}
 
-// runWatch runs a continuous refresh loop for watch mode
-func runWatch(fetchAndRender func() error) error {
-	const refreshInterval = 30 * time.Second
+	return saveFavorite(name, eva, stationID)
+}
 
-	sigChan := output.SetupSignalHandler()
-	ticker := time.NewTicker(refreshInterval)
-	defer ticker.Stop()
+// saveFavorite stores the station as favorite name, replacing an existing
+// one only with --force
+func saveFavorite(name string, eva int64, stationID string) error {
+	path := favorites.DefaultPath()
+	favs, err := favorites.Load(path)
+	if err != nil {
+		return err
+	}
+	if flagFavForce {
+		_ = favs.Remove(name)
+	}
+	if err := favs.Add(name, fmt.Sprintf("%d:%s", eva, stationID)); err != nil {
+		if errors.Is(err, favorites.ErrExists) {
+			return fmt.Errorf("%w (use --force to replace it)", err)
+		}
+		return err
+	}
+	if err := favs.Save(path); err != nil {
+		return fmt.Errorf("failed to save favorites: %w", err)
+	}
 
-	// Hide cursor during watch mode
-	output.HideCursor(os.Stdout)
-	defer output.ShowCursor(os.Stdout)
+	label := stationIDName(stationID)
+	if label == "" {
+		label = strconv.FormatInt(eva, 10)
+	}
+	_, _ = fmt.Fprintf(outWriter, "Saved %s as @%s\n", label, name)
+	return nil
+}
 
-	// Initial render
-	for {
-		output.ClearScreen(os.Stdout)
+func runFavList(cmd *cobra.Command, args []string) error {
+	favs, err := favorites.Load(favorites.DefaultPath())
+	if err != nil {
+		return err
+	}
 
-		// Show header with timestamp
-		now := time.Now()
-		fmt.Printf("Last update: %s | Next refresh in 30s | Press Ctrl+C to exit\n\n",
-			now.Format("15:04:05"))
+	if flagJSON || flagRawJSON {
+		enc := json.NewEncoder(outWriter)
+		enc.SetIndent("", "  ")
+		return enc.Encode(favs.Entries)
+	}
 
-		// Fetch and render data
-		if err := fetchAndRender(); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		}
+	if len(favs.Entries) == 0 {
+		_, _ = fmt.Fprintln(outWriter, "No favorites saved. Add one with 'moko fav add <name> <eva>:<id>'.")
+		return nil
+	}
 
-		// Wait for next tick or interrupt
-		select {
-		case <-ticker.C:
-			continue
-		case <-sigChan:
-			output.ClearScreen(os.Stdout)
-			fmt.Println("Watch mode ended.")
-			return nil
+	colors := newColors()
+	nameWidth := 0
+	for _, fav := range favs.Entries {
+		nameWidth = max(nameWidth, len(fav.Name)+1)
+	}
+	for _, fav := range favs.Entries {
+		eva, stationID, _ := strings.Cut(fav.Station, ":")
+		label := stationIDName(stationID)
+		if label == "" {
+			label = fav.Station
 		}
+		_, _ = fmt.Fprintf(outWriter, "%-*s  %s %s\n", nameWidth, "@"+fav.Name, label, colors.Muted("("+eva+")"))
 	}
+	return nil
 }
 
-func runDepartures(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-
-	// Parse station argument (format: eva:id)
-	parts := strings.SplitN(args[0], ":", 2)
-	if len(parts) != 2 {
-		return fmt.Errorf("station must be in format EVA:ID (e.g., 8000105:A=1@O=...)\nUse 'moko search <name>' to find station IDs")
-	}
-
-	eva, err := strconv.ParseInt(parts[0], 10, 64)
+func runFavRm(cmd *cobra.Command, args []string) error {
+	path := favorites.DefaultPath()
+	favs, err := favorites.Load(path)
 	if err != nil {
-		return fmt.Errorf("invalid EVA number: %w", err)
+		return err
 	}
-	stationID := parts[1]
+	if err := favs.Remove(args[0]); err != nil {
+		return err
+	}
+	if err := favs.Save(path); err != nil {
+		return fmt.Errorf("failed to save favorites: %w", err)
+	}
+	_, _ = fmt.Fprintf(outWriter, "Removed @%s\n", args[0])
+	return nil
+}
 
-	// Create API client
-	client, err := createClient()
+// tuiFavorites loads the saved favorites for the TUI command palette.
+// Unreadable files and entries are skipped with a warning, as the TUI is
+// usable without them.
+func tuiFavorites() []tui.Favorite {
+	favs, err := favorites.Load(favorites.DefaultPath())
 	if err != nil {
-		return fmt.Errorf("failed to create API client: %w", err)
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return nil
+	}
+
+	var result []tui.Favorite
+	for _, fav := range favs.Entries {
+		eva, stationID, err := parseStationArg(fav.Station)
+		if err != nil || stationID == "" {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: skipping favorite @%s: invalid station %q\n", fav.Name, fav.Station)
+			continue
+		}
+		name := stationIDName(stationID)
+		if name == "" {
+			name = fav.Name
+		}
+		result = append(result, tui.Favorite{
+			Name:    fav.Name,
+			Station: models.Location{EVA: eva, ID: stationID, Name: name},
+		})
+	}
+	return result
+}
+
+// statusTimeout bounds the API probe of the status command
+const statusTimeout = 10 * time.Second
+
+// apiStatus is the result of the status command
+type apiStatus struct {
+	Reachable  bool      `json:"reachable"`
+	OK         bool      `json:"ok"`
+	HTTPStatus int       `json:"httpStatus,omitempty"`
+	LatencyMs  int64     `json:"latencyMs"`
+	Endpoint   string    `json:"endpoint"`
+	CheckedAt  time.Time `json:"checkedAt"`
+	Error      string    `json:"error,omitempty"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	if flagOffline {
+		return fmt.Errorf("--offline cannot be combined with status, which always checks the live API")
+	}
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+
+	start := time.Now()
+	code, err := client.Ping(ctx)
+	status := apiStatus{
+		Reachable:  code != 0,
+		OK:         err == nil,
+		HTTPStatus: code,
+		LatencyMs:  time.Since(start).Milliseconds(),
+		Endpoint:   api.BaseURL + api.EndpointLocations,
+		CheckedAt:  start.UTC().Truncate(time.Second),
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	if flagJSON || flagRawJSON {
+		enc := json.NewEncoder(outWriter)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(status); encErr != nil {
+			return encErr
+		}
+	} else {
+		colors := newColors()
+		switch {
+		case status.OK:
+			_, _ = fmt.Fprintf(outWriter, "bahn.de API: %s (HTTP %d, %d ms)\n", colors.OnTime("up"), code, status.LatencyMs)
+		case status.Reachable:
+			_, _ = fmt.Fprintf(outWriter, "bahn.de API: %s (HTTP %d, %d ms)\n", colors.Canceled("error"), code, status.LatencyMs)
+		default:
+			_, _ = fmt.Fprintf(outWriter, "bahn.de API: %s\n", colors.Canceled("unreachable"))
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("API check failed: %w", err)
+	}
+	return nil
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	root := cmd.Root()
+	switch args[0] {
+	case "bash":
+		return root.GenBashCompletionV2(outWriter, true)
+	case "zsh":
+		return root.GenZshCompletion(outWriter)
+	case "fish":
+		return root.GenFishCompletion(outWriter, true)
+	case "powershell":
+		return root.GenPowerShellCompletionWithDesc(outWriter)
+	default:
+		return fmt.Errorf("unsupported shell %q (use bash, zsh, fish or powershell)", args[0])
+	}
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	if !isInteractive() {
+		return fmt.Errorf("the TUI requires an interactive terminal; use a subcommand such as 'moko departures' instead")
+	}
+	if flagAsOf != "" {
+		return fmt.Errorf("--as-of is not supported by the TUI")
+	}
+
+	cfg, err := config.Load(config.DefaultPath())
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	model := tui.New(client).
+		WithRefreshInterval(flagInterval).
+		WithFavorites(tuiFavorites()).
+		WithEstimatedDelays(flagEstimate).
+		WithTheme(theme).
+		WithTerminalTitle(cfg.TerminalTitle)
+	return startTUI(model)
+}
+
+// printNotice tells the user how an argument was resolved.
+func printNotice(msg string) {
+	// Keep stdout clean for JSON, --journey-id-only and --template consumers
+	_, _ = fmt.Fprintln(os.Stderr, msg)
+}
+
+// resolveStationArg parses a station argument given as EVA:ID. A LAT:LON
+// coordinate is accepted as well and resolved to the nearest station, and
+// @name is replaced by the saved favorite.
+func resolveStationArg(ctx context.Context, client *api.Client, arg string) (int64, string, error) {
+	if strings.HasPrefix(strings.TrimSpace(arg), favorites.Prefix) {
+		favs, err := favorites.Load(favorites.DefaultPath())
+		if err != nil {
+			return 0, "", err
+		}
+		if arg, err = favs.Resolve(arg); err != nil {
+			return 0, "", err
+		}
+	}
+
+	if lat, lon, ok := parseCoordinateArg(arg); ok {
+		station, dist, err := nearestStation(ctx, client, lat, lon)
+		if err != nil {
+			return 0, "", err
+		}
+		printNotice(fmt.Sprintf("Nearest station: %s (%d, %.0f m away)", station.Name, station.EVA, dist))
+		return station.EVA, station.ID, nil
+	}
+
+	eva, stationID, err := parseStationArg(arg)
+	if err != nil {
+		return 0, "", err
+	}
+	if stationID == "" {
+		stationID, err = lookupStationID(ctx, client, eva)
+		if err != nil {
+			return 0, "", err
+		}
+	}
+	return eva, stationID, nil
+}
+
+// hafasIDRegex matches Hafas location IDs: key=value pairs joined by "@",
+// e.g. "A=1@O=Frankfurt(Main)Hbf@X=8663003@Y=50107145@U=80@L=8000105@".
+// Values may contain any character but "@", including colons.
+var hafasIDRegex = regexp.MustCompile(`^[A-Za-z]+=[^@]*(?:@[A-Za-z]+=[^@]*)*@?$`)
+
+// hafasEVARegex extracts the EVA number from the L= field of a Hafas ID
+var hafasEVARegex = regexp.MustCompile(`(?:^|@)L=(\d+)(?:@|$)`)
+
+// hafasNameRegex extracts the station name from the O= field of a Hafas ID
+var hafasNameRegex = regexp.MustCompile(`(?:^|@)O=([^@]*)`)
+
+// parseStationArg parses an EVA:ID station argument. Only the first colon
+// separates EVA and ID, so IDs containing colons stay intact. URL-encoded IDs
+// (as copied from bahn.de links) are decoded. A bare EVA yields an empty ID,
+// which the caller looks up.
+func parseStationArg(arg string) (int64, string, error) {
+	evaStr, stationID, _ := strings.Cut(strings.TrimSpace(arg), ":")
+
+	eva, err := strconv.ParseInt(evaStr, 10, 64)
+	if err != nil || eva <= 0 {
+		return 0, "", fmt.Errorf("invalid EVA number %q: station must be in format EVA:ID (e.g., 8000105:A=1@O=...), EVA or LAT:LON\nUse 'moko search <name>' to find station IDs", evaStr)
+	}
+
+	stationID = strings.TrimSpace(stationID)
+	if strings.Contains(stationID, "%") {
+		if decoded, err := url.PathUnescape(stationID); err == nil {
+			stationID = decoded
+		}
+	}
+	if stationID == "" {
+		return eva, "", nil
+	}
+
+	if !hafasIDRegex.MatchString(stationID) {
+		return 0, "", fmt.Errorf("invalid station ID %q: expected a Hafas ID such as A=1@O=...@L=%d@\nUse 'moko search <name>' to find station IDs", stationID, eva)
+	}
+	if m := hafasEVARegex.FindStringSubmatch(stationID); m != nil {
+		if idEVA, err := strconv.ParseInt(m[1], 10, 64); err == nil && idEVA != eva {
+			return 0, "", fmt.Errorf("EVA %d does not match station ID (L=%s)", eva, m[1])
+		}
+	}
+	return eva, stationID, nil
+}
+
+// lookupStationID finds the Hafas ID of a station given only its EVA number
+func lookupStationID(ctx context.Context, client *api.Client, eva int64) (string, error) {
+	locations, err := client.SearchLocations(ctx, strconv.FormatInt(eva, 10))
+	if err != nil {
+		return "", fmt.Errorf("failed to look up station %d: %w", eva, err)
+	}
+	for _, loc := range locations {
+		if loc.EVA == eva && loc.ID != "" {
+			return loc.ID, nil
+		}
+	}
+	return "", fmt.Errorf("no station found for EVA %d\nUse 'moko search <name>' to find station IDs", eva)
+}
+
+// stationAutoModes returns the modes --auto-modes picks for a station from
+// the products it serves, or nil (all modes) when they can't be looked up
+func stationAutoModes(ctx context.Context, client *api.Client, eva int64) []string {
+	if loc := lookupStationByEVA(ctx, client, eva); loc != nil {
+		return loc.AutoModes()
+	}
+	return nil
+}
+
+// completionTimeout bounds the station search behind a completion request so
+// that pressing Tab never hangs the shell
+const completionTimeout = 3 * time.Second
+
+// completeStation completes the station argument of departures and arrivals:
+// favorites for "@..." and otherwise EVA:ID candidates from the station
+// search, with the station name as description
+func completeStation(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if strings.HasPrefix(toComplete, favorites.Prefix) {
+		favs, err := favorites.Load(favorites.DefaultPath())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var candidates []string
+		for _, fav := range favs.Entries {
+			name := stationIDName(fav.Station)
+			if name == "" {
+				name = fav.Station
+			}
+			candidates = append(candidates, favorites.Prefix+fav.Name+"\t"+name)
+		}
+		return candidates, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	query := strings.TrimSpace(toComplete)
+	if query == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	locations, err := client.SearchLocations(ctx, query)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var candidates []string
+	for _, loc := range locations {
+		if loc.EVA == 0 || loc.ID == "" {
+			continue
+		}
+		candidates = append(candidates, fmt.Sprintf("%d:%s\t%s", loc.EVA, loc.ID, loc.Name))
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// lookupStationByEVA searches for a station by its EVA number, returning nil
+// when the lookup fails or finds no exact match
+func lookupStationByEVA(ctx context.Context, client *api.Client, eva int64) *models.Location {
+	loc, err := client.GetStationDetails(ctx, eva)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+// maxHomeJourneyLookups bounds the journey requests --highlight-home makes
+// per board refresh for trains whose via list doesn't mention home
+const maxHomeJourneyLookups = 10
+
+// homeStationEVA returns the home_eva from the config file for --highlight-home
+func homeStationEVA() (int64, error) {
+	cfg, err := config.Load(config.DefaultPath())
+	if err != nil {
+		return 0, err
+	}
+	if cfg.HomeEVA == 0 {
+		return 0, fmt.Errorf("--highlight-home needs \"home_eva\" in %s", config.DefaultPath())
+	}
+	return cfg.HomeEVA, nil
+}
+
+// markCallsAtHome sets CallsAtHome on board entries of trains that also stop
+// at the home station. The via list is checked first since it comes with the
+// board; only trains it doesn't settle cost a journey request each, up to
+// maxHomeJourneyLookups. Boards at the home station itself are left alone.
+func markCallsAtHome(ctx context.Context, client *api.Client, deps []models.Departure, boardEVA, homeEVA int64) {
+	if homeEVA == 0 || homeEVA == boardEVA {
+		return
+	}
+
+	homeName := ""
+	if home := lookupStationByEVA(ctx, client, homeEVA); home != nil {
+		homeName = home.Name
+	}
+
+	lookups := 0
+	for i := range deps {
+		dep := &deps[i]
+		if dep.IsCancelled {
+			continue
+		}
+		if dep.ViaIncludes(homeName) {
+			dep.CallsAtHome = true
+			continue
+		}
+		if dep.JourneyID == "" || lookups >= maxHomeJourneyLookups {
+			continue
+		}
+		lookups++
+		journey, err := client.GetJourney(ctx, dep.JourneyID, false)
+		if err != nil {
+			continue
+		}
+		dep.CallsAtHome = journey.CallsAt(homeEVA)
+	}
+}
+
+// stationIDName returns the station name in a Hafas ID, or "" when the ID
+// has none
+func stationIDName(stationID string) string {
+	if m := hafasNameRegex.FindStringSubmatch(stationID); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// parseCoordinateArg reports whether arg is a LAT:LON pair rather than an
+// EVA:ID station. EVA numbers are far outside the valid latitude range.
+func parseCoordinateArg(arg string) (lat, lon float64, ok bool) {
+	lat, lon, err := parseCoordinates(arg)
+	if err != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// nearestStation returns the station closest to the coordinate along with
+// its distance in meters
+func nearestStation(ctx context.Context, client *api.Client, lat, lon float64) (*models.Location, float64, error) {
+	locations, err := client.SearchNearby(ctx, api.NearbyRequest{Latitude: lat, Longitude: lon})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var nearest *models.Location
+	var nearestDist float64
+	for i := range locations {
+		loc := &locations[i]
+		if loc.EVA == 0 || loc.ID == "" {
+			continue
+		}
+		dist := loc.DistanceTo(lat, lon)
+		if nearest == nil || dist < nearestDist {
+			nearest, nearestDist = loc, dist
+		}
+	}
+	if nearest == nil {
+		return nil, 0, fmt.Errorf("no station found within 10 km of %.5f:%.5f", lat, lon)
+	}
+	return nearest, nearestDist, nil
+}
+
+// parseGroupBy validates the --group-by value, normalizing "none" to ""
+func parseGroupBy(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none":
+		return "", nil
+	case output.GroupByMode:
+		return output.GroupByMode, nil
+	}
+	return "", fmt.Errorf("invalid --group-by %q (valid: mode, none)", s)
+}
+
+// Board sort orders for --sort
+const (
+	sortByTime        = "time"
+	sortByDelay       = "delay"
+	sortByLine        = "line"
+	sortByDestination = "destination"
+)
+
+// parseSort validates --sort
+func parseSort(s string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(s)); v {
+	case "":
+		return sortByTime, nil
+	case sortByTime, sortByDelay, sortByLine, sortByDestination:
+		return v, nil
+	}
+	return "", fmt.Errorf("invalid --sort %q (valid: time, delay, line, destination)", s)
+}
+
+// parseDelayStyle validates --delay-style
+func parseDelayStyle(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", output.DelayNumeric:
+		return output.DelayNumeric, nil
+	case output.DelayCompact:
+		return output.DelayCompact, nil
+	}
+	return "", fmt.Errorf("invalid --delay-style %q (valid: numeric, compact)", s)
+}
+
+// parseModes resolves --modes tokens into the modes to request. Tokens are
+// mode names, "all", or "-MODE" to exclude a mode. Exclusions always win over
+// inclusions regardless of order, and a list of only exclusions starts from
+// all modes. No tokens means the API default (all modes).
+func parseModes(tokens []string) ([]string, error) {
+	include := make(map[string]bool)
+	exclude := make(map[string]bool)
+	all, positive := false, false
+
+	for _, tok := range tokens {
+		tok = strings.ToUpper(strings.TrimSpace(tok))
+		if tok == "" {
+			continue
+		}
+		if tok == "ALL" {
+			all, positive = true, true
+			continue
+		}
+		neg := strings.HasPrefix(tok, "-")
+		mode := strings.TrimPrefix(tok, "-")
+		if !slices.Contains(api.ModesOfTransit, mode) {
+			return nil, fmt.Errorf("unknown mode %q in --modes (valid: all, %s)", mode, strings.Join(api.ModesOfTransit, ", "))
+		}
+		if neg {
+			exclude[mode] = true
+		} else {
+			include[mode] = true
+			positive = true
+		}
+	}
+
+	if len(include) == 0 && len(exclude) == 0 && !all {
+		return nil, nil
+	}
+	if !positive {
+		all = true
+	}
+
+	var modes []string
+	for _, mode := range api.ModesOfTransit {
+		if (all || include[mode]) && !exclude[mode] {
+			modes = append(modes, mode)
+		}
+	}
+	if len(modes) == 0 {
+		return nil, fmt.Errorf("--modes excludes every mode")
+	}
+	return modes, nil
+}
+
+// boardWidth returns the terminal width boards are fitted to with
+// --columns-auto, or 0 (show all columns) when it is unknown, e.g. for piped
+// or file output
+func boardWidth() int {
+	if !flagColsAuto || outWriter != io.Writer(os.Stdout) || !term.IsTerminal(os.Stdout.Fd()) {
+		return 0
+	}
+	width, _, err := term.GetSize(os.Stdout.Fd())
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// parseDualTZ loads the --dual-tz zone, returning nil when the flag is unset
+func parseDualTZ(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --dual-tz %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// asOfLayouts are the accepted --as-of formats besides RFC 3339
+var asOfLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "02.01.2006 15:04"}
+
+// parseAsOf parses the --as-of time in loc. A bare HH:MM means today.
+func parseAsOf(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(loc), nil
+	}
+	for _, layout := range asOfLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("15:04", s, loc); err == nil {
+		now := time.Now().In(loc)
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, loc), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --as-of %q: use HH:MM, YYYY-MM-DD HH:MM or RFC 3339", s)
+}
+
+// addTemplateFlag registers --template on cmd, with --output-template as a
+// hidden alias
+func addTemplateFlag(cmd *cobra.Command, item, example string) {
+	usage := fmt.Sprintf("Print each %s with a Go template, e.g. '%s'", item, example)
+	cmd.Flags().StringVar(&flagTemplate, "template", "", usage)
+	cmd.Flags().StringVar(&flagTemplate, "output-template", "", usage)
+	_ = cmd.Flags().MarkHidden("output-template")
+}
+
+// templateFuncs are the helpers --template offers besides the fields and
+// methods of the printed item
+var templateFuncs = template.FuncMap{
+	// hhmm formats a time as 15:04, "??:??" when it is unknown
+	"hhmm": func(t *time.Time) string {
+		if t == nil {
+			return "??:??"
+		}
+		return t.Format("15:04")
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// parseOutputTemplate parses a --template and tries it on sample, a fully
+// populated item, so that misspelled fields fail before anything is fetched.
+// An empty text yields nil.
+func parseOutputTemplate[T any](text string, sample *T) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("template").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, sample); err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// sampleTime stands in for times when trying out a --template
+var sampleTime = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+// sampleDeparture is a board entry with every time set, to try out templates
+func sampleDeparture() *models.Departure {
+	return &models.Departure{SchedDep: &sampleTime, RTDep: &sampleTime, Dep: &sampleTime}
+}
+
+// sampleStop is a journey stop with every time set, to try out templates
+func sampleStop() *models.Stop {
+	return &models.Stop{
+		SchedArr: &sampleTime, RTArr: &sampleTime, Arr: &sampleTime,
+		SchedDep: &sampleTime, RTDep: &sampleTime, Dep: &sampleTime,
+	}
+}
+
+// renderTemplate prints each item with tmpl, one per line unless the
+// template ends in a newline itself
+func renderTemplate[T any](w io.Writer, tmpl *template.Template, items []T) error {
+	var buf bytes.Buffer
+	for i := range items {
+		buf.Reset()
+		if err := tmpl.Execute(&buf, &items[i]); err != nil {
+			return fmt.Errorf("--template: %w", err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printLegend prints the delay color legend below text output when --legend
+// is set, for delays shown in delayStyle
+func printLegend(colors *output.Colors, delayStyle string) {
+	if !flagLegend {
+		return
+	}
+	if !flagBare {
+		_, _ = fmt.Fprintln(outWriter)
+	}
+	if delayStyle == output.DelayCompact {
+		output.RenderCompactDelayLegend(outWriter, colors, flagNoEmoji)
+		return
+	}
+	output.RenderDelayLegend(outWriter, colors)
+}
+
+// boardWindow returns the --window duration. --raw-json prints the API
+// response as is, so it cannot be narrowed.
+func boardWindow() (time.Duration, error) {
+	window := time.Duration(flagWindow) * time.Minute
+	if window < 0 {
+		return 0, fmt.Errorf("--window must not be negative")
+	}
+	if window > 0 && flagRawJSON {
+		return 0, fmt.Errorf("--window cannot be combined with --raw-json, which prints the API response unfiltered")
+	}
+	return window, nil
+}
+
+// filterDepartures filters departures by line and/or direction. The
+// direction is a substring of the destination, or the whole destination
+// when exact is set.
+func filterDepartures(deps []models.Departure, line, direction string, exact bool) []models.Departure {
+	if line == "" && direction == "" {
+		return deps
+	}
+
+	filtered := make([]models.Departure, 0, len(deps))
+	for _, d := range deps {
+		// Line filter: exact match (case-insensitive)
+		if line != "" && !strings.EqualFold(d.Line, line) {
+			continue
+		}
+		// Direction filter: substring or full match (case-insensitive)
+		if direction != "" {
+			if exact && !strings.EqualFold(d.Destination, direction) {
+				continue
+			}
+			if !exact && !strings.Contains(strings.ToLower(d.Destination), strings.ToLower(direction)) {
+				continue
+			}
+		}
+		filtered = append(filtered, d)
+	}
+	return filtered
+}
+
+// dedupeJourneys drops departures that repeat an earlier entry with the same
+// journey ID and effective time, keeping the first. Matching the time as well
+// keeps distinct services that happen to share an ID. Entries without a
+// journey ID are kept.
+func dedupeJourneys(deps []models.Departure, enabled bool) []models.Departure {
+	if !enabled {
+		return deps
+	}
+
+	type key struct {
+		journeyID string
+		at        time.Time
+	}
+	seen := make(map[key]bool, len(deps))
+	filtered := make([]models.Departure, 0, len(deps))
+	for _, d := range deps {
+		if d.JourneyID != "" {
+			k := key{journeyID: d.JourneyID}
+			if d.Dep != nil {
+				k.at = d.Dep.UTC()
+			}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+		}
+		filtered = append(filtered, d)
+	}
+	return filtered
+}
+
+// sortDepartures sorts departures in place by field. Sorting by time keeps
+// the board's own order; the other fields break ties by departure time, with
+// departures without a time last, and keep the board's order after that.
+func sortDepartures(deps []models.Departure, field string) {
+	byTime := func(a, b models.Departure) bool {
+		if a.Dep == nil || b.Dep == nil {
+			return a.Dep != nil && b.Dep == nil
+		}
+		return a.Dep.Before(*b.Dep)
+	}
+	line := func(d models.Departure) string {
+		if d.Line != "" {
+			return d.Line
+		}
+		return d.TrainShort
+	}
+
+	var less func(a, b models.Departure) bool
+	switch field {
+	case sortByDelay:
+		less = func(a, b models.Departure) bool {
+			if a.Delay != b.Delay {
+				return a.Delay > b.Delay
+			}
+			return byTime(a, b)
+		}
+	case sortByLine:
+		less = func(a, b models.Departure) bool {
+			if c := compareLines(line(a), line(b)); c != 0 {
+				return c < 0
+			}
+			return byTime(a, b)
+		}
+	case sortByDestination:
+		less = func(a, b models.Departure) bool {
+			if c := strings.Compare(strings.ToLower(a.Destination), strings.ToLower(b.Destination)); c != 0 {
+				return c < 0
+			}
+			return byTime(a, b)
+		}
+	default:
+		return
+	}
+	sort.SliceStable(deps, func(i, j int) bool { return less(deps[i], deps[j]) })
+}
+
+// compareLines compares line names case-insensitively, with runs of digits
+// compared by value so that "S 2" comes before "S 11"
+func compareLines(a, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	for a != "" && b != "" {
+		da, db := leadingDigits(a), leadingDigits(b)
+		if da != "" && db != "" {
+			na, nb := strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+			if len(na) != len(nb) {
+				return len(na) - len(nb)
+			}
+			if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+			a, b = a[len(da):], b[len(db):]
+			continue
+		}
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) - len(b)
+}
+
+// leadingDigits returns the ASCII digits at the start of s
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i]
+}
+
+// printJourneyIDs prints the journey ID of each departure on a line of its
+// own, skipping entries without one
+func printJourneyIDs(w io.Writer, deps []models.Departure) error {
+	for _, dep := range deps {
+		if dep.JourneyID == "" {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, dep.JourneyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// limitDepartures returns at most the first n departures; n <= 0 keeps all
+func limitDepartures(deps []models.Departure, n int) []models.Departure {
+	if n <= 0 || n >= len(deps) {
+		return deps
+	}
+	return deps[:n]
+}
+
+// filterAccessible drops trains known not to be wheelchair accessible. Most
+// trains don't report their accessibility, so those are kept unless strict
+// is set; strict implies only.
+func filterAccessible(deps []models.Departure, only, strict bool) []models.Departure {
+	if !only && !strict {
+		return deps
+	}
+
+	filtered := make([]models.Departure, 0, len(deps))
+	for _, d := range deps {
+		if d.Accessible == models.AccessibilityNo {
+			continue
+		}
+		if strict && d.Accessible != models.AccessibilityYes {
+			continue
+		}
+		filtered = append(filtered, d)
+	}
+	return filtered
+}
+
+// filterReachable drops departures whose effective time is earlier than
+// now+lead, i.e. those that cannot be caught. Entries without a time are kept.
+func filterReachable(deps []models.Departure, lead time.Duration, now time.Time) []models.Departure {
+	if lead <= 0 {
+		return deps
+	}
+
+	cutoff := now.Add(lead)
+	filtered := make([]models.Departure, 0, len(deps))
+	for _, d := range deps {
+		if d.Dep != nil && d.Dep.Before(cutoff) {
+			continue
+		}
+		filtered = append(filtered, d)
+	}
+	return filtered
+}
+
+// runWatch runs a continuous refresh loop for watch mode
+func runWatch(fetchAndRender func() error) error {
+	refreshInterval := flagInterval
+
+	sigChan := output.SetupSignalHandler()
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	// Ticks the header countdown between refreshes; it never fetches
+	countdown := time.NewTicker(time.Second)
+	defer countdown.Stop()
+
+	// Hide cursor during watch mode
+	output.HideCursor(os.Stdout)
+	defer output.ShowCursor(os.Stdout)
+
+	nextRefresh := time.Now().Add(refreshInterval)
+
+	// Initial render
+	for {
+		output.ClearScreen(os.Stdout)
+
+		// Show header with timestamp
+		lastUpdate := time.Now()
+		fmt.Printf("%s\n\n", watchHeader(lastUpdate, time.Until(nextRefresh)))
+
+		// Fetch and render data
+		if err := fetchAndRender(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+
+		// Count down until the next tick or interrupt
+	wait:
+		for {
+			select {
+			case t := <-ticker.C:
+				nextRefresh = t.Add(refreshInterval)
+				break wait
+			case <-countdown.C:
+				output.RewriteFirstLine(os.Stdout, watchHeader(lastUpdate, time.Until(nextRefresh)))
+			case <-sigChan:
+				output.ClearScreen(os.Stdout)
+				fmt.Println("Watch mode ended.")
+				return nil
+			}
+		}
+	}
+}
+
+// watchHeader formats the watch mode status line with a countdown to the
+// next refresh in whole seconds
+func watchHeader(lastUpdate time.Time, untilRefresh time.Duration) string {
+	secs := int(untilRefresh.Round(time.Second).Seconds())
+	if secs < 0 {
+		secs = 0
+	}
+	return fmt.Sprintf("Last update: %s | Next refresh in %ds | Press Ctrl+C to exit",
+		lastUpdate.Format("15:04:05"), secs)
+}
+
+// boardTableOptions parses the flags shared by the departure and arrival
+// tables. Colors and Width are set by renderBoard, as watch mode refreshes
+// them on every update.
+func boardTableOptions() (output.TableOptions, error) {
+	groupBy, err := parseGroupBy(flagGroupBy)
+	if err != nil {
+		return output.TableOptions{}, err
+	}
+	delayStyle, err := parseDelayStyle(flagDelayStyle)
+	if err != nil {
+		return output.TableOptions{}, err
+	}
+	dualTZ, err := parseDualTZ(flagDualTZ)
+	if err != nil {
+		return output.TableOptions{}, err
+	}
+	return output.TableOptions{
+		NoDecoration:    flagBare,
+		ShowVia:         flagShowVia,
+		ShowMessages:    flagMessages,
+		ShowRoute:       flagJourney,
+		GroupBy:         groupBy,
+		Compact:         flagCompact,
+		Separators:      flagSeparator,
+		DelayStyle:      delayStyle,
+		NoEmoji:         flagNoEmoji,
+		PreferScheduled: flagPrefSched,
+		DualZone:        dualTZ,
+	}, nil
+}
+
+// renderBoard prints a departure or arrival table sized to the terminal,
+// followed by the legend when asked for
+func renderBoard(deps []models.Departure, opts output.TableOptions, colors *output.Colors) {
+	opts.Colors = colors
+	opts.Width = boardWidth()
+	output.RenderDepartures(outWriter, deps, opts)
+	printLegend(colors, opts.DelayStyle)
+}
+
+func runDepartures(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	tableOpts, err := boardTableOptions()
+	if err != nil {
+		return err
+	}
+
+	sortField, err := parseSort(flagSort)
+	if err != nil {
+		return err
+	}
+
+	modes, err := parseModes(flagModes)
+	if err != nil {
+		return err
+	}
+
+	if flagLead < 0 {
+		return fmt.Errorf("--lead must not be negative")
+	}
+	if flagLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	window, err := boardWindow()
+	if err != nil {
+		return err
+	}
+	lead := time.Duration(flagLead) * time.Minute
+
+	var homeEVA int64
+	if flagHomeMark {
+		if homeEVA, err = homeStationEVA(); err != nil {
+			return err
+		}
+	}
+
+	tmpl, err := parseOutputTemplate(flagTemplate, sampleDeparture())
+	if err != nil {
+		return err
+	}
+	if tmpl != nil && (flagJSON || flagRawJSON) {
+		return fmt.Errorf("--template cannot be combined with --json or --raw-json")
+	}
+	if flagIDsOnly && (flagJSON || flagRawJSON || flagWatch || tmpl != nil) {
+		return fmt.Errorf("--journey-id-only cannot be combined with --json, --raw-json, --watch or --template")
+	}
+	if flagAggregate && (flagRawJSON || flagIDsOnly || tmpl != nil) {
+		return fmt.Errorf("--aggregate cannot be combined with --raw-json, --journey-id-only or --template")
+	}
+	switch flagFormat {
+	case "", "text", "gtfs-json":
+	default:
+		return fmt.Errorf("invalid --format %q (use text or gtfs-json)", flagFormat)
+	}
+	if flagFormat == "gtfs-json" && (flagJSON || flagRawJSON || flagWatch || flagIDsOnly || flagAggregate || tmpl != nil) {
+		return fmt.Errorf("--format gtfs-json cannot be combined with --json, --raw-json, --watch, --journey-id-only, --aggregate or --template")
+	}
+
+	// Create API client
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	// Parse station argument (format: eva:id or lat:lon)
+	eva, stationID, err := resolveStationArg(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+	if flagAutoModes && modes == nil {
+		modes = stationAutoModes(ctx, client, eva)
 	}
 
 	req := api.DepartureRequest{
 		EVA:            eva,
 		StationID:      stationID,
 		NumVias:        flagNumVias,
-		ModesOfTransit: flagModes,
+		ModesOfTransit: modes,
+		Window:         window,
 	}
 
 	// Parse date/time if provided
-	if flagDate != "" || flagTime != "" {
+	if flagDate != "" || flagTime != "" || flagAsOf != "" {
 		req.DateTime = parseDateTime(flagDate, flagTime, client.Timezone())
+		if err := checkStrictTime(req.DateTime, clock()); err != nil {
+			return err
+		}
 	}
 
+	hook := newNotifyHook()
+	defer hook.wait()
+
 	// Watch mode
 	if flagWatch {
 		return runWatch(func() error {
-			colors := output.NewColors(getColorMode())
+			colors := newColors()
 			deps, err := client.GetDepartures(ctx, req)
 			if err != nil {
 				return err
 			}
-			deps = filterDepartures(deps, flagLine, flagDirection)
-			output.RenderDepartures(os.Stdout, deps, output.TableOptions{
-				Colors:    colors,
-				ShowVia:   flagShowVia,
-				ShowRoute: flagJourney,
-			})
+			deps = filterDepartures(deps, flagLine, flagDirection, flagDirExact)
+			deps = filterAccessible(deps, flagAccOnly, flagAccStrict)
+			deps = dedupeJourneys(deps, flagDedupe)
+			deps = filterReachable(deps, lead, clock().In(client.Timezone()))
+			sortDepartures(deps, sortField)
+			deps = limitDepartures(deps, flagLimit)
+			markCallsAtHome(ctx, client, deps, eva, homeEVA)
+			hook.notify("departures", deps)
+			if tmpl != nil {
+				return renderTemplate(outWriter, tmpl, deps)
+			}
+			if flagAggregate {
+				opts := tableOpts
+				opts.Colors = colors
+				output.RenderAggregate(outWriter, output.AggregateDepartures(deps), opts)
+				return nil
+			}
+			renderBoard(deps, tableOpts, colors)
 			return nil
 		})
 	}
@@ -421,23 +2283,55 @@ func runDepartures(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Apply line/direction filters
-	departures = filterDepartures(departures, flagLine, flagDirection)
+	// Apply line/direction, duplicate and lead time filters, then the limit
+	departures = filterDepartures(departures, flagLine, flagDirection, flagDirExact)
+	departures = filterAccessible(departures, flagAccOnly, flagAccStrict)
+	departures = dedupeJourneys(departures, flagDedupe)
+	departures = filterReachable(departures, lead, clock().In(client.Timezone()))
+	sortDepartures(departures, sortField)
+	departures = limitDepartures(departures, flagLimit)
+	markCallsAtHome(ctx, client, departures, eva, homeEVA)
+	hook.notify("departures", departures)
+
+	// Journey IDs only, for piping into other commands
+	if flagIDsOnly {
+		return printJourneyIDs(outWriter, departures)
+	}
+
+	// Versioned JSON schema for integrations
+	if flagFormat == "gtfs-json" {
+		stop := output.GTFSStop{ID: strconv.FormatInt(eva, 10), Name: models.StationNameFromID(stationID)}
+		return output.RenderDeparturesGTFSJSON(outWriter, stop, departures)
+	}
+
+	// Punctuality summary over the filtered board
+	if flagAggregate {
+		agg := output.AggregateDepartures(departures)
+		if flagJSON {
+			enc := json.NewEncoder(outWriter)
+			enc.SetIndent("", "  ")
+			return enc.Encode(agg)
+		}
+		opts := tableOpts
+		opts.Colors = newColors()
+		output.RenderAggregate(outWriter, agg, opts)
+		return nil
+	}
 
 	// JSON output
 	if flagJSON {
-		enc := json.NewEncoder(os.Stdout)
+		enc := json.NewEncoder(outWriter)
 		enc.SetIndent("", "  ")
 		return enc.Encode(departures)
 	}
 
+	// Templated output
+	if tmpl != nil {
+		return renderTemplate(outWriter, tmpl, departures)
+	}
+
 	// Text output with colors
-	colors := output.NewColors(getColorMode())
-	output.RenderDepartures(os.Stdout, departures, output.TableOptions{
-		Colors:    colors,
-		ShowVia:   flagShowVia,
-		ShowRoute: flagJourney,
-	})
+	renderBoard(departures, tableOpts, newColors())
 
 	return nil
 }
@@ -445,17 +2339,47 @@ func runDepartures(cmd *cobra.Command, args []string) error {
 func runArrivals(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	// Parse station argument (format: eva:id)
-	parts := strings.SplitN(args[0], ":", 2)
-	if len(parts) != 2 {
-		return fmt.Errorf("station must be in format EVA:ID (e.g., 8000105:A=1@O=...)\nUse 'moko search <name>' to find station IDs")
+	tableOpts, err := boardTableOptions()
+	if err != nil {
+		return err
 	}
 
-	eva, err := strconv.ParseInt(parts[0], 10, 64)
+	sortField, err := parseSort(flagSort)
 	if err != nil {
-		return fmt.Errorf("invalid EVA number: %w", err)
+		return err
+	}
+
+	modes, err := parseModes(flagModes)
+	if err != nil {
+		return err
+	}
+
+	if flagLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	window, err := boardWindow()
+	if err != nil {
+		return err
+	}
+	tableOpts.Arrivals = true
+
+	var homeEVA int64
+	if flagHomeMark {
+		if homeEVA, err = homeStationEVA(); err != nil {
+			return err
+		}
+	}
+
+	tmpl, err := parseOutputTemplate(flagTemplate, sampleDeparture())
+	if err != nil {
+		return err
+	}
+	if tmpl != nil && (flagJSON || flagRawJSON) {
+		return fmt.Errorf("--template cannot be combined with --json or --raw-json")
+	}
+	if flagIDsOnly && (flagJSON || flagRawJSON || flagWatch || tmpl != nil) {
+		return fmt.Errorf("--journey-id-only cannot be combined with --json, --raw-json, --watch or --template")
 	}
-	stationID := parts[1]
 
 	// Create API client
 	client, err := createClient()
@@ -463,32 +2387,53 @@ func runArrivals(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create API client: %w", err)
 	}
 
+	// Parse station argument (format: eva:id or lat:lon)
+	eva, stationID, err := resolveStationArg(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+	if flagAutoModes && modes == nil {
+		modes = stationAutoModes(ctx, client, eva)
+	}
+
 	req := api.StationBoardRequest{
 		EVA:            eva,
 		StationID:      stationID,
 		NumVias:        flagNumVias,
-		ModesOfTransit: flagModes,
+		ModesOfTransit: modes,
+		Window:         window,
 	}
 
 	// Parse date/time if provided
-	if flagDate != "" || flagTime != "" {
+	if flagDate != "" || flagTime != "" || flagAsOf != "" {
 		req.DateTime = parseDateTime(flagDate, flagTime, client.Timezone())
+		if err := checkStrictTime(req.DateTime, clock()); err != nil {
+			return err
+		}
 	}
 
+	hook := newNotifyHook()
+	defer hook.wait()
+
 	// Watch mode
 	if flagWatch {
 		return runWatch(func() error {
-			colors := output.NewColors(getColorMode())
+			colors := newColors()
 			arrs, err := client.GetArrivals(ctx, req)
 			if err != nil {
 				return err
 			}
-			arrs = filterDepartures(arrs, flagLine, flagDirection)
-			output.RenderDepartures(os.Stdout, arrs, output.TableOptions{
-				Colors:    colors,
-				ShowVia:   flagShowVia,
-				ShowRoute: flagJourney,
-			})
+			arrs = filterDepartures(arrs, flagLine, flagDirection, flagDirExact)
+			arrs = filterAccessible(arrs, flagAccOnly, flagAccStrict)
+			arrs = dedupeJourneys(arrs, flagDedupe)
+			sortDepartures(arrs, sortField)
+			arrs = limitDepartures(arrs, flagLimit)
+			markCallsAtHome(ctx, client, arrs, eva, homeEVA)
+			hook.notify("arrivals", arrs)
+			if tmpl != nil {
+				return renderTemplate(outWriter, tmpl, arrs)
+			}
+			renderBoard(arrs, tableOpts, colors)
 			return nil
 		})
 	}
@@ -508,30 +2453,109 @@ func runArrivals(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Apply line/direction filters
-	arrivals = filterDepartures(arrivals, flagLine, flagDirection)
-
+	// Apply line/direction filters, then the limit
+	arrivals = filterDepartures(arrivals, flagLine, flagDirection, flagDirExact)
+	arrivals = filterAccessible(arrivals, flagAccOnly, flagAccStrict)
+	arrivals = dedupeJourneys(arrivals, flagDedupe)
+	sortDepartures(arrivals, sortField)
+	arrivals = limitDepartures(arrivals, flagLimit)
+	markCallsAtHome(ctx, client, arrivals, eva, homeEVA)
+	hook.notify("arrivals", arrivals)
+
+	// Journey IDs only, for piping into other commands
+	if flagIDsOnly {
+		return printJourneyIDs(outWriter, arrivals)
+	}
+
+	// JSON output
+	if flagJSON {
+		enc := json.NewEncoder(outWriter)
+		enc.SetIndent("", "  ")
+		return enc.Encode(arrivals)
+	}
+
+	// Templated output
+	if tmpl != nil {
+		return renderTemplate(outWriter, tmpl, arrivals)
+	}
+
+	// Text output with colors
+	renderBoard(arrivals, tableOpts, newColors())
+
+	return nil
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	query := args[0]
+
+	if flagSaveAs != "" {
+		if err := favorites.ValidateName(flagSaveAs); err != nil {
+			return err
+		}
+		if flagJSON || flagRawJSON {
+			return fmt.Errorf("--save-as cannot be combined with --json or --raw-json")
+		}
+	} else if flagInteractive {
+		return fmt.Errorf("--interactive requires --save-as")
+	}
+
+	// Create API client
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	// Raw JSON output
+	if flagRawJSON {
+		raw, err := client.SearchLocationsRaw(ctx, query)
+		if err != nil {
+			return err
+		}
+		return printPrettyJSON(raw)
+	}
+
+	// Get locations
+	locations, err := client.SearchLocations(ctx, query)
+	if err != nil {
+		return err
+	}
+	if !flagNoRank {
+		models.RankLocations(locations)
+	}
+
+	// Save the best match as a favorite
+	if flagSaveAs != "" {
+		station, err := pickStation(models.StrongMatches(locations, query), query, flagInteractive, os.Stdin, os.Stderr)
+		if err != nil {
+			return err
+		}
+		return saveFavorite(flagSaveAs, station.EVA, station.ID)
+	}
+
 	// JSON output
 	if flagJSON {
-		enc := json.NewEncoder(os.Stdout)
+		enc := json.NewEncoder(outWriter)
 		enc.SetIndent("", "  ")
-		return enc.Encode(arrivals)
+		return enc.Encode(locations)
 	}
 
 	// Text output with colors
-	colors := output.NewColors(getColorMode())
-	output.RenderDepartures(os.Stdout, arrivals, output.TableOptions{
-		Colors:    colors,
-		ShowVia:   flagShowVia,
-		ShowRoute: flagJourney,
+	colors := newColors()
+	output.RenderLocations(outWriter, locations, output.TableOptions{
+		Colors:       colors,
+		NoDecoration: flagBare,
 	})
 
 	return nil
 }
 
-func runSearch(cmd *cobra.Command, args []string) error {
+func runStation(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
-	query := args[0]
+
+	if flagRawJSON {
+		return fmt.Errorf("--raw-json is not supported by station; use --json")
+	}
 
 	// Create API client
 	client, err := createClient()
@@ -539,53 +2563,92 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create API client: %w", err)
 	}
 
-	// Raw JSON output
-	if flagRawJSON {
-		raw, err := client.SearchLocationsRaw(ctx, query)
-		if err != nil {
-			return err
-		}
-		return printPrettyJSON(raw)
+	eva, _, err := resolveStationArg(ctx, client, args[0])
+	if err != nil {
+		return err
 	}
 
-	// Get locations
-	locations, err := client.SearchLocations(ctx, query)
+	station, err := client.GetStationDetails(ctx, eva)
 	if err != nil {
+		if errors.Is(err, api.ErrNotFound) {
+			return fmt.Errorf("no station found for EVA %d\nUse 'moko search <name>' to find station IDs", eva)
+		}
 		return err
 	}
 
 	// JSON output
 	if flagJSON {
-		enc := json.NewEncoder(os.Stdout)
+		enc := json.NewEncoder(outWriter)
 		enc.SetIndent("", "  ")
-		return enc.Encode(locations)
+		return enc.Encode(station)
 	}
 
 	// Text output with colors
-	colors := output.NewColors(getColorMode())
-	output.RenderLocations(os.Stdout, locations, output.TableOptions{
-		Colors: colors,
+	colors := newColors()
+	output.RenderStation(outWriter, station, output.TableOptions{
+		Colors:       colors,
+		NoDecoration: flagBare,
 	})
 
 	return nil
 }
 
+// pickStation returns the only match of a search, or with interactive set
+// asks on in which of several equally good matches to use
+func pickStation(matches []models.Location, query string, interactive bool, in io.Reader, prompt io.Writer) (*models.Location, error) {
+	switch {
+	case len(matches) == 0:
+		return nil, fmt.Errorf("no station found for %q", query)
+	case len(matches) == 1:
+		return &matches[0], nil
+	}
+
+	if !interactive {
+		names := make([]string, len(matches))
+		for i, m := range matches {
+			names[i] = m.Name
+		}
+		return nil, fmt.Errorf("%d stations match %q equally well: %s\nUse --interactive to choose one, or a more specific query",
+			len(matches), query, strings.Join(names, ", "))
+	}
+
+	for i, m := range matches {
+		_, _ = fmt.Fprintf(prompt, "  %d) %s (%d)\n", i+1, m.Name, m.EVA)
+	}
+	_, _ = fmt.Fprintf(prompt, "Save which station? [1-%d]: ", len(matches))
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("no station chosen")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(matches) {
+		return nil, fmt.Errorf("invalid choice %q: enter a number from 1 to %d", strings.TrimSpace(line), len(matches))
+	}
+	return &matches[n-1], nil
+}
+
 func runNearby(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	// Parse coordinates (format: lat:lon)
-	parts := strings.SplitN(args[0], ":", 2)
-	if len(parts) != 2 {
-		return fmt.Errorf("coordinates must be in format LAT:LON (e.g., 50.107:8.663)")
+	if len(args) == 0 && !flagHere {
+		return fmt.Errorf("coordinates required (LAT:LON), or use --here")
+	}
+	if len(args) > 0 && flagHere {
+		return fmt.Errorf("--here cannot be combined with coordinates")
 	}
 
-	lat, err := strconv.ParseFloat(parts[0], 64)
+	modes, err := parseModes(flagModes)
 	if err != nil {
-		return fmt.Errorf("invalid latitude: %w", err)
+		return err
 	}
-	lon, err := strconv.ParseFloat(parts[1], 64)
-	if err != nil {
-		return fmt.Errorf("invalid longitude: %w", err)
+	if modes != nil && flagRawJSON {
+		return fmt.Errorf("--modes cannot be combined with --raw-json")
+	}
+	if flagRadius < 0 {
+		return fmt.Errorf("--radius must not be negative")
+	}
+	if flagMaxNo < 0 {
+		return fmt.Errorf("--max must not be negative")
 	}
 
 	// Create API client
@@ -594,9 +2657,26 @@ func runNearby(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create API client: %w", err)
 	}
 
+	var lat, lon float64
+	if flagHere {
+		var source string
+		lat, lon, source, err = resolveHere(ctx, client)
+		if err != nil {
+			return err
+		}
+		printNotice(fmt.Sprintf("Using %s (%.4f:%.4f)", source, lat, lon))
+	} else {
+		lat, lon, err = parseCoordinates(args[0])
+		if err != nil {
+			return err
+		}
+	}
+
 	req := api.NearbyRequest{
 		Latitude:  lat,
 		Longitude: lon,
+		Radius:    flagRadius,
+		MaxNo:     flagMaxNo,
 	}
 
 	// Raw JSON output
@@ -614,50 +2694,250 @@ func runNearby(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	models.SortByDistance(locations, lat, lon)
+
+	// Mode filter, from the products each station lists
+	if modes != nil {
+		filtered, ok := models.FilterLocationsByModes(locations, modes)
+		if !ok {
+			_, _ = fmt.Fprintln(os.Stderr, "Warning: no product data for these stations, --modes not applied")
+		}
+		locations = filtered
+	}
+
 	// JSON output
 	if flagJSON {
-		enc := json.NewEncoder(os.Stdout)
+		enc := json.NewEncoder(outWriter)
 		enc.SetIndent("", "  ")
 		return enc.Encode(locations)
 	}
 
 	// Text output with colors
-	colors := output.NewColors(getColorMode())
-	output.RenderLocations(os.Stdout, locations, output.TableOptions{
-		Colors: colors,
+	colors := newColors()
+	output.RenderLocations(outWriter, locations, output.TableOptions{
+		Colors:       colors,
+		NoDecoration: flagBare,
 	})
 
 	return nil
 }
 
+// parseCoordinates parses a LAT:LON pair in decimal degrees
+func parseCoordinates(s string) (lat, lon float64, err error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("coordinates must be in format LAT:LON (e.g., 50.107:8.663)")
+	}
+
+	lat, err = strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid latitude: %w", err)
+	}
+	lon, err = strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid longitude: %w", err)
+	}
+	return lat, lon, nil
+}
+
+// resolveHere determines the current position for `nearby --here`. A home
+// coordinate from the config file takes precedence over IP geolocation.
+func resolveHere(ctx context.Context, client *api.Client) (lat, lon float64, source string, err error) {
+	cfg, err := config.Load(config.DefaultPath())
+	if err != nil {
+		return 0, 0, "", err
+	}
+	if cfg.Home != nil {
+		return cfg.Home.Latitude, cfg.Home.Longitude, "configured home location", nil
+	}
+
+	loc, err := client.LocateByIP(ctx)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("could not determine location (set \"home\" in %s): %w", config.DefaultPath(), err)
+	}
+	source = "approximate location via IP"
+	if loc.City != "" {
+		source += ", " + loc.City
+	}
+	return loc.Latitude, loc.Longitude, source, nil
+}
+
+// defaultNotifyTimeout bounds a notify_cmd hook without notify_timeout
+const defaultNotifyTimeout = 10 * time.Second
+
+// notifyHook runs the notify_cmd from the config file after each fetch
+type notifyHook struct {
+	command string
+	timeout time.Duration
+	running sync.Mutex // held while the command runs
+	done    sync.WaitGroup
+}
+
+// newNotifyHook reads notify_cmd from the config file, once per command so
+// that --watch refreshes don't reread it. Without a hook, or when the config
+// cannot be read, notify does nothing.
+func newNotifyHook() *notifyHook {
+	h := &notifyHook{timeout: defaultNotifyTimeout}
+	cfg, err := config.Load(config.DefaultPath())
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return h
+	}
+	h.command = cfg.NotifyCmd
+	if cfg.NotifyTimeout > 0 {
+		h.timeout = time.Duration(cfg.NotifyTimeout) * time.Second
+	}
+	return h
+}
+
+// notify pipes v as JSON into the hook, with MOKO_EVENT naming what was
+// fetched. The hook runs in the background so a slow one never holds up the
+// output; while the previous run is still going, this one is skipped.
+// Failures are reported on stderr but never fail the command.
+func (h *notifyHook) notify(event string, v any) {
+	if h.command == "" {
+		return
+	}
+	// Encode now, as the caller goes on to use v
+	data, err := json.Marshal(v)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: notify_cmd failed: %v\n", err)
+		return
+	}
+	if !h.running.TryLock() {
+		_, _ = fmt.Fprintln(os.Stderr, "Warning: notify_cmd skipped, the previous run has not finished")
+		return
+	}
+	h.done.Add(1)
+	go func() {
+		defer h.done.Done()
+		defer h.running.Unlock()
+		if err := execNotifyHook(h.command, h.timeout, event, data); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: notify_cmd failed: %v\n", err)
+		}
+	}()
+}
+
+// wait blocks until a running hook has finished, so the command doesn't
+// exit underneath it
+func (h *notifyHook) wait() {
+	h.done.Wait()
+}
+
+// execNotifyHook runs command through the shell with data on stdin. The
+// hook's own output goes to stderr so it can't corrupt --json output.
+func execNotifyHook(command string, timeout time.Duration, event string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var hook *exec.Cmd
+	if runtime.GOOS == "windows" {
+		hook = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		hook = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	hook.Stdin = bytes.NewReader(data)
+	hook.Stdout = os.Stderr
+	hook.Stderr = os.Stderr
+	hook.Env = append(os.Environ(), "MOKO_EVENT="+event)
+	// Don't wait for background children that inherited the pipes
+	hook.WaitDelay = time.Second
+
+	err := hook.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
+
 func runJourney(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	journeyID := args[0]
 
+	if flagShare && (flagJSON || flagRawJSON) {
+		return fmt.Errorf("--share cannot be combined with --json or --raw-json")
+	}
+	if flagStep && (flagJSON || flagRawJSON || flagShare || flagWatch) {
+		return fmt.Errorf("--step cannot be combined with --json, --raw-json, --share or --watch")
+	}
+	if flagTimetable && (flagJSON || flagRawJSON || flagShare || flagStep || flagWatch) {
+		return fmt.Errorf("--timetable cannot be combined with --json, --raw-json, --share, --step or --watch")
+	}
+	switch flagFormat {
+	case "", "text", "ics":
+	default:
+		return fmt.Errorf("invalid --format %q (use text or ics)", flagFormat)
+	}
+	if flagFormat == "ics" && (flagJSON || flagRawJSON || flagShare || flagStep || flagTimetable || flagWatch) {
+		return fmt.Errorf("--format ics cannot be combined with --json, --raw-json, --share, --step, --timetable or --watch")
+	}
+	if flagPolyline && !flagJSON && !flagRawJSON {
+		return fmt.Errorf("--polyline requires --json or --raw-json")
+	}
+	tmpl, err := parseOutputTemplate(flagTemplate, sampleStop())
+	if err != nil {
+		return err
+	}
+	if tmpl != nil && (flagJSON || flagRawJSON || flagShare || flagStep || flagTimetable || flagFormat == "ics") {
+		return fmt.Errorf("--template cannot be combined with --json, --raw-json, --share, --step, --timetable or --format ics")
+	}
+	if len(flagConnAt) > maxConnectionStops {
+		return fmt.Errorf("--connections-at accepts at most %d stops", maxConnectionStops)
+	}
+	if len(flagConnAt) > 0 && (flagJSON || flagRawJSON || flagShare || flagStep || flagTimetable || flagWatch) {
+		return fmt.Errorf("--connections-at cannot be combined with --json, --raw-json, --share, --step, --timetable or --watch")
+	}
+
+	dualTZ, err := parseDualTZ(flagDualTZ)
+	if err != nil {
+		return err
+	}
+
 	// Create API client
 	client, err := createClient()
 	if err != nil {
 		return fmt.Errorf("failed to create API client: %w", err)
 	}
 
+	hook := newNotifyHook()
+	defer hook.wait()
+
 	// Watch mode
 	if flagWatch {
 		return runWatch(func() error {
-			colors := output.NewColors(getColorMode())
+			colors := newColors()
 			j, err := client.GetJourney(ctx, journeyID, false)
 			if err != nil {
 				return err
 			}
-			output.RenderJourney(os.Stdout, j, output.TableOptions{
-				Colors: colors,
+			hook.notify("journey", j)
+			if tmpl != nil {
+				return renderTemplate(outWriter, tmpl, j.Stops)
+			}
+			if flagShare {
+				output.RenderJourneyShare(outWriter, j, clock())
+				return nil
+			}
+			output.RenderJourney(outWriter, j, output.TableOptions{
+				Colors:             colors,
+				NoDecoration:       flagBare,
+				PreferScheduled:    flagPrefSched,
+				Summary:            flagSummary,
+				GroupLegs:          flagGroupLegs,
+				EstimateDelays:     flagEstimate,
+				HideCancelledStops: flagHideCanc,
+				DualZone:           dualTZ,
+				Width:              boardWidth(),
+				Now:                clock,
 			})
+			printLegend(colors, output.DelayNumeric)
 			return nil
 		})
 	}
 
 	// Raw JSON output
 	if flagRawJSON {
-		raw, err := client.GetJourneyRaw(ctx, journeyID, false)
+		raw, err := client.GetJourneyRaw(ctx, journeyID, flagPolyline)
 		if err != nil {
 			return err
 		}
@@ -665,27 +2945,150 @@ func runJourney(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get journey
-	journey, err := client.GetJourney(ctx, journeyID, false)
+	journey, err := client.GetJourney(ctx, journeyID, flagPolyline)
 	if err != nil {
 		return err
 	}
+	hook.notify("journey", journey)
 
 	// JSON output
 	if flagJSON {
-		enc := json.NewEncoder(os.Stdout)
+		enc := json.NewEncoder(outWriter)
 		enc.SetIndent("", "  ")
 		return enc.Encode(journey)
 	}
 
+	// Share summary
+	if flagShare {
+		output.RenderJourneyShare(outWriter, journey, clock())
+		return nil
+	}
+
+	// Printable timetable
+	if flagTimetable {
+		output.RenderJourneyTimetable(outWriter, journey)
+		return nil
+	}
+
+	// Calendar event
+	if flagFormat == "ics" {
+		output.RenderJourneyICS(outWriter, journey)
+		return nil
+	}
+
+	// Templated output
+	if tmpl != nil {
+		return renderTemplate(outWriter, tmpl, journey.Stops)
+	}
+
 	// Text output with colors
-	colors := output.NewColors(getColorMode())
-	output.RenderJourney(os.Stdout, journey, output.TableOptions{
-		Colors: colors,
-	})
+	colors := newColors()
+	opts := output.TableOptions{
+		Colors:             colors,
+		NoDecoration:       flagBare,
+		PreferScheduled:    flagPrefSched,
+		Summary:            flagSummary,
+		GroupLegs:          flagGroupLegs,
+		EstimateDelays:     flagEstimate,
+		HideCancelledStops: flagHideCanc,
+		DualZone:           dualTZ,
+		Now:                clock,
+	}
+
+	// Step through the stops; without a terminal print everything
+	if flagStep && outWriter == io.Writer(os.Stdout) && isInteractive() {
+		return stepJourney(journey, opts)
+	}
+
+	output.RenderJourney(outWriter, journey, opts)
+	if err := printConnections(ctx, client, journey, journeyID, opts); err != nil {
+		return err
+	}
+	printLegend(colors, output.DelayNumeric)
+
+	return nil
+}
+
+const (
+	// maxConnectionStops bounds the board requests --connections-at makes
+	maxConnectionStops = 3
+	// connectionsPerStop is the number of onward departures listed per stop
+	connectionsPerStop = 5
+	// connectionWindow is how far after the arrival connections are looked up
+	connectionWindow = 60 * time.Minute
+)
+
+// printConnections lists onward departures after the journey for each stop
+// given with --connections-at, starting at the train's arrival there. The
+// journey's own departure is left out.
+func printConnections(ctx context.Context, client *api.Client, journey *models.Journey, journeyID string, opts output.TableOptions) error {
+	for _, eva := range flagConnAt {
+		idx := slices.IndexFunc(journey.Stops, func(s models.Stop) bool { return s.EVA == eva })
+		if idx < 0 {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: stop %d is not on this journey\n", eva)
+			continue
+		}
+		stop := journey.Stops[idx]
+		at := stop.Arr
+		if at == nil {
+			at = stop.Dep
+		}
+		if at == nil {
+			continue
+		}
+
+		stationID, err := lookupStationID(ctx, client, eva)
+		if err != nil {
+			return err
+		}
+		deps, err := client.GetDepartures(ctx, api.StationBoardRequest{
+			EVA:       eva,
+			StationID: stationID,
+			DateTime:  *at,
+			Window:    connectionWindow,
+		})
+		if err != nil {
+			return err
+		}
+
+		onward := make([]models.Departure, 0, connectionsPerStop)
+		for _, d := range deps {
+			if d.JourneyID == journeyID || d.Line == journey.Name {
+				continue
+			}
+			onward = append(onward, d)
+			if len(onward) == connectionsPerStop {
+				break
+			}
+		}
 
+		_, _ = fmt.Fprintln(outWriter)
+		_, _ = fmt.Fprintln(outWriter, opts.Colors.Header(fmt.Sprintf("Connections at %s (from %s):", stop.Name, at.Format("15:04"))))
+		output.RenderDepartures(outWriter, onward, opts)
+	}
 	return nil
 }
 
+// stepJourney pages through a rendered journey one screen of stops at a
+// time, reading keys from the terminal in raw mode. The journey header stays
+// on every page.
+func stepJourney(journey *models.Journey, opts output.TableOptions) error {
+	header, rows := output.RenderJourneyLines(journey, opts)
+
+	pageSize := 20
+	if _, height, err := term.GetSize(os.Stdout.Fd()); err == nil && height-len(header)-2 > 0 {
+		pageSize = height - len(header) - 2
+	}
+
+	state, err := term.MakeRaw(os.Stdin.Fd())
+	if err != nil {
+		return fmt.Errorf("failed to read keys from the terminal: %w", err)
+	}
+	defer func() { _ = term.Restore(os.Stdin.Fd(), state) }()
+
+	return output.Page(os.Stdin, os.Stdout, header, rows, pageSize)
+}
+
 func runFormation(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
@@ -697,6 +3100,10 @@ func runFormation(cmd *cobra.Command, args []string) error {
 	trainType := args[1]
 	trainNumber := args[2]
 
+	if flagSVG && (flagJSON || flagRawJSON) {
+		return fmt.Errorf("--svg cannot be combined with --json or --raw-json")
+	}
+
 	// Create API client
 	client, err := createClient()
 	if err != nil {
@@ -710,8 +3117,11 @@ func runFormation(cmd *cobra.Command, args []string) error {
 	}
 
 	// Parse date/time if provided
-	if flagDate != "" || flagTime != "" {
+	if flagDate != "" || flagTime != "" || flagAsOf != "" {
 		req.Departure = parseDateTime(flagDate, flagTime, client.Timezone())
+		if err := checkStrictTime(req.Departure, clock()); err != nil {
+			return err
+		}
 	}
 
 	// Raw JSON output
@@ -731,22 +3141,200 @@ func runFormation(cmd *cobra.Command, args []string) error {
 
 	// JSON output
 	if flagJSON {
-		enc := json.NewEncoder(os.Stdout)
+		enc := json.NewEncoder(outWriter)
 		enc.SetIndent("", "  ")
 		return enc.Encode(formation)
 	}
 
+	if flagSVG {
+		output.RenderFormationSVG(outWriter, formation)
+		return nil
+	}
+
 	// Text output with colors
-	colors := output.NewColors(getColorMode())
-	output.RenderFormation(os.Stdout, formation, output.TableOptions{
-		Colors: colors,
+	colors := newColors()
+	output.RenderFormation(outWriter, formation, output.TableOptions{
+		Colors:       colors,
+		NoDecoration: flagBare,
 	})
 
 	return nil
 }
 
+func runConnections(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if flagSaveTrip != "" {
+		if err := favorites.ValidateTripName(flagSaveTrip); err != nil {
+			return err
+		}
+	}
+
+	var from, to string
+	if flagRepeat {
+		trip, err := loadTrip(cmd, args)
+		if err != nil {
+			return err
+		}
+		from, to = trip.From, trip.To
+	} else {
+		from, to = args[0], args[1]
+	}
+
+	modes, err := parseModes(flagModes)
+	if err != nil {
+		return err
+	}
+
+	dualTZ, err := parseDualTZ(flagDualTZ)
+	if err != nil {
+		return err
+	}
+
+	// Create API client
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	_, fromID, err := resolveStationArg(ctx, client, from)
+	if err != nil {
+		return err
+	}
+	_, toID, err := resolveStationArg(ctx, client, to)
+	if err != nil {
+		return err
+	}
+	trip := favorites.Trip{From: from, To: to, Modes: flagModes, PreferSched: flagPrefSched}
+
+	req := api.ConnectionRequest{
+		FromID:         fromID,
+		ToID:           toID,
+		ModesOfTransit: modes,
+	}
+
+	// Parse date/time if provided
+	if flagDate != "" || flagTime != "" || flagAsOf != "" {
+		req.DateTime = parseDateTime(flagDate, flagTime, client.Timezone())
+		if err := checkStrictTime(req.DateTime, clock()); err != nil {
+			return err
+		}
+	}
+
+	// Raw JSON output
+	if flagRawJSON {
+		raw, err := client.GetConnectionsRaw(ctx, req)
+		if err != nil {
+			return err
+		}
+		rememberTrip(trip, flagSaveTrip)
+		return printPrettyJSON(raw)
+	}
+
+	connections, err := client.GetConnections(ctx, req)
+	if err != nil {
+		return err
+	}
+	rememberTrip(trip, flagSaveTrip)
+
+	// JSON output
+	if flagJSON {
+		enc := json.NewEncoder(outWriter)
+		enc.SetIndent("", "  ")
+		return enc.Encode(connections)
+	}
+
+	output.RenderConnections(outWriter, connections, output.TableOptions{
+		Colors:          newColors(),
+		NoDecoration:    flagBare,
+		PreferScheduled: flagPrefSched,
+		DualZone:        dualTZ,
+	})
+	return nil
+}
+
+// loadTrip returns the trip to run for --repeat: the one named by the
+// argument, else the last query. Its --modes and --prefer-sched apply unless
+// given on the command line.
+func loadTrip(cmd *cobra.Command, args []string) (favorites.Trip, error) {
+	name := favorites.LastTrip
+	if len(args) > 0 {
+		name = args[0]
+	}
+	trips, err := favorites.LoadTrips(favorites.DefaultTripsPath())
+	if err != nil {
+		return favorites.Trip{}, err
+	}
+	trip, err := trips.Get(name)
+	if err != nil {
+		return favorites.Trip{}, err
+	}
+
+	if !cmd.Flags().Changed("modes") {
+		flagModes = trip.Modes
+	}
+	if !cmd.Flags().Changed("prefer-sched") && !cmd.Flags().Changed("prefer-rt") {
+		flagPrefSched = trip.PreferSched
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Repeating %s\n", describeTrip(trip))
+	return trip, nil
+}
+
+// describeTrip returns a one-line summary of a trip, e.g.
+// "trip work: @home → @work (modes REGIONAL,SBAHN)"
+func describeTrip(trip favorites.Trip) string {
+	s := fmt.Sprintf("trip %s: %s → %s", trip.Name, trip.From, trip.To)
+	var prefs []string
+	if len(trip.Modes) > 0 {
+		prefs = append(prefs, "modes "+strings.Join(trip.Modes, ","))
+	}
+	if trip.PreferSched {
+		prefs = append(prefs, "scheduled times")
+	}
+	if len(prefs) > 0 {
+		s += " (" + strings.Join(prefs, ", ") + ")"
+	}
+	return s
+}
+
+// rememberTrip records trip as the last connection query and, with a name,
+// saves it for --repeat. Failures only print a warning, as the query itself
+// succeeded.
+func rememberTrip(trip favorites.Trip, name string) {
+	path := favorites.DefaultTripsPath()
+	trips, err := favorites.LoadTrips(path)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: could not remember this query: %v\n", err)
+		return
+	}
+	trips.Record(trip)
+	if name != "" {
+		if err := trips.Put(name, trip); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: could not save trip: %v\n", err)
+			return
+		}
+	}
+	if err := trips.Save(path); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: could not remember this query: %v\n", err)
+		return
+	}
+	if name != "" {
+		_, _ = fmt.Fprintf(os.Stderr, "Saved trip %s; run it again with 'moko connections --repeat %s'\n", name, name)
+	}
+}
+
+// checkStrictTime rejects a query time before the current minute when
+// --strict-time is set, echoing the parsed time so typos such as a wrong
+// year are easy to spot
+func checkStrictTime(t, now time.Time) error {
+	if !flagStrict || !t.Before(now.Truncate(time.Minute)) {
+		return nil
+	}
+	return fmt.Errorf("requested time %s is in the past (--strict-time)", t.Format("Mon 02.01.2006 15:04"))
+}
+
 func parseDateTime(dateStr, timeStr string, loc *time.Location) time.Time {
-	now := time.Now().In(loc)
+	now := clock().In(loc)
 
 	year := now.Year()
 	month := now.Month()
@@ -803,14 +3391,28 @@ func parseDateTime(dateStr, timeStr string, loc *time.Location) time.Time {
 }
 
 func printPrettyJSON(data []byte) error {
+	return writePrettyJSON(outWriter, data)
+}
+
+// writePrettyJSON re-indents raw JSON without decoding it, so object keys keep
+// the order the API sent them in. Input that cannot be re-indented goes
+// through a decode/encode round trip, or is printed as-is if that fails too.
+func writePrettyJSON(w io.Writer, data []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(data), "", "  "); err == nil {
+		buf.WriteByte('\n')
+		_, err = buf.WriteTo(w)
+		return err
+	}
+
 	var prettyJSON interface{}
 	if err := json.Unmarshal(data, &prettyJSON); err != nil {
 		// If we can't parse it, just print raw
-		fmt.Println(string(data))
+		_, _ = fmt.Fprintln(w, string(data))
 		return err
 	}
 
-	enc := json.NewEncoder(os.Stdout)
+	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(prettyJSON)
 }
@@ -0,0 +1,1239 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mobil-koeln/moko-cli/internal/api"
+	"github.com/mobil-koeln/moko-cli/internal/favorites"
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/output"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+	"github.com/mobil-koeln/moko-cli/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// stubTUI replaces the terminal hooks for the duration of a test and reports
+// whether the TUI program was started.
+func stubTUI(t *testing.T, interactive bool) *bool {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()) // no default_command
+	started := false
+	origInteractive, origStart := isInteractive, startTUI
+	isInteractive = func() bool { return interactive }
+	startTUI = func(tea.Model) error {
+		started = true
+		return nil
+	}
+	t.Cleanup(func() {
+		isInteractive, startTUI = origInteractive, origStart
+		flagNoTUI = false
+		rootCmd.SetArgs(nil)
+		rootCmd.SetOut(nil)
+	})
+	return &started
+}
+
+func TestRoot_TUIGuard(t *testing.T) {
+	tests := []struct {
+		name        string
+		interactive bool
+		args        []string
+		wantTUI     bool
+	}{
+		{"non-tty prints help", false, []string{}, false},
+		{"--no-tui prints help", true, []string{"--no-tui"}, false},
+		{"tty launches tui", true, []string{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := stubTUI(t, tt.interactive)
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs(tt.args)
+
+			err := rootCmd.Execute()
+			testutil.AssertNil(t, err)
+			testutil.AssertEqual(t, *started, tt.wantTUI)
+			if !tt.wantTUI {
+				testutil.AssertContains(t, out.String(), "Usage:")
+			}
+		})
+	}
+}
+
+func TestRoot_DefaultCommand(t *testing.T) {
+	started := stubTUI(t, true)
+	configDir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "moko")
+	testutil.AssertNil(t, os.MkdirAll(configDir, 0750))
+	writeConfig := func(content string) {
+		testutil.AssertNil(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(content), 0600))
+	}
+	var buf bytes.Buffer
+	outWriter = &buf
+	t.Cleanup(func() { outWriter = os.Stdout })
+
+	// The configured command runs instead of the TUI
+	writeConfig(`{"default_command": "fav list"}`)
+	rootCmd.SetArgs([]string{})
+	testutil.AssertNil(t, rootCmd.Execute())
+	testutil.AssertFalse(t, *started)
+	testutil.AssertContains(t, buf.String(), "No favorites saved")
+
+	writeConfig(`{"default_command": "teleport home"}`)
+	err := rootCmd.Execute()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "invalid default_command")
+
+	// Scripts still get help
+	isInteractive = func() bool { return false }
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	testutil.AssertNil(t, rootCmd.Execute())
+	testutil.AssertContains(t, out.String(), "Usage:")
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	words, err := splitCommandLine(`departures  "8000207:A=1@O=Köln Hbf@L=8000207@" --via`)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, len(words), 3)
+	testutil.AssertEqual(t, words[1], "8000207:A=1@O=Köln Hbf@L=8000207@")
+	testutil.AssertEqual(t, words[2], "--via")
+
+	words, err = splitCommandLine(`search ''`)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, len(words), 2)
+
+	_, err = splitCommandLine(`departures "@home`)
+	testutil.AssertError(t, err)
+}
+
+func TestTUICommand_NonInteractive(t *testing.T) {
+	started := stubTUI(t, false)
+
+	err := runTUI(tuiCmd, nil)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "interactive terminal")
+	testutil.AssertFalse(t, *started)
+}
+
+func TestParseCoordinateArg(t *testing.T) {
+	tests := []struct {
+		arg    string
+		wantOK bool
+	}{
+		{"50.107:8.663", true},
+		{"-33.86:151.21", true},
+		{"8000105:A=1@O=Frankfurt(Main)Hbf@", false},
+		{"8000105:123", false}, // EVA is not a valid latitude
+		{"50.107", false},
+		{"abc:def", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			_, _, ok := parseCoordinateArg(tt.arg)
+			testutil.AssertEqual(t, ok, tt.wantOK)
+		})
+	}
+}
+
+func TestParseStationArg(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		wantEVA int64
+		wantID  string
+		wantErr bool
+	}{
+		{
+			name:    "full Hafas ID",
+			arg:     "8000105:A=1@O=Frankfurt(Main)Hbf@X=8663003@Y=50107145@U=80@L=8000105@B=1@p=1734389021@",
+			wantEVA: 8000105,
+			wantID:  "A=1@O=Frankfurt(Main)Hbf@X=8663003@Y=50107145@U=80@L=8000105@B=1@p=1734389021@",
+		},
+		{
+			name:    "colon and umlauts in name",
+			arg:     "8003368:A=1@O=Köln/Bonn Flughafen: Terminal 1@X=7119955@Y=50878811@U=80@L=8003368@",
+			wantEVA: 8003368,
+			wantID:  "A=1@O=Köln/Bonn Flughafen: Terminal 1@X=7119955@Y=50878811@U=80@L=8003368@",
+		},
+		{
+			name:    "transit stop without trailing @",
+			arg:     "900000100003:A=1@O=S+U Alexanderplatz (Berlin)@L=900000100003",
+			wantEVA: 900000100003,
+			wantID:  "A=1@O=S+U Alexanderplatz (Berlin)@L=900000100003",
+		},
+		{
+			name:    "URL-encoded ID",
+			arg:     "8000207:A%3D1%40O%3DK%C3%B6ln%20Hbf%40L%3D8000207%40",
+			wantEVA: 8000207,
+			wantID:  "A=1@O=Köln Hbf@L=8000207@",
+		},
+		{
+			name:    "surrounding whitespace",
+			arg:     "  8000207:A=1@O=Köln Hbf@L=8000207@ ",
+			wantEVA: 8000207,
+			wantID:  "A=1@O=Köln Hbf@L=8000207@",
+		},
+		{name: "bare EVA", arg: "8000207", wantEVA: 8000207},
+		{name: "EVA with empty ID", arg: "8000207:", wantEVA: 8000207},
+		{name: "non-numeric EVA", arg: "Köln:A=1@O=Köln Hbf@", wantErr: true},
+		{name: "negative EVA", arg: "-5:A=1@", wantErr: true},
+		{name: "ID is not a Hafas ID", arg: "8000105:Frankfurt Hbf", wantErr: true},
+		{name: "ID cut at the wrong colon", arg: "8000105:@O=Frankfurt@", wantErr: true},
+		{name: "EVA does not match L=", arg: "8000105:A=1@O=Köln Hbf@L=8000207@", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eva, id, err := parseStationArg(tt.arg)
+			if tt.wantErr {
+				testutil.AssertError(t, err)
+				return
+			}
+			testutil.AssertNil(t, err)
+			testutil.AssertEqual(t, eva, tt.wantEVA)
+			testutil.AssertEqual(t, id, tt.wantID)
+		})
+	}
+}
+
+func TestWritePrettyJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "preserves key order",
+			input: `{"zeit":"12:00","abfahrt":{"z":1,"a":2},"liste":[1,2]}`,
+			want:  "{\n  \"zeit\": \"12:00\",\n  \"abfahrt\": {\n    \"z\": 1,\n    \"a\": 2\n  },\n  \"liste\": [\n    1,\n    2\n  ]\n}\n",
+		},
+		{
+			name:  "keeps escapes as sent",
+			input: `{"name":"K\u00f6ln <Hbf>"}`,
+			want:  "{\n  \"name\": \"K\\u00f6ln <Hbf>\"\n}\n",
+		},
+		{
+			name:    "malformed input printed raw",
+			input:   `{"broken":`,
+			want:    "{\"broken\":\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writePrettyJSON(&buf, []byte(tt.input))
+			if tt.wantErr {
+				testutil.AssertError(t, err)
+			} else {
+				testutil.AssertNil(t, err)
+			}
+			testutil.AssertEqual(t, buf.String(), tt.want)
+		})
+	}
+}
+
+func TestOpenOutput(t *testing.T) {
+	dir := t.TempDir()
+	t.Cleanup(func() { flagOut = "" })
+
+	// Parent directories are created and output lands in the file
+	flagOut = filepath.Join(dir, "boards", "today", "koeln.json")
+	testutil.AssertNil(t, openOutput(searchCmd, nil))
+	testutil.AssertNil(t, printPrettyJSON([]byte(`{"a":1}`)))
+	testutil.AssertNil(t, closeOutput(searchCmd, nil))
+	testutil.AssertTrue(t, outWriter == os.Stdout)
+
+	data, err := os.ReadFile(flagOut)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, string(data), "{\n  \"a\": 1\n}\n")
+
+	// Colors are off for files unless forced
+	testutil.AssertEqual(t, getColorMode(), output.ColorNever)
+
+	// An unwritable path fails before any request is made
+	blocker := filepath.Join(dir, "file")
+	testutil.AssertNil(t, os.WriteFile(blocker, nil, 0600))
+	flagOut = filepath.Join(blocker, "out.txt")
+	testutil.AssertError(t, openOutput(searchCmd, nil))
+	testutil.AssertTrue(t, outFile == nil)
+
+	// The TUI cannot write to a file
+	flagOut = filepath.Join(dir, "tui.txt")
+	testutil.AssertError(t, openOutput(tuiCmd, nil))
+}
+
+func TestOpenOutput_FailedCommandKeepsFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Cleanup(func() { flagOut = "" })
+	flagOut = filepath.Join(dir, "board.txt")
+	testutil.AssertNil(t, os.WriteFile(flagOut, []byte("previous\n"), 0600))
+
+	// Nothing is truncated before the command has output
+	testutil.AssertNil(t, openOutput(searchCmd, nil))
+	data, err := os.ReadFile(flagOut)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, string(data), "previous\n")
+
+	// A failing command discards its partial output
+	_, _ = io.WriteString(outWriter, "partial\n")
+	discardOutput()
+	testutil.AssertTrue(t, outWriter == os.Stdout)
+	data, err = os.ReadFile(flagOut)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, string(data), "previous\n")
+	entries, err := os.ReadDir(dir)
+	testutil.AssertNil(t, err)
+	testutil.AssertLen(t, entries, 1)
+
+	// A successful one replaces the file
+	testutil.AssertNil(t, openOutput(searchCmd, nil))
+	_, _ = io.WriteString(outWriter, "fresh\n")
+	testutil.AssertNil(t, closeOutput(searchCmd, nil))
+	data, err = os.ReadFile(flagOut)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, string(data), "fresh\n")
+}
+
+func TestGetColorMode_Env(t *testing.T) {
+	t.Cleanup(func() { flagColor, flagOut = "auto", "" })
+	t.Setenv("FORCE_COLOR", "")
+	_ = os.Unsetenv("FORCE_COLOR")
+	t.Setenv("CLICOLOR_FORCE", "")
+	_ = os.Unsetenv("CLICOLOR_FORCE")
+
+	flagColor = "auto"
+	t.Setenv("NO_COLOR", "1")
+	testutil.AssertEqual(t, getColorMode(), output.ColorNever)
+
+	// An explicit --color wins over the environment
+	flagColor = "always"
+	testutil.AssertEqual(t, getColorMode(), output.ColorAlways)
+
+	flagColor = "auto"
+	t.Setenv("FORCE_COLOR", "1")
+	testutil.AssertEqual(t, getColorMode(), output.ColorAlways)
+	flagColor = "never"
+	testutil.AssertEqual(t, getColorMode(), output.ColorNever)
+
+	// Files stay plain unless --color always
+	flagColor, flagOut = "auto", "out.txt"
+	testutil.AssertEqual(t, getColorMode(), output.ColorNever)
+}
+
+func TestParseDualTZ(t *testing.T) {
+	loc, err := parseDualTZ("")
+	testutil.AssertNil(t, err)
+	testutil.AssertTrue(t, loc == nil)
+
+	loc, err = parseDualTZ(" Europe/London ")
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, loc.String(), "Europe/London")
+
+	_, err = parseDualTZ("Mars/Olympus")
+	testutil.AssertError(t, err)
+}
+
+func TestParseModes(t *testing.T) {
+	allBut := func(skip ...string) []string {
+		var modes []string
+		for _, m := range api.ModesOfTransit {
+			if !slices.Contains(skip, m) {
+				modes = append(modes, m)
+			}
+		}
+		return modes
+	}
+
+	tests := []struct {
+		name   string
+		tokens []string
+		want   []string
+	}{
+		{"unset", nil, nil},
+		{"positive", []string{"ice", "EC_IC"}, []string{"ICE", "EC_IC"}},
+		{"all minus", []string{"all", "-BUS", "-TRAM"}, allBut("BUS", "TRAM")},
+		{"only negatives", []string{"-BUS"}, allBut("BUS")},
+		{"negative wins", []string{"-ICE", "ICE", "REGIONAL"}, []string{"REGIONAL"}},
+		{"all", []string{"all"}, api.ModesOfTransit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseModes(tt.tokens)
+			testutil.AssertNil(t, err)
+			testutil.AssertEqual(t, strings.Join(got, ","), strings.Join(tt.want, ","))
+		})
+	}
+
+	_, err := parseModes([]string{"all", "-HOVERCRAFT"})
+	testutil.AssertError(t, err)
+	_, err = parseModes([]string{"ICE", "-ICE"})
+	testutil.AssertError(t, err)
+}
+
+func TestWatchHeader(t *testing.T) {
+	last := time.Date(2024, 1, 1, 14, 30, 5, 0, time.UTC)
+
+	testutil.AssertEqual(t, watchHeader(last, 30*time.Second),
+		"Last update: 14:30:05 | Next refresh in 30s | Press Ctrl+C to exit")
+	testutil.AssertContains(t, watchHeader(last, 11600*time.Millisecond), "Next refresh in 12s")
+	testutil.AssertContains(t, watchHeader(last, -time.Second), "Next refresh in 0s")
+}
+
+func TestRunJourney_StepConflicts(t *testing.T) {
+	t.Cleanup(func() { flagStep, flagJSON, flagWatch = false, false, false })
+
+	flagStep, flagJSON = true, true
+	testutil.AssertError(t, runJourney(journeyCmd, []string{"id"}))
+
+	flagJSON, flagWatch = false, true
+	testutil.AssertError(t, runJourney(journeyCmd, []string{"id"}))
+}
+
+func TestRunJourney_FormatConflicts(t *testing.T) {
+	t.Cleanup(func() { flagFormat, flagShare = "", false })
+
+	flagFormat = "pdf"
+	testutil.AssertError(t, runJourney(journeyCmd, []string{"id"}))
+
+	flagFormat, flagShare = "ics", true
+	testutil.AssertError(t, runJourney(journeyCmd, []string{"id"}))
+}
+
+func TestRunJourney_PolylineRequiresJSON(t *testing.T) {
+	t.Cleanup(func() { flagPolyline = false })
+
+	flagPolyline = true
+	err := runJourney(journeyCmd, []string{"id"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--polyline requires --json or --raw-json")
+}
+
+func TestRunNearby_ModesConflicts(t *testing.T) {
+	t.Cleanup(func() { flagModes, flagRawJSON = nil, false })
+
+	flagModes = []string{"HOVERCRAFT"}
+	testutil.AssertError(t, runNearby(nearbyCmd, []string{"50.9:6.9"}))
+
+	flagModes, flagRawJSON = []string{"TRAM"}, true
+	testutil.AssertError(t, runNearby(nearbyCmd, []string{"50.9:6.9"}))
+}
+
+func TestRunNearby_RadiusAndMax(t *testing.T) {
+	t.Cleanup(func() { flagRadius, flagMaxNo = 0, 0 })
+
+	flagRadius = -1
+	testutil.AssertError(t, runNearby(nearbyCmd, []string{"50.9:6.9"}))
+
+	flagRadius, flagMaxNo = 500, -5
+	testutil.AssertError(t, runNearby(nearbyCmd, []string{"50.9:6.9"}))
+}
+
+func TestRunStation_RawJSON(t *testing.T) {
+	t.Cleanup(func() { flagRawJSON = false })
+
+	flagRawJSON = true
+	testutil.AssertError(t, runStation(stationCmd, []string{"8000105"}))
+}
+
+func TestFilterReachable(t *testing.T) {
+	now := time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)
+	at := func(min int) *time.Time {
+		ts := now.Add(time.Duration(min) * time.Minute)
+		return &ts
+	}
+	deps := []models.Departure{
+		{Line: "S 11", Dep: at(2)},
+		{Line: "RE 5", Dep: at(5)},
+		{Line: "RB 25", Dep: at(12)},
+		{Line: "Bus 132"},
+	}
+
+	got := filterReachable(deps, 5*time.Minute, now)
+	testutil.AssertLen(t, got, 3)
+	testutil.AssertEqual(t, got[0].Line, "RE 5")
+	testutil.AssertEqual(t, got[2].Line, "Bus 132")
+
+	testutil.AssertLen(t, filterReachable(deps, 0, now), 4)
+}
+
+func TestFilterAccessible(t *testing.T) {
+	deps := []models.Departure{
+		{Line: "RE 5", Accessible: models.AccessibilityYes},
+		{Line: "RB 25", Accessible: models.AccessibilityNo},
+		{Line: "S 11"},
+	}
+
+	testutil.AssertLen(t, filterAccessible(deps, false, false), 3)
+
+	// Unknown trains are kept unless strict
+	got := filterAccessible(deps, true, false)
+	testutil.AssertLen(t, got, 2)
+	testutil.AssertEqual(t, got[0].Line, "RE 5")
+	testutil.AssertEqual(t, got[1].Line, "S 11")
+
+	// Strict works without --accessible-only
+	got = filterAccessible(deps, false, true)
+	testutil.AssertLen(t, got, 1)
+	testutil.AssertEqual(t, got[0].Line, "RE 5")
+}
+
+func TestAccessibleFlags(t *testing.T) {
+	for _, cmd := range []*cobra.Command{departuresCmd, arrivalsCmd} {
+		t.Run(cmd.Name(), func(t *testing.T) {
+			t.Cleanup(func() {
+				flagAccOnly, flagAccStrict = false, false
+				for _, name := range []string{"accessible-only", "accessible-strict"} {
+					cmd.Flags().Lookup(name).Changed = false
+				}
+			})
+
+			testutil.AssertNil(t, cmd.ParseFlags([]string{"--accessible-only"}))
+			testutil.AssertTrue(t, flagAccOnly)
+			testutil.AssertFalse(t, flagAccStrict)
+
+			testutil.AssertNil(t, cmd.ParseFlags([]string{"--accessible-strict"}))
+			testutil.AssertTrue(t, flagAccStrict)
+		})
+	}
+}
+
+func TestLimitDepartures(t *testing.T) {
+	deps := []models.Departure{{Line: "S 11"}, {Line: "RE 5"}, {Line: "RB 25"}}
+
+	tests := []struct {
+		name string
+		n    int
+		want int
+	}{
+		{"smaller than length", 2, 2},
+		{"larger than length", 10, 3},
+		{"zero is unlimited", 0, 3},
+		{"negative is unlimited", -1, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := limitDepartures(deps, tt.n)
+			testutil.AssertLen(t, got, tt.want)
+			testutil.AssertEqual(t, got[0].Line, "S 11")
+		})
+	}
+
+	testutil.AssertLen(t, limitDepartures(nil, 5), 0)
+}
+
+func TestSortDepartures(t *testing.T) {
+	at := func(min int) *time.Time {
+		ts := time.Date(2024, 1, 1, 14, min, 0, 0, time.UTC)
+		return &ts
+	}
+	board := func() []models.Departure {
+		return []models.Departure{
+			{JourneyID: "a", Line: "S 11", Destination: "Düsseldorf", Dep: at(5), Delay: 2},
+			{JourneyID: "b", Line: "RE 5", Destination: "koblenz Hbf", Dep: at(1), Delay: 12},
+			{JourneyID: "c", Line: "S 2", Destination: "Bonn", Dep: nil, Delay: 2},
+			{JourneyID: "d", TrainShort: "ICE", Destination: "Berlin", Dep: at(3)},
+			{JourneyID: "e", Line: "S 11", Destination: "Bonn", Dep: at(2), Delay: 2},
+		}
+	}
+	ids := func(deps []models.Departure) string {
+		var b strings.Builder
+		for _, d := range deps {
+			b.WriteString(d.JourneyID)
+		}
+		return b.String()
+	}
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		// The board's order is kept
+		{sortByTime, "abcde"},
+		// Ties on delay go by time, the missing time last
+		{sortByDelay, "beacd"},
+		// Numbers within lines compare by value; ties go by time
+		{sortByLine, "dbcea"},
+		// Case-insensitive; "Bonn" tie goes by time with the missing time last
+		{sortByDestination, "decab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			deps := board()
+			sortDepartures(deps, tt.field)
+			testutil.AssertEqual(t, ids(deps), tt.want)
+		})
+	}
+
+	// Equal keys and times keep the board's order
+	deps := []models.Departure{
+		{JourneyID: "x", Line: "S 1", Dep: at(0)},
+		{JourneyID: "y", Line: "S 1", Dep: at(0)},
+		{JourneyID: "z", Line: "S 1"},
+		{JourneyID: "w", Line: "S 1"},
+	}
+	sortDepartures(deps, sortByLine)
+	testutil.AssertEqual(t, ids(deps), "xyzw")
+}
+
+func TestParseSort(t *testing.T) {
+	for in, want := range map[string]string{"": sortByTime, "Delay": sortByDelay, " line ": sortByLine, "destination": sortByDestination} {
+		got, err := parseSort(in)
+		testutil.AssertNil(t, err)
+		testutil.AssertEqual(t, got, want)
+	}
+	_, err := parseSort("platform")
+	testutil.AssertError(t, err)
+}
+
+func TestCompareLines(t *testing.T) {
+	testutil.AssertTrue(t, compareLines("S 2", "S 11") < 0)
+	testutil.AssertTrue(t, compareLines("s 11", "S 11") == 0)
+	testutil.AssertTrue(t, compareLines("RE 5", "S 1") < 0)
+	testutil.AssertTrue(t, compareLines("ICE 100", "ICE 99") > 0)
+	testutil.AssertTrue(t, compareLines("S 1", "S 1X") < 0)
+	testutil.AssertTrue(t, compareLines("Bus 007", "Bus 7") == 0)
+}
+
+func TestDedupeJourneys(t *testing.T) {
+	now := time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)
+	at := func(min int) *time.Time {
+		ts := now.Add(time.Duration(min) * time.Minute)
+		return &ts
+	}
+	deps := []models.Departure{
+		{JourneyID: "a", Line: "S 11", Dep: at(2), Destination: "Bergisch Gladbach"},
+		{JourneyID: "a", Line: "S 11", Dep: at(2), Destination: "Bergisch Gladbach (via Mülheim)"},
+		{JourneyID: "a", Line: "S 11", Dep: at(22)},
+		{JourneyID: "b", Line: "RE 5", Dep: at(5)},
+		{Line: "Bus 132"},
+		{Line: "Bus 132"},
+	}
+
+	got := dedupeJourneys(deps, true)
+	testutil.AssertLen(t, got, 5)
+	testutil.AssertEqual(t, got[0].Destination, "Bergisch Gladbach")
+	testutil.AssertEqual(t, got[1].Dep.Minute(), 22)
+
+	testutil.AssertLen(t, dedupeJourneys(deps, false), 6)
+}
+
+func TestParseAsOf(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+
+	got, err := parseAsOf("2024-03-01 14:30", loc)
+	testutil.AssertNil(t, err)
+	testutil.AssertTrue(t, got.Equal(time.Date(2024, 3, 1, 14, 30, 0, 0, loc)))
+
+	got, err = parseAsOf("01.03.2024 14:30", loc)
+	testutil.AssertNil(t, err)
+	testutil.AssertTrue(t, got.Equal(time.Date(2024, 3, 1, 14, 30, 0, 0, loc)))
+
+	got, err = parseAsOf("2024-03-01T13:30:00Z", loc)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, got.Format("15:04"), "14:30")
+
+	got, err = parseAsOf("07:05", loc)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, got.Format("15:04"), "07:05")
+	testutil.AssertEqual(t, got.Format("2006-01-02"), time.Now().In(loc).Format("2006-01-02"))
+
+	_, err = parseAsOf("yesterday", loc)
+	testutil.AssertError(t, err)
+}
+
+func TestPrintNotice(t *testing.T) {
+	var buf bytes.Buffer
+	outWriter = &buf
+	t.Cleanup(func() { outWriter = os.Stdout })
+	oldStderr := os.Stderr
+	t.Cleanup(func() {
+		os.Stderr = oldStderr
+		flagJSON, flagIDsOnly, flagTemplate = false, false, ""
+	})
+
+	tests := []struct {
+		name  string
+		setup func()
+	}{
+		{"text", func() {}},
+		{"json", func() { flagJSON = true }},
+		{"ids only", func() { flagIDsOnly = true }},
+		{"template", func() { flagTemplate = "{{.Line}}" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flagJSON, flagIDsOnly, flagTemplate = false, false, ""
+			tt.setup()
+			r, w, err := os.Pipe()
+			testutil.AssertNil(t, err)
+			os.Stderr = w
+			buf.Reset()
+
+			printNotice("Using 50% of the map")
+			testutil.AssertNil(t, w.Close())
+			os.Stderr = oldStderr
+			stderr, err := io.ReadAll(r)
+			testutil.AssertNil(t, err)
+
+			// Piped output starts with the first result, not the notice
+			testutil.AssertEqual(t, buf.String(), "")
+			testutil.AssertEqual(t, string(stderr), "Using 50% of the map\n")
+		})
+	}
+}
+
+func TestSetupClock(t *testing.T) {
+	oldAsOf, oldClock := flagAsOf, clock
+	defer func() { flagAsOf, clock = oldAsOf, oldClock }()
+
+	flagAsOf = ""
+	testutil.AssertNil(t, setupClock())
+	testutil.AssertTrue(t, time.Since(clock()) < time.Minute)
+
+	flagAsOf = "2024-03-01 14:30"
+	testutil.AssertNil(t, setupClock())
+	testutil.AssertEqual(t, clock().UTC().Format("2006-01-02 15:04"), "2024-03-01 13:30")
+
+	flagAsOf = "yesterday"
+	testutil.AssertError(t, setupClock())
+}
+
+func TestFilterDepartures_Direction(t *testing.T) {
+	deps := []models.Departure{
+		{Line: "ICE 27", Destination: "Frankfurt(Main)Hbf"},
+		{Line: "RE 1", Destination: "Frankfurt(Oder)"},
+		{Line: "S 11", Destination: "Bergisch Gladbach"},
+	}
+
+	testutil.AssertLen(t, filterDepartures(deps, "", "frankfurt", false), 2)
+	testutil.AssertLen(t, filterDepartures(deps, "", "frankfurt", true), 0)
+
+	got := filterDepartures(deps, "", "frankfurt(oder)", true)
+	testutil.AssertLen(t, got, 1)
+	testutil.AssertEqual(t, got[0].Line, "RE 1")
+
+	testutil.AssertLen(t, filterDepartures(deps, "", "", true), 3)
+}
+
+func TestParseHeader(t *testing.T) {
+	key, value, err := parseHeader("Referer: https://www.bahn.de/")
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, key, "Referer")
+	testutil.AssertEqual(t, value, "https://www.bahn.de/")
+
+	key, value, err = parseHeader("Origin:")
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, key, "Origin")
+	testutil.AssertEqual(t, value, "")
+
+	for _, bad := range []string{"Referer", ": x", "Bad Name: x"} {
+		_, _, err = parseHeader(bad)
+		testutil.AssertError(t, err)
+	}
+}
+
+func TestRunJourney_ConnectionsAtLimits(t *testing.T) {
+	t.Cleanup(func() { flagConnAt, flagJSON = nil, false })
+
+	flagConnAt = []int64{8000207, 8000044, 8000105, 8000244}
+	testutil.AssertError(t, runJourney(journeyCmd, []string{"id"}))
+
+	flagConnAt, flagJSON = []int64{8000207}, true
+	testutil.AssertError(t, runJourney(journeyCmd, []string{"id"}))
+}
+
+func TestCheckStrictTime(t *testing.T) {
+	t.Cleanup(func() { flagStrict = false })
+	now := time.Date(2025, 3, 1, 14, 30, 45, 0, time.UTC)
+	past := time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC)
+
+	// Past queries are allowed by default
+	testutil.AssertNil(t, checkStrictTime(past, now))
+
+	flagStrict = true
+	err := checkStrictTime(past, now)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "Fri 01.03.2024 14:30")
+
+	// The current minute and later pass
+	testutil.AssertNil(t, checkStrictTime(time.Date(2025, 3, 1, 14, 30, 0, 0, time.UTC), now))
+	testutil.AssertNil(t, checkStrictTime(now.Add(time.Hour), now))
+}
+
+func TestParseDelayStyle(t *testing.T) {
+	for in, want := range map[string]string{"": "numeric", "numeric": "numeric", "Compact": "compact"} {
+		got, err := parseDelayStyle(in)
+		testutil.AssertNil(t, err)
+		testutil.AssertEqual(t, got, want)
+	}
+	_, err := parseDelayStyle("emoji")
+	testutil.AssertError(t, err)
+}
+
+func TestRunConnections_InvalidArgs(t *testing.T) {
+	t.Cleanup(func() { flagModes = nil })
+
+	err := runConnections(connectionsCmd, []string{"koeln", "8000105"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "invalid EVA number")
+
+	flagModes = []string{"HOVERCRAFT"}
+	testutil.AssertError(t, runConnections(connectionsCmd, []string{"8000207", "8000105"}))
+}
+
+func TestConnectionsTrips(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Cleanup(func() { flagModes, flagPrefSched = nil, false })
+
+	// Nothing to repeat yet
+	_, err := loadTrip(connectionsCmd, nil)
+	testutil.AssertError(t, err)
+
+	rememberTrip(favorites.Trip{From: "@home", To: "@work", Modes: []string{"REGIONAL", "SBAHN"}}, "work")
+	rememberTrip(favorites.Trip{From: "8000207", To: "8000105", PreferSched: true}, "")
+
+	trip, err := loadTrip(connectionsCmd, nil)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, trip.From, "8000207")
+	testutil.AssertTrue(t, flagPrefSched)
+
+	// The named trip brings back its preferences
+	trip, err = loadTrip(connectionsCmd, []string{"work"})
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, trip.To, "@work")
+	testutil.AssertEqual(t, strings.Join(flagModes, ","), "REGIONAL,SBAHN")
+	testutil.AssertFalse(t, flagPrefSched)
+	testutil.AssertEqual(t, describeTrip(trip), "trip work: @home → @work (modes REGIONAL,SBAHN)")
+
+	_, err = loadTrip(connectionsCmd, []string{"gym"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "saved: work")
+}
+
+func TestConnectionsCmd_Args(t *testing.T) {
+	t.Cleanup(func() { flagRepeat = false })
+
+	testutil.AssertError(t, connectionsCmd.Args(connectionsCmd, []string{"8000207"}))
+	testutil.AssertNil(t, connectionsCmd.Args(connectionsCmd, []string{"8000207", "8000105"}))
+
+	flagRepeat = true
+	testutil.AssertNil(t, connectionsCmd.Args(connectionsCmd, nil))
+	testutil.AssertNil(t, connectionsCmd.Args(connectionsCmd, []string{"work"}))
+	testutil.AssertError(t, connectionsCmd.Args(connectionsCmd, []string{"8000207", "8000105"}))
+}
+
+func TestExecNotifyHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	out := filepath.Join(t.TempDir(), "hook.json")
+
+	data, err := json.Marshal([]models.Departure{{Line: "S 12", Destination: "Köln Hbf"}})
+	testutil.AssertNil(t, err)
+	err = execNotifyHook(`printf '%s ' "$MOKO_EVENT" > `+out+` && cat >> `+out, 5*time.Second, "departures", data)
+	testutil.AssertNil(t, err)
+
+	data, err = os.ReadFile(out)
+	testutil.AssertNil(t, err)
+	testutil.AssertContains(t, string(data), `departures [{"journeyId":"","type":"","line":"S 12"`)
+
+	// Failing and slow hooks are reported
+	testutil.AssertError(t, execNotifyHook("exit 3", 5*time.Second, "journey", nil))
+	err = execNotifyHook("sleep 5", 50*time.Millisecond, "journey", nil)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "timed out")
+}
+
+func TestNotifyHook_Background(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	out := filepath.Join(t.TempDir(), "hook.txt")
+	hook := &notifyHook{command: `sleep 0.3; printf '%s\n' "$MOKO_EVENT" >> ` + out, timeout: 5 * time.Second}
+
+	// A slow hook does not hold up the command
+	start := time.Now()
+	hook.notify("departures", nil)
+	testutil.AssertTrue(t, time.Since(start) < 200*time.Millisecond)
+
+	// A refresh while it still runs skips the hook instead of piling up
+	hook.notify("arrivals", nil)
+	hook.wait()
+
+	data, err := os.ReadFile(out)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, string(data), "departures\n")
+
+	// Without a notify_cmd nothing runs
+	(&notifyHook{}).notify("journey", nil)
+}
+
+func TestCreateClient_Interval(t *testing.T) {
+	t.Cleanup(func() { flagInterval = tui.DefaultRefreshInterval })
+
+	flagInterval = 2 * time.Second
+	_, err := createClient()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--interval must be at least 5s")
+
+	flagInterval = 15 * time.Second
+	_, err = createClient()
+	testutil.AssertNil(t, err)
+}
+
+func TestCreateClient_Offline(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Cleanup(func() { flagOffline, flagMemCache, flagJSON = false, false, false })
+
+	flagOffline, flagMemCache = true, true
+	_, err := createClient()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--offline")
+
+	// Nothing is cached yet, so the query fails without a request
+	flagMemCache, flagJSON = false, true
+	err = runJourney(journeyCmd, []string{"2|#VN#1#ST#1"})
+	testutil.AssertTrue(t, errors.Is(err, api.ErrNotCached))
+	testutil.AssertContains(t, err.Error(), "run this query while online first")
+
+	// The status check never falls back to the cache
+	err = runStatus(statusCmd, nil)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--offline")
+}
+
+func TestPickStation(t *testing.T) {
+	matches := []models.Location{
+		{Name: "Frankfurt(Main)Hbf", EVA: 8000105, ID: "A=1@O=Frankfurt(Main)Hbf@L=8000105@"},
+		{Name: "Frankfurt(Oder)", EVA: 8010113, ID: "A=1@O=Frankfurt(Oder)@L=8010113@"},
+	}
+
+	_, err := pickStation(nil, "Atlantis", false, strings.NewReader(""), io.Discard)
+	testutil.AssertError(t, err)
+
+	got, err := pickStation(matches[:1], "Frankfurt", false, strings.NewReader(""), io.Discard)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, got.EVA, int64(8000105))
+
+	// Several equally good matches need --interactive
+	_, err = pickStation(matches, "Frankfurt", false, strings.NewReader(""), io.Discard)
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--interactive")
+
+	var prompt bytes.Buffer
+	got, err = pickStation(matches, "Frankfurt", true, strings.NewReader("2\n"), &prompt)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, got.EVA, int64(8010113))
+	testutil.AssertContains(t, prompt.String(), "2) Frankfurt(Oder) (8010113)")
+
+	_, err = pickStation(matches, "Frankfurt", true, strings.NewReader("3\n"), io.Discard)
+	testutil.AssertError(t, err)
+	_, err = pickStation(matches, "Frankfurt", true, strings.NewReader(""), io.Discard)
+	testutil.AssertError(t, err)
+}
+
+func TestRunSearch_SaveAsValidation(t *testing.T) {
+	t.Cleanup(func() { flagSaveAs, flagInteractive, flagJSON = "", false, false })
+
+	flagSaveAs = "my home"
+	testutil.AssertError(t, runSearch(searchCmd, []string{"Köln"}))
+
+	flagSaveAs, flagJSON = "home", true
+	testutil.AssertError(t, runSearch(searchCmd, []string{"Köln"}))
+
+	flagSaveAs, flagJSON, flagInteractive = "", false, true
+	testutil.AssertError(t, runSearch(searchCmd, []string{"Köln"}))
+}
+
+func TestFavCommands(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	var buf bytes.Buffer
+	outWriter = &buf
+	t.Cleanup(func() {
+		outWriter = os.Stdout
+		flagFavForce = false
+	})
+
+	const station = "8000207:A=1@O=Köln Hbf@X=6958730@Y=50943029@U=80@L=8000207@"
+	testutil.AssertNil(t, runFavAdd(favAddCmd, []string{"home", station}))
+	testutil.AssertContains(t, buf.String(), "Saved Köln Hbf as @home")
+
+	// Names are unique unless --force replaces the entry
+	err := runFavAdd(favAddCmd, []string{"Home", "8000105:A=1@O=Frankfurt(Main)Hbf@L=8000105@"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--force")
+	testutil.AssertError(t, runFavAdd(favAddCmd, []string{"my home", station}))
+
+	buf.Reset()
+	testutil.AssertNil(t, runFavList(favListCmd, nil))
+	testutil.AssertContains(t, buf.String(), "@home  Köln Hbf (8000207)")
+
+	// @name resolves without a network lookup
+	eva, stationID, err := resolveStationArg(context.Background(), nil, "@home")
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, eva, int64(8000207))
+	testutil.AssertContains(t, stationID, "O=Köln Hbf")
+	_, _, err = resolveStationArg(context.Background(), nil, "@gym")
+	testutil.AssertError(t, err)
+
+	favs := tuiFavorites()
+	testutil.AssertLen(t, favs, 1)
+	testutil.AssertEqual(t, favs[0].Station.Name, "Köln Hbf")
+
+	testutil.AssertNil(t, runFavRm(favRmCmd, []string{"home"}))
+	testutil.AssertError(t, runFavRm(favRmCmd, []string{"home"}))
+	buf.Reset()
+	testutil.AssertNil(t, runFavList(favListCmd, nil))
+	testutil.AssertContains(t, buf.String(), "No favorites saved")
+}
+
+func TestHomeStationEVA(t *testing.T) {
+	configDir := filepath.Join(t.TempDir(), "moko")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Dir(configDir))
+
+	_, err := homeStationEVA()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "home_eva")
+
+	testutil.AssertNil(t, os.MkdirAll(configDir, 0750))
+	testutil.AssertNil(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"home_eva": 8000044}`), 0600))
+	eva, err := homeStationEVA()
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, eva, int64(8000044))
+
+	// Boards at home itself need no lookups
+	deps := []models.Departure{{Destination: "Bonn Hbf", JourneyID: "1"}}
+	markCallsAtHome(context.Background(), nil, deps, 8000044, 8000044)
+	testutil.AssertFalse(t, deps[0].CallsAtHome)
+}
+
+func TestLoadTheme(t *testing.T) {
+	configDir := filepath.Join(t.TempDir(), "moko")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Dir(configDir))
+	t.Cleanup(func() { flagTheme, theme = "", output.DefaultTheme })
+
+	testutil.AssertNil(t, loadTheme())
+	testutil.AssertEqual(t, theme.Name, "default")
+
+	// The config picks the theme unless --theme overrides it
+	testutil.AssertNil(t, os.MkdirAll(configDir, 0750))
+	testutil.AssertNil(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"theme": "solarized"}`), 0600))
+	testutil.AssertNil(t, loadTheme())
+	testutil.AssertEqual(t, theme.Name, "solarized")
+
+	flagTheme = "mono"
+	testutil.AssertNil(t, loadTheme())
+	testutil.AssertEqual(t, theme.Name, "mono")
+
+	flagTheme = "neon"
+	err := loadTheme()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "unknown theme")
+
+	// Marker overrides from the config apply to any theme
+	flagTheme = "mono"
+	testutil.AssertNil(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"markers": {"current": ">", "current_color": "5"}}`), 0600))
+	testutil.AssertNil(t, loadTheme())
+	testutil.AssertEqual(t, theme.Name, "mono")
+	testutil.AssertEqual(t, theme.CurrentMarker, ">")
+	testutil.AssertEqual(t, theme.CurrentBg, "5")
+
+	// Config problems fall back to the defaults instead of breaking every
+	// command, help included
+	testutil.AssertNil(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"markers": {"current": "=>"}}`), 0600))
+	testutil.AssertNil(t, loadTheme())
+	testutil.AssertEqual(t, theme.Name, "mono")
+	testutil.AssertTrue(t, theme.CurrentMarker != "=>")
+
+	flagTheme = ""
+	testutil.AssertNil(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"theme": "neon"}`), 0600))
+	testutil.AssertNil(t, loadTheme())
+	testutil.AssertEqual(t, theme.Name, "default")
+
+	testutil.AssertNil(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"theme": `), 0600))
+	testutil.AssertNil(t, loadTheme())
+	testutil.AssertEqual(t, theme.Name, "default")
+}
+
+func TestCompleteStation(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	var buf bytes.Buffer
+	outWriter = &buf
+	t.Cleanup(func() { outWriter = os.Stdout })
+	testutil.AssertNil(t, runFavAdd(favAddCmd, []string{"home", "8000207:A=1@O=Köln Hbf@L=8000207@"}))
+
+	candidates, directive := completeStation(departuresCmd, nil, "@")
+	testutil.AssertLen(t, candidates, 1)
+	testutil.AssertEqual(t, candidates[0], "@home\tKöln Hbf")
+	testutil.AssertEqual(t, directive, cobra.ShellCompDirectiveNoFileComp)
+
+	// Only the first argument is a station, and empty input needs no search
+	candidates, _ = completeStation(departuresCmd, []string{"8000207"}, "")
+	testutil.AssertLen(t, candidates, 0)
+	candidates, _ = completeStation(departuresCmd, nil, " ")
+	testutil.AssertLen(t, candidates, 0)
+}
+
+func TestRunCompletion(t *testing.T) {
+	var buf bytes.Buffer
+	outWriter = &buf
+	t.Cleanup(func() { outWriter = os.Stdout })
+
+	for shell, want := range map[string]string{
+		"bash":       "bash completion V2 for moko",
+		"zsh":        "#compdef moko",
+		"fish":       "fish completion for moko",
+		"powershell": "powershell completion for moko",
+	} {
+		buf.Reset()
+		testutil.AssertNil(t, runCompletion(completionCmd, []string{shell}))
+		testutil.AssertContains(t, buf.String(), want)
+	}
+	testutil.AssertError(t, runCompletion(completionCmd, []string{"tcsh"}))
+}
+
+func TestOutputTemplate(t *testing.T) {
+	tmpl, err := parseOutputTemplate("", sampleDeparture())
+	testutil.AssertNil(t, err)
+	testutil.AssertTrue(t, tmpl == nil)
+
+	// Misspelled fields and syntax errors fail before anything is fetched
+	_, err = parseOutputTemplate("{{.Destinaton}}", sampleDeparture())
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "invalid --template")
+	_, err = parseOutputTemplate("{{.Line", sampleDeparture())
+	testutil.AssertError(t, err)
+
+	tmpl, err = parseOutputTemplate(`{{hhmm .Dep}} {{.Line}} {{.Destination}} {{.EffectivePlatform}} {{join .Via ", "}}`, sampleDeparture())
+	testutil.AssertNil(t, err)
+	dep := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	deps := []models.Departure{
+		{Dep: &dep, Line: "S 11", Destination: "Düsseldorf", Platform: "10", RTPlatform: "11", Via: []string{"Köln Messe/Deutz", "Mülheim"}},
+		{Line: "RE 5", Destination: "Koblenz Hbf"},
+	}
+	var buf bytes.Buffer
+	testutil.AssertNil(t, renderTemplate(&buf, tmpl, deps))
+	testutil.AssertEqual(t, buf.String(), "14:30 S 11 Düsseldorf 11 Köln Messe/Deutz, Mülheim\n??:?? RE 5 Koblenz Hbf  \n")
+
+	// Stops have their own fields
+	tmpl, err = parseOutputTemplate("{{.Name}} {{upper .Platform}}\n", sampleStop())
+	testutil.AssertNil(t, err)
+	buf.Reset()
+	testutil.AssertNil(t, renderTemplate(&buf, tmpl, []models.Stop{{Name: "Köln Hbf", Platform: "4a"}}))
+	testutil.AssertEqual(t, buf.String(), "Köln Hbf 4A\n")
+	_, err = parseOutputTemplate("{{.Line}}", sampleStop())
+	testutil.AssertError(t, err)
+}
+
+func TestRunDepartures_TemplateConflicts(t *testing.T) {
+	t.Cleanup(func() { flagTemplate, flagJSON = "", false })
+
+	flagTemplate, flagJSON = "{{.Line}}", true
+	testutil.AssertError(t, runDepartures(departuresCmd, []string{"8000207"}))
+	testutil.AssertError(t, runJourney(journeyCmd, []string{"id"}))
+}
+
+func TestPrintJourneyIDs(t *testing.T) {
+	deps := []models.Departure{
+		{Line: "S 11", JourneyID: "2|#VN#1#ST#1#"},
+		{Line: "RE 5"},
+		{Line: "S 12", JourneyID: "2|#VN#1#ST#2#"},
+	}
+	var buf bytes.Buffer
+	testutil.AssertNil(t, printJourneyIDs(&buf, deps))
+	testutil.AssertEqual(t, buf.String(), "2|#VN#1#ST#1#\n2|#VN#1#ST#2#\n")
+}
+
+func TestRunDepartures_JourneyIDOnlyConflicts(t *testing.T) {
+	t.Cleanup(func() { flagIDsOnly, flagJSON, flagWatch = false, false, false })
+
+	flagIDsOnly, flagJSON = true, true
+	err := runDepartures(departuresCmd, []string{"8000207"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--journey-id-only")
+
+	flagJSON, flagWatch = false, true
+	testutil.AssertError(t, runArrivals(arrivalsCmd, []string{"8000207"}))
+}
+
+func TestRunDepartures_AggregateConflicts(t *testing.T) {
+	t.Cleanup(func() { flagAggregate, flagRawJSON, flagIDsOnly = false, false, false })
+
+	flagAggregate, flagRawJSON = true, true
+	err := runDepartures(departuresCmd, []string{"8000207"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--aggregate")
+
+	flagRawJSON, flagIDsOnly = false, true
+	testutil.AssertError(t, runDepartures(departuresCmd, []string{"8000207"}))
+}
+
+func TestBoardTableOptions(t *testing.T) {
+	t.Cleanup(func() { flagGroupBy, flagDelayStyle, flagShowVia = "", output.DelayNumeric, false })
+
+	flagGroupBy, flagDelayStyle, flagShowVia = " MODE ", "Compact", true
+	opts, err := boardTableOptions()
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, opts.GroupBy, output.GroupByMode)
+	testutil.AssertEqual(t, opts.DelayStyle, output.DelayCompact)
+	testutil.AssertTrue(t, opts.ShowVia)
+	// The flags keep what the user typed
+	testutil.AssertEqual(t, flagGroupBy, " MODE ")
+	testutil.AssertEqual(t, flagDelayStyle, "Compact")
+
+	flagDelayStyle = "fancy"
+	_, err = boardTableOptions()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--delay-style")
+}
+
+func TestBoardWindow(t *testing.T) {
+	t.Cleanup(func() { flagWindow, flagRawJSON = 0, false })
+
+	flagWindow = 90
+	window, err := boardWindow()
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, window, 90*time.Minute)
+
+	flagWindow = -5
+	_, err = boardWindow()
+	testutil.AssertError(t, err)
+
+	flagWindow, flagRawJSON = 30, true
+	_, err = boardWindow()
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--raw-json")
+	testutil.AssertError(t, runArrivals(arrivalsCmd, []string{"8000207"}))
+}
+
+func TestRunDepartures_FormatValidation(t *testing.T) {
+	t.Cleanup(func() { flagFormat, flagJSON = "", false })
+
+	flagFormat = "ics"
+	err := runDepartures(departuresCmd, []string{"8000207"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "use text or gtfs-json")
+
+	flagFormat, flagJSON = "gtfs-json", true
+	err = runDepartures(departuresCmd, []string{"8000207"})
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "--format gtfs-json cannot be combined")
+}
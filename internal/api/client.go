@@ -1,25 +1,34 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	crand "crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
+	"net"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/mobil-koeln/moko-cli/internal/cache"
 	"github.com/mobil-koeln/moko-cli/internal/models"
+	"golang.org/x/time/rate"
 )
 
 const (
 	defaultTimeout  = 10 * time.Second
 	defaultCacheTTL = 90 * time.Second
+
+	// MaxEmptyRetries caps WithEmptyRetry so a board that is genuinely empty
+	// (e.g. a small station at night) is reported without a long wait
+	MaxEmptyRetries = 3
 )
 
 // browserProfile holds a consistent browser identity for a client session.
@@ -94,13 +103,45 @@ type Cache interface {
 	Set(key string, value []byte) error
 }
 
+// AgeCache is a Cache that can report how long ago an entry was stored.
+// It is required for WithMaxAge to serve cached responses.
+type AgeCache interface {
+	Cache
+	GetWithAge(key string) ([]byte, time.Duration, bool)
+}
+
+// StaleCache is a Cache that can also return expired entries. WithOffline
+// uses it to serve responses older than the cache TTL.
+type StaleCache interface {
+	Cache
+	GetStale(key string) ([]byte, time.Duration, bool)
+}
+
 // Client is the API client for bahn.de
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
+	geoIPURL   string
 	timezone   *time.Location
+	tzFallback bool // true when timezone is a fixed-offset stand-in for Europe/Berlin
 	cache      Cache
+	maxAge     time.Duration // 0 means any unexpired cache entry is served
 	browser    browserProfile
+	headers    http.Header // overrides applied after the browser headers
+
+	emptyRetries    int           // refetches of a board that parsed to zero entries
+	emptyRetryDelay time.Duration // wait before each of those refetches
+
+	retryAttempts  int           // total attempts per request on transient failures
+	retryBaseDelay time.Duration // backoff before the second attempt, doubled after each
+
+	cookies *persistentJar // set by WithPersistentCookies, saved by Close
+
+	limiter *rate.Limiter // set by WithRateLimit; nil sends requests unthrottled
+
+	offline bool // serve from the cache only, see WithOffline
+
+	now func() time.Time // current time for requests without an explicit one
 }
 
 // ClientOption configures the Client
@@ -127,6 +168,105 @@ func WithCache(cache Cache) ClientOption {
 	}
 }
 
+// WithMaxAge limits cached responses to entries younger than d, refetching
+// older ones even when they are still within the cache TTL. Caches that do not
+// implement AgeCache are bypassed on reads when a max age is set.
+func WithMaxAge(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxAge = d
+	}
+}
+
+// WithHeader sets a request header, replacing the browser default of the
+// same name; an empty value removes the header. Origin, Referer, User-Agent,
+// Accept-Language and the sec-ch-ua hints are safe to change. Host,
+// Content-Length and Accept-Encoding are managed by the HTTP transport and
+// overriding them can break requests.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Set(key, value)
+	}
+}
+
+// WithEmptyRetry refetches a departure or arrival board that came back
+// without any entries up to retries times, waiting delay before each attempt.
+// bahn.de occasionally serves an empty board that fills moments later. Retries
+// bypass the cache and are capped at MaxEmptyRetries.
+func WithEmptyRetry(retries int, delay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.emptyRetries = min(max(retries, 0), MaxEmptyRetries)
+		c.emptyRetryDelay = delay
+	}
+}
+
+// WithRetry retries requests that fail with 429, 502, 503 or 504 or a network
+// timeout, making up to maxAttempts attempts in total. The wait before each
+// retry starts at baseDelay and doubles, plus up to 50% random jitter. Other
+// errors fail immediately and no retry is started once ctx is done or its
+// deadline would pass before the next attempt.
+func WithRetry(maxAttempts int, baseDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retryAttempts = max(maxAttempts, 1)
+		c.retryBaseDelay = baseDelay
+	}
+}
+
+// WithRateLimit spaces out requests to at most rps per second, allowing
+// bursts of up to burst requests, so that concurrent refreshes don't look like
+// a bot to bahn.de. Every request sent waits its turn, retries included; cache
+// hits are served without waiting. By default requests are not limited.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.limiter = rate.NewLimiter(rate.Limit(rps), max(burst, 1))
+	}
+}
+
+// WithOffline serves every request from the cache and never touches the
+// network. Cached responses are served regardless of age when the cache is a
+// StaleCache; requests without one fail with ErrNotCached. Boards for the
+// current time are served from the latest board cached for the station, as
+// the exact query minute is unlikely to be cached. Empty boards are not
+// refetched.
+func WithOffline() ClientOption {
+	return func(c *Client) {
+		c.offline = true
+	}
+}
+
+// WithClock sets the clock that supplies the query time of requests that
+// don't specify one, e.g. boards for "now"
+func WithClock(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		c.now = now
+	}
+}
+
+// WithMemoryCache enables an in-memory cache of at most maxEntries responses,
+// each kept for ttl. Nothing is written to disk, which suits CI and other
+// ephemeral environments.
+func WithMemoryCache(maxEntries int, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cache = cache.NewMemoryCache(maxEntries, ttl)
+	}
+}
+
+// WithPersistentCookies loads session cookies from path and saves them back
+// on Close, so that one-shot commands reuse the bahn.de session instead of
+// starting a new one each time. A missing or corrupt file starts a fresh
+// session.
+func WithPersistentCookies(path string) ClientOption {
+	return func(c *Client) {
+		jar, _ := newPersistentJar(path, time.Now())
+		if jar != nil {
+			c.cookies = jar
+			c.httpClient.Jar = jar
+		}
+	}
+}
+
 // WithDefaultCache enables caching with the default file cache
 func WithDefaultCache() ClientOption {
 	return func(c *Client) {
@@ -139,10 +279,7 @@ func WithDefaultCache() ClientOption {
 
 // NewClient creates a new API client
 func NewClient(opts ...ClientOption) (*Client, error) {
-	tz, err := time.LoadLocation("Europe/Berlin")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load timezone: %w", err)
-	}
+	tz, tzFallback := loadTimezone(time.Now())
 
 	jar, err := cookiejar.New(nil)
 	if err != nil {
@@ -154,9 +291,12 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 			Timeout: defaultTimeout,
 			Jar:     jar,
 		},
-		baseURL:  BaseURL,
-		timezone: tz,
-		browser:  newBrowserProfile(),
+		baseURL:    BaseURL,
+		geoIPURL:   GeoIPURL,
+		timezone:   tz,
+		tzFallback: tzFallback,
+		browser:    newBrowserProfile(),
+		now:        time.Now,
 	}
 
 	for _, opt := range opts {
@@ -166,11 +306,63 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 	return c, nil
 }
 
+// Close saves the cookies of a client created with WithPersistentCookies.
+// It is a no-op for other clients and may be called more than once.
+func (c *Client) Close() error {
+	if c.cookies == nil {
+		return nil
+	}
+	return c.cookies.save(time.Now())
+}
+
 // Timezone returns the client's timezone
 func (c *Client) Timezone() *time.Location {
 	return c.timezone
 }
 
+// TimezoneFallback reports whether the Europe/Berlin zone database was
+// unavailable and a fixed CET/CEST offset is used instead.
+func (c *Client) TimezoneFallback() bool {
+	return c.tzFallback
+}
+
+// Timezone returns the zone the API reports times in: Europe/Berlin, or the
+// fixed CET/CEST offset at now when the zone database is missing.
+func Timezone(now time.Time) *time.Location {
+	tz, _ := loadTimezone(now)
+	return tz
+}
+
+// loadTimezone loads the Europe/Berlin timezone. When the zone database is
+// missing (e.g. scratch containers without tzdata), it falls back to a fixed
+// offset matching the EU daylight saving rules at the given instant. The fixed
+// zone does not switch offsets, so long-running processes may drift by an hour
+// across a DST change.
+func loadTimezone(now time.Time) (*time.Location, bool) {
+	if tz, err := time.LoadLocation("Europe/Berlin"); err == nil {
+		return tz, false
+	}
+	if isEUSummerTime(now) {
+		return time.FixedZone("CEST", 2*60*60), true
+	}
+	return time.FixedZone("CET", 60*60), true
+}
+
+// isEUSummerTime reports whether t falls within EU summer time, which runs from
+// 01:00 UTC on the last Sunday of March to 01:00 UTC on the last Sunday of October.
+func isEUSummerTime(t time.Time) bool {
+	t = t.UTC()
+	start := lastSundayUTC(t.Year(), time.March)
+	end := lastSundayUTC(t.Year(), time.October)
+	return !t.Before(start) && t.Before(end)
+}
+
+// lastSundayUTC returns 01:00 UTC on the last Sunday of the given month.
+func lastSundayUTC(year int, month time.Month) time.Time {
+	d := time.Date(year, month+1, 1, 1, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	return d.AddDate(0, 0, -int(d.Weekday()))
+}
+
 // StationBoardRequest contains parameters for a departure/arrival query
 type StationBoardRequest struct {
 	EVA            int64     // Station EVA number (required)
@@ -178,6 +370,10 @@ type StationBoardRequest struct {
 	DateTime       time.Time // Query time (defaults to now)
 	NumVias        int       // Number of via stations (default: 5)
 	ModesOfTransit []string  // Filter by transport mode (default: all)
+	// Window limits results to entries within this duration after DateTime
+	// (default: whatever window the API returns). It filters the fetched
+	// board and cannot extend it; ignored by the Raw methods.
+	Window time.Duration
 }
 
 // DepartureRequest is an alias for StationBoardRequest for backward compatibility
@@ -185,24 +381,19 @@ type DepartureRequest = StationBoardRequest
 
 // GetDepartures fetches departures for a station
 func (c *Client) GetDepartures(ctx context.Context, req StationBoardRequest) ([]models.Departure, error) {
-	body, err := c.GetDeparturesRaw(ctx, req)
+	resp, err := c.getStationBoard(ctx, req, EndpointDepartures, "departures")
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse response
-	var resp models.DeparturesResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse departures response: %w", err)
-	}
-
 	// Convert to domain models
 	departures := make([]models.Departure, 0, len(resp.Entries))
 	for _, entry := range resp.Entries {
 		departures = append(departures, *entry.ToDeparture(c.timezone))
 	}
+	models.MarkEndpoints(departures, models.StationNameFromID(req.StationID), false)
 
-	return departures, nil
+	return c.applyWindow(departures, req), nil
 }
 
 // GetDeparturesRaw fetches departures and returns raw JSON
@@ -212,24 +403,19 @@ func (c *Client) GetDeparturesRaw(ctx context.Context, req StationBoardRequest)
 
 // GetArrivals fetches arrivals for a station
 func (c *Client) GetArrivals(ctx context.Context, req StationBoardRequest) ([]models.Departure, error) {
-	body, err := c.GetArrivalsRaw(ctx, req)
+	resp, err := c.getStationBoard(ctx, req, EndpointArrivals, "arrivals")
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse response (same format as departures)
-	var resp models.DeparturesResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse arrivals response: %w", err)
-	}
-
 	// Convert to domain models
 	arrivals := make([]models.Departure, 0, len(resp.Entries))
 	for _, entry := range resp.Entries {
-		arrivals = append(arrivals, *entry.ToDeparture(c.timezone))
+		arrivals = append(arrivals, *entry.ToArrival(c.timezone))
 	}
+	models.MarkEndpoints(arrivals, models.StationNameFromID(req.StationID), true)
 
-	return arrivals, nil
+	return c.applyWindow(arrivals, req), nil
 }
 
 // GetArrivalsRaw fetches arrivals and returns raw JSON
@@ -237,18 +423,113 @@ func (c *Client) GetArrivalsRaw(ctx context.Context, req StationBoardRequest) (j
 	return c.getStationBoardRaw(ctx, req, EndpointArrivals)
 }
 
+// boardTime returns the query time for a station board request
+func (c *Client) boardTime(req StationBoardRequest) time.Time {
+	// Use current time if not specified
+	if req.DateTime.IsZero() {
+		return c.now().In(c.timezone)
+	}
+	return req.DateTime
+}
+
+// applyWindow drops entries whose effective time lies beyond the request window.
+// The bahn.de board endpoints always return a time-bounded window; this only
+// narrows it further, so entries without a time are kept.
+func (c *Client) applyWindow(deps []models.Departure, req StationBoardRequest) []models.Departure {
+	if req.Window <= 0 {
+		return deps
+	}
+
+	// Compare at minute precision, matching the "zeit" query parameter
+	end := c.boardTime(req).Truncate(time.Minute).Add(req.Window)
+	filtered := deps[:0]
+	for _, d := range deps {
+		if d.Dep != nil && d.Dep.After(end) {
+			continue
+		}
+		filtered = append(filtered, d)
+	}
+	return filtered
+}
+
+// getStationBoard fetches and parses a departure or arrival board, refetching
+// empty boards as configured by WithEmptyRetry
+func (c *Client) getStationBoard(ctx context.Context, req StationBoardRequest, endpoint, kind string) (*models.DeparturesResponse, error) {
+	reqURL := c.stationBoardURL(req, endpoint)
+	body, err := c.doBoardRequest(ctx, req, reqURL, endpoint)
+	for attempt := 0; ; attempt++ {
+		if err != nil {
+			return nil, err
+		}
+
+		var resp models.DeparturesResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse %s response: %w", kind, err)
+		}
+		if len(resp.Entries) > 0 || attempt >= c.emptyRetries || c.offline {
+			return &resp, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrTimeout, ctx.Err())
+		case <-time.After(c.emptyRetryDelay):
+		}
+		body, err = c.fetchBoard(ctx, reqURL, c.latestBoardKey(req, endpoint))
+	}
+}
+
 // getStationBoardRaw is a helper for fetching departures/arrivals
 func (c *Client) getStationBoardRaw(ctx context.Context, req StationBoardRequest, endpoint string) (json.RawMessage, error) {
-	// Use current time if not specified
-	dt := req.DateTime
-	if dt.IsZero() {
-		dt = time.Now().In(c.timezone)
+	return c.doBoardRequest(ctx, req, c.stationBoardURL(req, endpoint), endpoint)
+}
+
+// doBoardRequest performs the GET request for a board. The query time makes
+// every minute a new cache key, so offline boards for "now" fall back to the
+// latest board fetched for the station, see latestBoardKey.
+func (c *Client) doBoardRequest(ctx context.Context, req StationBoardRequest, reqURL, endpoint string) ([]byte, error) {
+	if data, ok := c.cachedResponse(reqURL); ok {
+		return data, nil
 	}
+	latestKey := c.latestBoardKey(req, endpoint)
+	if c.offline && req.DateTime.IsZero() {
+		if data, ok := c.cachedResponse(latestKey); ok {
+			return data, nil
+		}
+	}
+	return c.fetchBoard(ctx, reqURL, latestKey)
+}
 
-	// Build query parameters
-	params := url.Values{}
+// fetchBoard fetches a board like fetch and also caches it under latestKey
+func (c *Client) fetchBoard(ctx context.Context, reqURL, latestKey string) ([]byte, error) {
+	body, err := c.fetch(ctx, reqURL)
+	if err == nil && c.cache != nil {
+		_ = c.cache.Set(latestKey, body)
+	}
+	return body, err
+}
+
+// latestBoardKey is the cache key of the latest board fetched for the same
+// station, endpoint, modes and vias, whatever its query time
+func (c *Client) latestBoardKey(req StationBoardRequest, endpoint string) string {
+	return c.baseURL + endpoint + "?" + boardParams(req).Encode() + "#latest"
+}
+
+// stationBoardURL builds the request URL for a departure or arrival board
+func (c *Client) stationBoardURL(req StationBoardRequest, endpoint string) string {
+	dt := c.boardTime(req)
+
+	params := boardParams(req)
 	params.Set("datum", dt.Format("2006-01-02"))
 	params.Set("zeit", dt.Format("15:04:00"))
+
+	return c.baseURL + endpoint + "?" + params.Encode()
+}
+
+// boardParams returns the query parameters of a board request except its
+// date and time
+func boardParams(req StationBoardRequest) url.Values {
+	params := url.Values{}
 	params.Set("ortExtId", fmt.Sprintf("%d", req.EVA))
 	params.Set("ortId", req.StationID)
 	params.Set("mitVias", "true")
@@ -267,10 +548,7 @@ func (c *Client) getStationBoardRaw(ctx context.Context, req StationBoardRequest
 	for _, mot := range mots {
 		params.Add("verkehrsmittel[]", mot)
 	}
-
-	reqURL := c.baseURL + endpoint + "?" + params.Encode()
-
-	return c.doRequest(ctx, reqURL)
+	return params
 }
 
 // NearbyRequest contains parameters for a nearby search
@@ -355,12 +633,59 @@ func (c *Client) SearchLocationsRaw(ctx context.Context, query string) (json.Raw
 	return c.doRequest(ctx, reqURL)
 }
 
-// GetJourney fetches journey details by journey ID
-func (c *Client) GetJourney(ctx context.Context, journeyID string, withPolyline bool) (*models.Journey, error) {
-	body, err := c.GetJourneyRaw(ctx, journeyID, withPolyline)
+// GetStationDetails returns the station with the given EVA number, as
+// listed by the location search. It fails with ErrNotFound when the search
+// has no exact match.
+func (c *Client) GetStationDetails(ctx context.Context, eva int64) (*models.Location, error) {
+	locations, err := c.SearchLocations(ctx, strconv.FormatInt(eva, 10))
 	if err != nil {
 		return nil, err
 	}
+	for i := range locations {
+		if locations[i].EVA == eva {
+			return &locations[i], nil
+		}
+	}
+	return nil, fmt.Errorf("station %d: %w", eva, ErrNotFound)
+}
+
+// Ping probes whether the API is up with a single small location search that
+// bypasses the cache and retries. It returns the HTTP status of the response,
+// or 0 when none was received, and an error unless the status is 200.
+// It always needs the network, so it fails offline.
+func (c *Client) Ping(ctx context.Context) (int, error) {
+	if c.offline {
+		return 0, fmt.Errorf("offline: %w: the API status is never cached", ErrNotCached)
+	}
+	params := url.Values{}
+	params.Set("suchbegriff", "Köln Hbf")
+	params.Set("typ", "ALL")
+	params.Set("limit", "1")
+
+	_, err := c.sendOnce(ctx, http.MethodGet, c.baseURL+EndpointLocations+"?"+params.Encode(), nil)
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		return http.StatusOK, nil
+	case errors.As(err, &apiErr):
+		return apiErr.StatusCode, err
+	}
+	return 0, err
+}
+
+// GetJourney fetches journey details by journey ID. Without withPolyline, a
+// cached response that includes the polyline is reused, as it is a superset.
+func (c *Client) GetJourney(ctx context.Context, journeyID string, withPolyline bool) (*models.Journey, error) {
+	var body []byte
+	if !withPolyline {
+		body, _ = c.cachedResponse(journeyURL(c.baseURL, journeyID, true))
+	}
+	if body == nil {
+		var err error
+		if body, err = c.GetJourneyRaw(ctx, journeyID, withPolyline); err != nil {
+			return nil, err
+		}
+	}
 
 	var resp models.JourneyResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
@@ -372,6 +697,12 @@ func (c *Client) GetJourney(ctx context.Context, journeyID string, withPolyline
 
 // GetJourneyRaw fetches journey details and returns raw JSON
 func (c *Client) GetJourneyRaw(ctx context.Context, journeyID string, withPolyline bool) (json.RawMessage, error) {
+	return c.doRequest(ctx, journeyURL(c.baseURL, journeyID, withPolyline))
+}
+
+// journeyURL builds the journey request URL. Parameters are always encoded in
+// the same order, so the URL doubles as a stable cache key.
+func journeyURL(baseURL, journeyID string, withPolyline bool) string {
 	params := url.Values{}
 	params.Set("journeyId", journeyID)
 	if withPolyline {
@@ -379,10 +710,7 @@ func (c *Client) GetJourneyRaw(ctx context.Context, journeyID string, withPolyli
 	} else {
 		params.Set("poly", "false")
 	}
-
-	reqURL := c.baseURL + EndpointJourney + "?" + params.Encode()
-
-	return c.doRequest(ctx, reqURL)
+	return baseURL + EndpointJourney + "?" + params.Encode()
 }
 
 // FormationRequest contains parameters for a formation query
@@ -413,7 +741,7 @@ func (c *Client) GetFormationRaw(ctx context.Context, req FormationRequest) (jso
 	// Convert departure time to UTC
 	departure := req.Departure
 	if departure.IsZero() {
-		departure = time.Now().In(c.timezone)
+		departure = c.now().In(c.timezone)
 	}
 	utcTime := departure.UTC()
 
@@ -430,19 +758,194 @@ func (c *Client) GetFormationRaw(ctx context.Context, req FormationRequest) (jso
 	return c.doRequest(ctx, reqURL)
 }
 
+// ConnectionRequest contains parameters for a trip search between two stations
+type ConnectionRequest struct {
+	FromID         string    // Station ID of the origin (required)
+	ToID           string    // Station ID of the destination (required)
+	DateTime       time.Time // Earliest departure (defaults to now)
+	ModesOfTransit []string  // Filter by transport mode (default: all)
+}
+
+// connectionSearch is the JSON body of a trip search
+type connectionSearch struct {
+	From             string               `json:"abfahrtsHalt"`
+	To               string               `json:"ankunftsHalt"`
+	Time             string               `json:"anfrageZeitpunkt"`
+	SearchBy         string               `json:"ankunftSuche"`
+	Class            string               `json:"klasse"`
+	Modes            []string             `json:"produktgattungen"`
+	Travellers       []connectionTraveler `json:"reisende"`
+	FastConnections  bool                 `json:"schnelleVerbindungen"`
+	SeatOnly         bool                 `json:"sitzplatzOnly"`
+	BikeCarriage     bool                 `json:"bikeCarriage"`
+	ReservationQuota bool                 `json:"reservierungsKontingenteVorhanden"`
+}
+
+// connectionTraveler describes a passenger; the trip search requires one
+type connectionTraveler struct {
+	Type       string `json:"typ"`
+	Discounts  []any  `json:"ermaessigungen"`
+	Ages       []int  `json:"alter"`
+	Passengers int    `json:"anzahl"`
+}
+
+// GetConnections searches for connections between two stations
+func (c *Client) GetConnections(ctx context.Context, req ConnectionRequest) ([]models.Connection, error) {
+	body, err := c.GetConnectionsRaw(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp models.ConnectionsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse connections response: %w", err)
+	}
+
+	connections := make([]models.Connection, 0, len(resp.Verbindungen))
+	for i := range resp.Verbindungen {
+		connections = append(connections, *resp.Verbindungen[i].ToConnection(c.timezone))
+	}
+	return connections, nil
+}
+
+// GetConnectionsRaw searches for connections and returns raw JSON
+func (c *Client) GetConnectionsRaw(ctx context.Context, req ConnectionRequest) (json.RawMessage, error) {
+	if req.FromID == "" || req.ToID == "" {
+		return nil, fmt.Errorf("connection search needs origin and destination station IDs")
+	}
+
+	dt := req.DateTime
+	if dt.IsZero() {
+		dt = c.now()
+	}
+	modes := req.ModesOfTransit
+	if len(modes) == 0 {
+		modes = ModesOfTransit
+	}
+
+	search := connectionSearch{
+		From:     req.FromID,
+		To:       req.ToID,
+		Time:     dt.In(c.timezone).Format("2006-01-02T15:04:05"),
+		SearchBy: "ABFAHRT",
+		Class:    "KLASSE_2",
+		Modes:    modes,
+		Travellers: []connectionTraveler{{
+			Type:       "ERWACHSENER",
+			Discounts:  []any{map[string]string{"art": "KEINE_ERMAESSIGUNG", "klasse": "KLASSENLOS"}},
+			Ages:       []int{},
+			Passengers: 1,
+		}},
+		FastConnections: true,
+	}
+	payload, err := json.Marshal(search)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode connection search: %w", err)
+	}
+
+	return c.doPost(ctx, c.baseURL+EndpointConnections, payload)
+}
+
+// doPost performs an HTTP POST request with a JSON body. Responses are cached
+// under the URL and body, as the URL alone doesn't identify the query.
+func (c *Client) doPost(ctx context.Context, reqURL string, payload []byte) ([]byte, error) {
+	key := reqURL + "#" + string(payload)
+	if data, ok := c.cachedResponse(key); ok {
+		return data, nil
+	}
+	return c.send(ctx, http.MethodPost, reqURL, payload, key)
+}
+
 // doRequest performs an HTTP GET request with optional caching
 func (c *Client) doRequest(ctx context.Context, reqURL string) ([]byte, error) {
 	// Check cache first
-	if c.cache != nil {
-		if data, ok := c.cache.Get(reqURL); ok {
-			return data, nil
+	if data, ok := c.cachedResponse(reqURL); ok {
+		return data, nil
+	}
+	return c.fetch(ctx, reqURL)
+}
+
+// fetch performs the HTTP GET request for reqURL, bypassing the cache on read
+// but storing the response in it
+func (c *Client) fetch(ctx context.Context, reqURL string) ([]byte, error) {
+	return c.send(ctx, http.MethodGet, reqURL, nil, reqURL)
+}
+
+// send performs an HTTP request with the browser headers, retrying transient
+// failures as configured by WithRetry, and stores a successful response in
+// the cache under cacheKey
+func (c *Client) send(ctx context.Context, method, reqURL string, payload []byte, cacheKey string) ([]byte, error) {
+	if c.offline {
+		return nil, notCachedError(reqURL)
+	}
+	for attempt := 1; ; attempt++ {
+		body, err := c.sendOnce(ctx, method, reqURL, payload)
+		if err == nil {
+			if c.cache != nil {
+				_ = c.cache.Set(cacheKey, body)
+			}
+			return body, nil
+		}
+		if attempt >= c.retryAttempts || ctx.Err() != nil || !isRetryable(err) {
+			return nil, err
+		}
+
+		wait := c.retryBackoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
 		}
 	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+// retryBackoff returns the wait before the retry following the given attempt:
+// the base delay doubled per attempt plus up to 50% jitter
+func (c *Client) retryBackoff(attempt int) time.Duration {
+	wait := c.retryBaseDelay << (attempt - 1)
+	if half := int(wait / 2); half > 0 {
+		wait += time.Duration(cryptoRandIntn(half))
+	}
+	return wait
+}
+
+// isRetryable reports whether err is a transient failure worth retrying:
+// rate limiting, a gateway or availability error, or a network timeout
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sendOnce performs a single HTTP request with the browser headers
+func (c *Client) sendOnce(ctx context.Context, method, reqURL string, payload []byte) ([]byte, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("request not sent: %w", err)
+		}
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	bp := c.browser
 
@@ -474,6 +977,15 @@ func (c *Client) doRequest(ctx context.Context, reqURL string) ([]byte, error) {
 	// Correlation ID per request
 	req.Header.Set("x-correlation-id", uuid4()+"_"+uuid4())
 
+	// User overrides win over the defaults
+	for key, values := range c.headers {
+		if values[0] == "" {
+			req.Header.Del(key)
+			continue
+		}
+		req.Header[key] = values
+	}
+
 	resp, err := c.httpClient.Do(req) //nolint:gosec // URL is constructed from fixed baseURL + API endpoint constants
 	if err != nil {
 		// Check for context errors
@@ -496,14 +1008,36 @@ func (c *Client) doRequest(ctx context.Context, reqURL string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	// Store in cache
-	if c.cache != nil {
-		_ = c.cache.Set(reqURL, body)
-	}
-
 	return body, nil
 }
 
+// cachedResponse returns the cached body for reqURL if present and, when a
+// max age is configured, young enough
+func (c *Client) cachedResponse(reqURL string) ([]byte, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+	if c.offline {
+		if sc, ok := c.cache.(StaleCache); ok {
+			data, _, ok := sc.GetStale(reqURL)
+			return data, ok
+		}
+		return c.cache.Get(reqURL)
+	}
+	if c.maxAge <= 0 {
+		return c.cache.Get(reqURL)
+	}
+	ac, ok := c.cache.(AgeCache)
+	if !ok {
+		return nil, false
+	}
+	data, age, ok := ac.GetWithAge(reqURL)
+	if !ok || age > c.maxAge {
+		return nil, false
+	}
+	return data, true
+}
+
 // extractEndpoint extracts the endpoint path from a full URL
 func extractEndpoint(fullURL string) string {
 	u, err := url.Parse(fullURL)
@@ -2,10 +2,17 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/mobil-koeln/moko-cli/internal/cache"
 	"github.com/mobil-koeln/moko-cli/internal/testutil"
 )
 
@@ -147,6 +154,29 @@ func TestSearchLocations_Success(t *testing.T) {
 	testutil.AssertTrue(t, len(locations) > 0)
 }
 
+func TestClient_GetStationDetails(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[
+			{"extId": "8098105", "name": "Frankfurt(Main)Hbf (tief)", "id": "A=1@O=Frankfurt(Main)Hbf (tief)@L=8098105@"},
+			{"extId": "8000105", "name": "Frankfurt(Main)Hbf", "id": "A=1@O=Frankfurt(Main)Hbf@X=8663785@Y=50107145@L=8000105@",
+			 "products": ["ICE", "EC_IC", "SBAHN"]}
+		]`))
+	})
+	defer ms.Close()
+
+	client := newTestClient(ms.URL)
+
+	station, err := client.GetStationDetails(context.Background(), 8000105)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, station.Name, "Frankfurt(Main)Hbf")
+	testutil.AssertLen(t, station.Products, 3)
+	testutil.AssertTrue(t, station.Lat > 50.1 && station.Lat < 50.2)
+
+	_, err = client.GetStationDetails(context.Background(), 8000207)
+	testutil.AssertTrue(t, errors.Is(err, ErrNotFound))
+}
+
 func TestSearchLocations_EmptyQuery(t *testing.T) {
 	client, _ := NewClient()
 
@@ -203,6 +233,221 @@ func TestClient_WithCache(t *testing.T) {
 	// (This test assumes cache is implemented correctly)
 }
 
+func TestClient_GetJourney_ReusesPolylineResponse(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleJourneyResponse))
+	})
+	defer ms.Close()
+
+	client := newTestClient(ms.URL)
+	client.cache = &mockCache{data: make(map[string][]byte)}
+	ctx := context.Background()
+
+	// A journey fetched with its polyline also answers plain requests
+	_, err := client.GetJourney(ctx, "j1", true)
+	testutil.AssertNil(t, err)
+	_, err = client.GetJourney(ctx, "j1", false)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, ms.RequestCount(), 1)
+
+	// The reverse is not true: a plain response lacks the geometry
+	_, err = client.GetJourney(ctx, "j2", false)
+	testutil.AssertNil(t, err)
+	_, err = client.GetJourney(ctx, "j2", true)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, ms.RequestCount(), 3)
+}
+
+func TestClient_WithMaxAge(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleDepartureResponse))
+	})
+	defer ms.Close()
+
+	ac := &mockAgeCache{mockCache: mockCache{data: make(map[string][]byte)}, ages: make(map[string]time.Duration)}
+	client := newTestClient(ms.URL)
+	client.cache = ac
+	client.maxAge = 30 * time.Second
+
+	req := StationBoardRequest{
+		EVA:       8000105,
+		StationID: "test",
+	}
+
+	// First call populates the cache
+	_, err := client.GetDepartures(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, ms.RequestCount(), 1)
+
+	// Young entry is served from cache
+	for key := range ac.data {
+		ac.ages[key] = 10 * time.Second
+	}
+	_, err = client.GetDepartures(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, ms.RequestCount(), 1)
+
+	// Entry older than max age triggers a refetch
+	for key := range ac.data {
+		ac.ages[key] = 45 * time.Second
+	}
+	_, err = client.GetDepartures(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, ms.RequestCount(), 2)
+}
+
+func TestClient_WithOffline(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleDepartureResponse))
+	})
+	defer ms.Close()
+
+	fc, err := cache.NewFileCache(t.TempDir(), time.Millisecond)
+	testutil.AssertNil(t, err)
+	req := StationBoardRequest{EVA: 8000105, StationID: "test"}
+	fetchedAt := time.Date(2025, 1, 15, 10, 0, 30, 0, time.UTC)
+
+	online := newTestClient(ms.URL)
+	online.cache = fc
+	WithClock(func() time.Time { return fetchedAt })(online)
+	_, err = online.GetDepartures(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, ms.RequestCount(), 1)
+
+	// Offline serves the board after it expired, without a request
+	time.Sleep(5 * time.Millisecond)
+	offline := newTestClient(ms.URL)
+	offline.cache = fc
+	WithOffline()(offline)
+	WithEmptyRetry(2, 0)(offline)
+	WithClock(func() time.Time { return fetchedAt })(offline)
+	deps, err := offline.GetDepartures(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertTrue(t, len(deps) > 0)
+
+	// A later query for "now" gets the latest board of the station, raw
+	// output included
+	WithClock(func() time.Time { return fetchedAt.Add(20 * time.Minute) })(offline)
+	deps, err = offline.GetDepartures(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertTrue(t, len(deps) > 0)
+	_, err = offline.GetDeparturesRaw(context.Background(), req)
+	testutil.AssertNil(t, err)
+
+	// An explicit time must match what was cached
+	later := req
+	later.DateTime = fetchedAt.Add(20 * time.Minute)
+	_, err = offline.GetDepartures(context.Background(), later)
+	testutil.AssertTrue(t, errors.Is(err, ErrNotCached))
+
+	// Queries never run online fail with an actionable error
+	_, err = offline.GetArrivals(context.Background(), req)
+	testutil.AssertTrue(t, errors.Is(err, ErrNotCached))
+	testutil.AssertContains(t, err.Error(), "run this query while online first")
+	_, err = offline.LocateByIP(context.Background())
+	testutil.AssertTrue(t, errors.Is(err, ErrNotCached))
+	status, err := offline.Ping(context.Background())
+	testutil.AssertTrue(t, errors.Is(err, ErrNotCached))
+	testutil.AssertEqual(t, status, 0)
+	testutil.AssertEqual(t, ms.RequestCount(), 1)
+}
+
+func TestClient_WithMaxAge_NoAgeSupport(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleDepartureResponse))
+	})
+	defer ms.Close()
+
+	client := newTestClient(ms.URL)
+	client.cache = &mockCache{data: make(map[string][]byte)}
+	client.maxAge = time.Minute
+
+	req := StationBoardRequest{
+		EVA:       8000105,
+		StationID: "test",
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetDepartures(context.Background(), req)
+		testutil.AssertNil(t, err)
+	}
+	// Without entry ages the freshness guard cannot be honored from cache
+	testutil.AssertEqual(t, ms.RequestCount(), 2)
+}
+
+func TestClient_WithMemoryCache(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleDepartureResponse))
+	})
+	defer ms.Close()
+
+	client, _ := NewClient(WithMemoryCache(10, time.Minute), WithMaxAge(30*time.Second))
+	client.baseURL = ms.URL
+	req := StationBoardRequest{EVA: 8000105, StationID: "test"}
+
+	// The second call is served from memory, which also honors max age
+	for i := 0; i < 2; i++ {
+		_, err := client.GetDepartures(context.Background(), req)
+		testutil.AssertNil(t, err)
+	}
+	testutil.AssertEqual(t, ms.RequestCount(), 1)
+}
+
+func TestClient_WithRateLimit(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleDepartureResponse))
+	})
+	defer ms.Close()
+
+	// 20 requests per second after a burst of 2: the 3rd to 5th calls wait
+	// 50ms each
+	client, _ := NewClient(WithRateLimit(20, 2))
+	client.baseURL = ms.URL
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := client.GetDepartures(context.Background(), StationBoardRequest{EVA: 8000105, StationID: "test"})
+		testutil.AssertNil(t, err)
+	}
+	elapsed := time.Since(start)
+	testutil.AssertEqual(t, ms.RequestCount(), 5)
+	testutil.AssertTrue(t, elapsed >= 140*time.Millisecond)
+
+	// A context ending before the next slot fails without a request
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.GetDepartures(ctx, StationBoardRequest{EVA: 8000105, StationID: "test"})
+	testutil.AssertError(t, err)
+	testutil.AssertEqual(t, ms.RequestCount(), 5)
+}
+
+func TestClient_WithRateLimit_CacheHits(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleDepartureResponse))
+	})
+	defer ms.Close()
+
+	// One request per minute: only cache hits can follow the first call
+	client, _ := NewClient(WithMemoryCache(10, time.Minute), WithRateLimit(1.0/60, 1))
+	client.baseURL = ms.URL
+	req := StationBoardRequest{EVA: 8000105, StationID: "test"}
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		_, err := client.GetDepartures(context.Background(), req)
+		testutil.AssertNil(t, err)
+	}
+	testutil.AssertEqual(t, ms.RequestCount(), 1)
+	testutil.AssertTrue(t, time.Since(start) < time.Second)
+}
+
 func TestClient_ContextCancellation(t *testing.T) {
 	// Create a server that delays response
 	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
@@ -249,6 +494,28 @@ func TestGetArrivals_Success(t *testing.T) {
 	testutil.AssertTrue(t, len(arrivals) > 0)
 }
 
+func TestGetArrivals_MarksOrigin(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleArrivalResponse))
+	})
+	defer ms.Close()
+
+	client := newTestClient(ms.URL)
+
+	// The sample arrival's terminus is the board station itself
+	req := StationBoardRequest{
+		EVA:       8000105,
+		StationID: "A=1@O=Frankfurt(Main)Hbf@X=8663785@Y=50107145@U=80@L=8000105@",
+	}
+
+	arrivals, err := client.GetArrivals(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertLen(t, arrivals, 1)
+	testutil.AssertTrue(t, arrivals[0].StartsHere)
+	testutil.AssertFalse(t, arrivals[0].EndsHere)
+}
+
 func TestStationBoardRequest_DefaultValues(t *testing.T) {
 	req := StationBoardRequest{
 		EVA:       8000105,
@@ -280,9 +547,402 @@ func (m *mockCache) Set(key string, value []byte) error {
 	return nil
 }
 
+// Mock cache that reports a configurable age per entry
+type mockAgeCache struct {
+	mockCache
+	ages map[string]time.Duration
+}
+
+func (m *mockAgeCache) GetWithAge(key string) ([]byte, time.Duration, bool) {
+	val, ok := m.data[key]
+	return val, m.ages[key], ok
+}
+
 // Helper to create a client with custom base URL for testing
 func newTestClient(baseURL string) *Client {
 	client, _ := NewClient()
 	client.baseURL = baseURL
 	return client
 }
+
+func TestIsEUSummerTime(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"winter", time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC), false},
+		{"summer", time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC), true},
+		{"just before switch to summer", time.Date(2025, 3, 30, 0, 59, 0, 0, time.UTC), false},
+		{"switch to summer", time.Date(2025, 3, 30, 1, 0, 0, 0, time.UTC), true},
+		{"just before switch to winter", time.Date(2025, 10, 26, 0, 59, 0, 0, time.UTC), true},
+		{"switch to winter", time.Date(2025, 10, 26, 1, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.AssertEqual(t, isEUSummerTime(tt.t), tt.want)
+		})
+	}
+}
+
+func TestLoadTimezone(t *testing.T) {
+	tz, fallback := loadTimezone(time.Now())
+	testutil.AssertTrue(t, tz != nil)
+	if !fallback {
+		testutil.AssertEqual(t, tz.String(), "Europe/Berlin")
+	}
+}
+
+func TestGetDepartures_Window(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleDepartureResponse))
+	})
+	defer ms.Close()
+
+	client := newTestClient(ms.URL)
+	queryTime := time.Date(2024, 1, 1, 14, 0, 0, 0, client.Timezone())
+
+	tests := []struct {
+		name   string
+		window time.Duration
+		want   int
+	}{
+		{"no window", 0, 1},
+		{"window excludes later departure", 20 * time.Minute, 0},
+		{"window includes departure", 40 * time.Minute, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := StationBoardRequest{
+				EVA:       8000105,
+				StationID: "test",
+				DateTime:  queryTime,
+				Window:    tt.window,
+			}
+			departures, err := client.GetDepartures(context.Background(), req)
+			testutil.AssertNil(t, err)
+			testutil.AssertLen(t, departures, tt.want)
+		})
+	}
+}
+
+func TestLocateByIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+		wantLat float64
+	}{
+		{"success", http.StatusOK, `{"latitude": 50.9375, "longitude": 6.9603, "city": "Köln"}`, false, 50.9375},
+		{"no position", http.StatusOK, `{"error": true, "reason": "RateLimited"}`, true, 0},
+		{"http error", http.StatusTooManyRequests, `{}`, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer ms.Close()
+
+			client := newTestClient(ms.URL)
+			client.geoIPURL = ms.URL
+
+			loc, err := client.LocateByIP(context.Background())
+			if tt.wantErr {
+				testutil.AssertError(t, err)
+				return
+			}
+			testutil.AssertNil(t, err)
+			testutil.AssertFloatEqual(t, loc.Latitude, tt.wantLat, 0.0001)
+			testutil.AssertEqual(t, loc.City, "Köln")
+		})
+	}
+}
+
+func TestClient_WithHeader(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		testutil.AssertEqual(t, r.Header.Get("Referer"), "https://example.org/")
+		testutil.AssertEqual(t, r.Header.Get("X-Debug"), "1")
+		testutil.AssertEqual(t, r.Header.Get("Origin"), "")
+		testutil.AssertEqual(t, r.Header.Get("Accept"), "application/json, text/plain, */*")
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleLocationResponse))
+	})
+	defer ms.Close()
+
+	client, _ := NewClient(
+		WithHeader("Referer", "https://example.org/"),
+		WithHeader("x-debug", "1"),
+		WithHeader("Origin", ""),
+	)
+	client.baseURL = ms.URL
+
+	_, err := client.SearchLocations(context.Background(), "Frankfurt")
+	testutil.AssertNil(t, err)
+}
+
+func TestClient_WithEmptyRetry(t *testing.T) {
+	var calls int
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+		if calls == 1 {
+			_, _ = w.Write([]byte(`{"entries": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(testutil.SampleDepartureResponse))
+	})
+	defer ms.Close()
+
+	client, _ := NewClient(WithEmptyRetry(2, time.Millisecond))
+	client.baseURL = ms.URL
+	client.cache = &mockCache{data: make(map[string][]byte)}
+	req := StationBoardRequest{EVA: 8000105, StationID: "test"}
+
+	// The empty board is refetched past the cache
+	deps, err := client.GetDepartures(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertTrue(t, len(deps) > 0)
+	testutil.AssertEqual(t, ms.RequestCount(), 2)
+}
+
+func TestClient_WithEmptyRetry_GenuinelyEmpty(t *testing.T) {
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"entries": []}`))
+	})
+	defer ms.Close()
+
+	// Retries are capped
+	client, _ := NewClient(WithEmptyRetry(10, time.Millisecond))
+	client.baseURL = ms.URL
+	req := StationBoardRequest{EVA: 8000105, StationID: "test"}
+
+	arrs, err := client.GetArrivals(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertLen(t, arrs, 0)
+	testutil.AssertEqual(t, ms.RequestCount(), 1+MaxEmptyRetries)
+
+	// Cancellation ends the wait
+	client, _ = NewClient(WithEmptyRetry(1, time.Hour))
+	client.baseURL = ms.URL
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.GetDepartures(ctx, req)
+	testutil.AssertError(t, err)
+	testutil.AssertTrue(t, errors.Is(err, ErrTimeout))
+}
+
+func TestClient_WithRetry(t *testing.T) {
+	var calls int
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleDepartureResponse))
+	})
+	defer ms.Close()
+
+	client, _ := NewClient(WithRetry(3, time.Millisecond))
+	client.baseURL = ms.URL
+	client.cache = &mockCache{data: make(map[string][]byte)}
+	req := StationBoardRequest{EVA: 8000105, StationID: "test"}
+
+	deps, err := client.GetDepartures(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertTrue(t, len(deps) > 0)
+	testutil.AssertEqual(t, ms.RequestCount(), 3)
+}
+
+func TestClient_WithRetry_Failures(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	})
+	defer ms.Close()
+	req := StationBoardRequest{EVA: 8000105, StationID: "test"}
+
+	// All attempts fail: the last API error is returned
+	client, _ := NewClient(WithRetry(3, time.Millisecond))
+	client.baseURL = ms.URL
+	_, err := client.GetDepartures(context.Background(), req)
+	var apiErr *APIError
+	testutil.AssertTrue(t, errors.As(err, &apiErr))
+	testutil.AssertEqual(t, apiErr.StatusCode, http.StatusServiceUnavailable)
+	testutil.AssertEqual(t, ms.RequestCount(), 3)
+
+	// Client errors fail fast
+	status = http.StatusNotFound
+	_, err = client.GetDepartures(context.Background(), req)
+	testutil.AssertTrue(t, errors.Is(err, ErrNotFound))
+	testutil.AssertEqual(t, ms.RequestCount(), 4)
+
+	// No retry when the backoff would outlast the deadline
+	status = http.StatusTooManyRequests
+	client, _ = NewClient(WithRetry(3, time.Hour))
+	client.baseURL = ms.URL
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = client.GetDepartures(ctx, req)
+	testutil.AssertTrue(t, errors.As(err, &apiErr))
+	testutil.AssertEqual(t, apiErr.StatusCode, http.StatusTooManyRequests)
+	testutil.AssertEqual(t, ms.RequestCount(), 5)
+}
+
+func TestClient_Ping(t *testing.T) {
+	status := http.StatusOK
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		testutil.AssertContains(t, r.URL.Path, EndpointLocations)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(testutil.SampleLocationResponse))
+	})
+	defer ms.Close()
+
+	client, _ := NewClient(WithRetry(3, time.Millisecond))
+	client.baseURL = ms.URL
+	client.cache = &mockCache{data: make(map[string][]byte)}
+
+	// The probe always goes to the API
+	for i := 0; i < 2; i++ {
+		code, err := client.Ping(context.Background())
+		testutil.AssertNil(t, err)
+		testutil.AssertEqual(t, code, http.StatusOK)
+	}
+	testutil.AssertEqual(t, ms.RequestCount(), 2)
+
+	// Failures are reported once, without retries
+	status = http.StatusServiceUnavailable
+	code, err := client.Ping(context.Background())
+	testutil.AssertError(t, err)
+	testutil.AssertEqual(t, code, http.StatusServiceUnavailable)
+	testutil.AssertEqual(t, ms.RequestCount(), 3)
+
+	client.baseURL = "http://127.0.0.1:1"
+	code, err = client.Ping(context.Background())
+	testutil.AssertError(t, err)
+	testutil.AssertEqual(t, code, 0)
+}
+
+func TestGetConnections_Success(t *testing.T) {
+	var body map[string]any
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		testutil.AssertEqual(t, r.Method, "POST")
+		testutil.AssertContains(t, r.URL.Path, "/angebote/fahrplan")
+		testutil.AssertEqual(t, r.Header.Get("Content-Type"), "application/json")
+		testutil.AssertNil(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleConnectionResponse))
+	})
+	defer ms.Close()
+
+	client := newTestClient(ms.URL)
+	client.cache = &mockCache{data: make(map[string][]byte)}
+	req := ConnectionRequest{
+		FromID:         "A=1@O=Köln Hbf@L=8000207@",
+		ToID:           "A=1@O=Frankfurt(Main)Hbf@L=8000105@",
+		DateTime:       time.Date(2025, 12, 28, 12, 0, 0, 0, client.Timezone()),
+		ModesOfTransit: []string{"ICE"},
+	}
+
+	conns, err := client.GetConnections(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertLen(t, conns, 1)
+	testutil.AssertLen(t, conns[0].Legs, 3)
+	testutil.AssertEqual(t, conns[0].Transfers, 1)
+
+	testutil.AssertEqual(t, body["abfahrtsHalt"], "A=1@O=Köln Hbf@L=8000207@")
+	testutil.AssertEqual(t, body["ankunftsHalt"], "A=1@O=Frankfurt(Main)Hbf@L=8000105@")
+	testutil.AssertEqual(t, body["anfrageZeitpunkt"], "2025-12-28T12:00:00")
+	testutil.AssertEqual(t, body["produktgattungen"].([]any)[0], "ICE")
+
+	// Same search is served from the cache, a different one is not
+	_, err = client.GetConnections(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, ms.RequestCount(), 1)
+
+	req.DateTime = req.DateTime.Add(time.Hour)
+	_, _ = client.GetConnections(context.Background(), req)
+	testutil.AssertEqual(t, ms.RequestCount(), 2)
+}
+
+func TestGetConnections_MissingStation(t *testing.T) {
+	client, _ := NewClient()
+	_, err := client.GetConnections(context.Background(), ConnectionRequest{FromID: "A=1@O=Köln Hbf@"})
+	testutil.AssertError(t, err)
+}
+
+func TestClient_WithPersistentCookies(t *testing.T) {
+	var got []string
+	ms := testutil.NewMockServer(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("session"); err == nil {
+			got = append(got, c.Value)
+		} else {
+			got = append(got, "")
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/", MaxAge: 3600})
+		http.SetCookie(w, &http.Cookie{Name: "stale", Value: "x", Path: "/", MaxAge: -1})
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(testutil.SampleDepartureResponse))
+	})
+	defer ms.Close()
+
+	path := filepath.Join(t.TempDir(), "moko", "cookies.json")
+	req := StationBoardRequest{EVA: 8000105, StationID: "test"}
+
+	// First invocation receives the cookie and saves it on Close
+	client, err := NewClient(WithPersistentCookies(path))
+	testutil.AssertNil(t, err)
+	client.baseURL = ms.URL
+	_, err = client.GetDepartures(context.Background(), req)
+	testutil.AssertNil(t, err)
+	testutil.AssertNil(t, client.Close())
+
+	data, err := os.ReadFile(path)
+	testutil.AssertNil(t, err)
+	testutil.AssertContains(t, string(data), "abc123")
+	testutil.AssertFalse(t, strings.Contains(string(data), "stale"))
+	info, err := os.Stat(path)
+	testutil.AssertNil(t, err)
+	if runtime.GOOS != "windows" {
+		testutil.AssertEqual(t, info.Mode().Perm(), os.FileMode(0600))
+	}
+
+	// A new client reloads it and sends it with its first request
+	client, err = NewClient(WithPersistentCookies(path))
+	testutil.AssertNil(t, err)
+	client.baseURL = ms.URL
+	_, err = client.GetDepartures(context.Background(), req)
+	testutil.AssertNil(t, err)
+
+	testutil.AssertLen(t, got, 2)
+	testutil.AssertEqual(t, got[0], "")
+	testutil.AssertEqual(t, got[1], "abc123")
+
+	// Clients without persistent cookies have nothing to save
+	plain, _ := NewClient()
+	testutil.AssertNil(t, plain.Close())
+}
+
+func TestClient_WithPersistentCookies_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	testutil.AssertNil(t, os.WriteFile(path, []byte("{not json"), 0600))
+
+	client, err := NewClient(WithPersistentCookies(path))
+	testutil.AssertNil(t, err)
+	testutil.AssertNil(t, client.Close())
+
+	data, err := os.ReadFile(path)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, string(data), "{}")
+}
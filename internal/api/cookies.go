@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// persistentJar is a cookie jar that remembers every cookie it was given so
+// that they can be written to a file. The standard cookiejar.Jar does the
+// matching; it just can't list its contents.
+type persistentJar struct {
+	*cookiejar.Jar
+	path string
+
+	mu      sync.Mutex
+	cookies map[string][]*http.Cookie // by host the cookies were set for
+}
+
+// newPersistentJar creates a jar preloaded with the unexpired cookies stored
+// at path. A missing or unreadable file starts an empty session.
+func newPersistentJar(path string, now time.Time) (*persistentJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cookie jar: %w", err)
+	}
+	j := &persistentJar{Jar: jar, path: path, cookies: make(map[string][]*http.Cookie)}
+
+	// #nosec G304 -- path is the client's own cookie file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return j, nil
+		}
+		return j, fmt.Errorf("failed to read cookies: %w", err)
+	}
+	var stored map[string][]*http.Cookie
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return j, fmt.Errorf("invalid cookie file %s: %w", path, err)
+	}
+	for host, cookies := range stored {
+		var live []*http.Cookie
+		for _, cookie := range cookies {
+			if cookie != nil && !cookieExpired(cookie, now) {
+				live = append(live, cookie)
+			}
+		}
+		if len(live) > 0 {
+			j.SetCookies(&url.URL{Scheme: "https", Host: host, Path: "/"}, live)
+		}
+	}
+	return j, nil
+}
+
+// SetCookies stores the cookies in the jar and remembers them for saving.
+// Max-Age is turned into an absolute expiry so a reload doesn't extend it.
+func (j *persistentJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.Jar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	for _, cookie := range cookies {
+		c := *cookie
+		if c.MaxAge > 0 {
+			c.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+			c.MaxAge = 0
+		}
+		j.cookies[u.Host] = replaceCookie(j.cookies[u.Host], &c)
+	}
+}
+
+// save writes the unexpired cookies to the jar's file, readable only by the
+// user since they identify the session
+func (j *persistentJar) save(now time.Time) error {
+	j.mu.Lock()
+	stored := make(map[string][]*http.Cookie)
+	for host, cookies := range j.cookies {
+		for _, cookie := range cookies {
+			if !cookieExpired(cookie, now) {
+				stored[host] = append(stored[host], cookie)
+			}
+		}
+	}
+	j.mu.Unlock()
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cookies: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0700); err != nil {
+		return fmt.Errorf("failed to create cookie directory: %w", err)
+	}
+	if err := os.WriteFile(j.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cookies: %w", err)
+	}
+	return nil
+}
+
+// replaceCookie returns cookies with c added, replacing an earlier cookie of
+// the same name, domain and path
+func replaceCookie(cookies []*http.Cookie, c *http.Cookie) []*http.Cookie {
+	for i, old := range cookies {
+		if old.Name == c.Name && old.Domain == c.Domain && old.Path == c.Path {
+			cookies[i] = c
+			return cookies
+		}
+	}
+	return append(cookies, c)
+}
+
+// cookieExpired reports whether a cookie has expired or was deleted by the
+// server (negative Max-Age). Session cookies without expiry are kept.
+func cookieExpired(c *http.Cookie, now time.Time) bool {
+	return c.MaxAge < 0 || (!c.Expires.IsZero() && !c.Expires.After(now))
+}
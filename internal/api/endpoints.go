@@ -27,6 +27,10 @@ const (
 	// EndpointFormation returns train carriage formation
 	// Required params: administrationId, category, date, evaNumber, number, time
 	EndpointFormation = "/reisebegleitung/wagenreihung/vehicle-sequence"
+
+	// EndpointConnections searches for trips between two locations (POST)
+	// Required body: abfahrtsHalt, ankunftsHalt, anfrageZeitpunkt, ankunftSuche, produktgattungen
+	EndpointConnections = "/angebote/fahrplan"
 )
 
 // ModesOfTransit contains all supported transport modes
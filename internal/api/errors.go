@@ -19,8 +19,15 @@ var (
 	// ErrTimeout indicates the request timed out
 	ErrTimeout = errors.New("request timed out")
 
+	// ErrBlocked indicates bahn.de refused the request as too frequent or
+	// automated (HTTP 403 or 429)
+	ErrBlocked = errors.New("request blocked")
+
 	// ErrNoResults indicates no results were found
 	ErrNoResults = errors.New("no results found")
+
+	// ErrNotCached indicates an offline request had no cached response
+	ErrNotCached = errors.New("no cached data")
 )
 
 // APIError represents an error returned by the bahn.de API
@@ -47,10 +54,17 @@ func (e *APIError) Is(target error) bool {
 		return e.StatusCode >= 500
 	case ErrInvalidRequest:
 		return e.StatusCode == 400
+	case ErrBlocked:
+		return e.StatusCode == 403 || e.StatusCode == 429
 	}
 	return false
 }
 
+// notCachedError reports that reqURL could not be served offline
+func notCachedError(reqURL string) error {
+	return fmt.Errorf("offline: %w for %s; run this query while online first", ErrNotCached, extractEndpoint(reqURL))
+}
+
 // NewAPIError creates a new API error
 func NewAPIError(statusCode int, status, endpoint string) *APIError {
 	return &APIError{
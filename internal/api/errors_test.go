@@ -71,6 +71,24 @@ func TestAPIError_Is(t *testing.T) {
 			target:    ErrInvalidRequest,
 			wantMatch: true,
 		},
+		{
+			name:      "429 matches ErrBlocked",
+			err:       &APIError{StatusCode: 429},
+			target:    ErrBlocked,
+			wantMatch: true,
+		},
+		{
+			name:      "403 matches ErrBlocked",
+			err:       &APIError{StatusCode: 403},
+			target:    ErrBlocked,
+			wantMatch: true,
+		},
+		{
+			name:      "503 does not match ErrBlocked",
+			err:       &APIError{StatusCode: 503},
+			target:    ErrBlocked,
+			wantMatch: false,
+		},
 		{
 			name:      "404 does not match ErrServerError",
 			err:       &APIError{StatusCode: 404},
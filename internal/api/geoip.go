@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// GeoIPURL is the IP geolocation service used to approximate the user's position
+const GeoIPURL = "https://ipapi.co/json/"
+
+// IPLocation is an approximate position derived from the public IP address
+type IPLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	City      string  `json:"city"`
+}
+
+// LocateByIP approximates the current position via IP geolocation. The result
+// is coarse (typically city level) and is never cached, so it fails offline.
+func (c *Client) LocateByIP(ctx context.Context) (*IPLocation, error) {
+	if c.offline {
+		return nil, fmt.Errorf("offline: %w: IP geolocation is never cached", ErrNotCached)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.geoIPURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req) //nolint:gosec // URL is the fixed GeoIPURL constant
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %w", ErrTimeout, ctx.Err())
+		}
+		return nil, fmt.Errorf("geolocation request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, NewAPIError(resp.StatusCode, resp.Status, extractEndpoint(c.geoIPURL))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var loc IPLocation
+	if err := json.Unmarshal(body, &loc); err != nil {
+		return nil, fmt.Errorf("failed to parse geolocation response: %w", err)
+	}
+	if loc.Latitude == 0 && loc.Longitude == 0 {
+		return nil, fmt.Errorf("geolocation: %w", ErrNoResults)
+	}
+
+	return &loc, nil
+}
@@ -19,6 +19,7 @@ type FileCache struct {
 type cacheEntry struct {
 	Data      []byte    `json:"data"`
 	ExpiresAt time.Time `json:"expires_at"`
+	StoredAt  time.Time `json:"stored_at,omitempty"`
 }
 
 // NewFileCache creates a new file cache
@@ -58,35 +59,74 @@ func (c *FileCache) keyToFilename(key string) string {
 
 // Get retrieves a value from the cache
 func (c *FileCache) Get(key string) ([]byte, bool) {
+	data, _, ok := c.GetWithAge(key)
+	return data, ok
+}
+
+// GetWithAge retrieves a value from the cache along with the time elapsed
+// since it was stored. Entries written before StoredAt was recorded have
+// their age derived from the expiry and the configured TTL.
+func (c *FileCache) GetWithAge(key string) ([]byte, time.Duration, bool) {
+	entry, ok := c.read(key)
+	if !ok {
+		return nil, 0, false
+	}
+
+	// Check if expired
+	now := time.Now()
+	if now.After(entry.ExpiresAt) {
+		_ = os.Remove(c.keyToFilename(key))
+		return nil, 0, false
+	}
+
+	return entry.Data, c.age(entry, now), true
+}
+
+// GetStale is GetWithAge without the expiry check: expired entries are
+// returned and kept, so that offline use can show data of any age.
+func (c *FileCache) GetStale(key string) ([]byte, time.Duration, bool) {
+	entry, ok := c.read(key)
+	if !ok {
+		return nil, 0, false
+	}
+	return entry.Data, c.age(entry, time.Now()), true
+}
+
+// read loads the entry stored for key, removing it when it is invalid
+func (c *FileCache) read(key string) (cacheEntry, bool) {
 	filename := c.keyToFilename(key)
 
 	// #nosec G304 -- filename is derived from hash of cache key, not user input
 	data, err := os.ReadFile(filename)
 	if err != nil {
-		return nil, false
+		return cacheEntry{}, false
 	}
 
 	var entry cacheEntry
 	if err := json.Unmarshal(data, &entry); err != nil {
 		// Invalid cache entry, remove it
 		_ = os.Remove(filename)
-		return nil, false
+		return cacheEntry{}, false
 	}
+	return entry, true
+}
 
-	// Check if expired
-	if time.Now().After(entry.ExpiresAt) {
-		_ = os.Remove(filename)
-		return nil, false
+// age returns the time elapsed between storing entry and now
+func (c *FileCache) age(entry cacheEntry, now time.Time) time.Duration {
+	storedAt := entry.StoredAt
+	if storedAt.IsZero() {
+		storedAt = entry.ExpiresAt.Add(-c.ttl)
 	}
-
-	return entry.Data, true
+	return max(now.Sub(storedAt), 0)
 }
 
 // Set stores a value in the cache
 func (c *FileCache) Set(key string, value []byte) error {
+	now := time.Now()
 	entry := cacheEntry{
 		Data:      value,
-		ExpiresAt: time.Now().Add(c.ttl),
+		ExpiresAt: now.Add(c.ttl),
+		StoredAt:  now,
 	}
 
 	data, err := json.Marshal(entry)
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -88,6 +89,100 @@ func TestFileCache_Expiration(t *testing.T) {
 	}
 }
 
+func TestFileCache_GetWithAge(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(dir, 60*time.Second)
+	if err != nil {
+		t.Fatalf("NewFileCache() error = %v", err)
+	}
+
+	key := "https://example.com/api/age"
+	if err := cache.Set(key, []byte("fresh")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	data, age, ok := cache.GetWithAge(key)
+	if !ok || string(data) != "fresh" {
+		t.Fatalf("GetWithAge() = %q, %v, want fresh entry", data, ok)
+	}
+	if age < 0 || age > 5*time.Second {
+		t.Errorf("GetWithAge() age = %v, want close to zero", age)
+	}
+
+	// Entry stored 40s ago, still within TTL
+	entry := cacheEntry{
+		Data:      []byte("aged"),
+		ExpiresAt: time.Now().Add(20 * time.Second),
+		StoredAt:  time.Now().Add(-40 * time.Second),
+	}
+	raw, _ := json.Marshal(entry)
+	if err := os.WriteFile(cache.keyToFilename(key), raw, 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	_, age, ok = cache.GetWithAge(key)
+	if !ok {
+		t.Fatal("GetWithAge() returned false for unexpired entry")
+	}
+	if age < 39*time.Second || age > 45*time.Second {
+		t.Errorf("GetWithAge() age = %v, want ~40s", age)
+	}
+
+	// Legacy entry without StoredAt derives its age from the TTL
+	raw = []byte(`{"data":"bGVnYWN5","expires_at":"` + time.Now().Add(30*time.Second).Format(time.RFC3339Nano) + `"}`)
+	if err := os.WriteFile(cache.keyToFilename(key), raw, 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	data, age, ok = cache.GetWithAge(key)
+	if !ok || string(data) != "legacy" {
+		t.Fatalf("GetWithAge() = %q, %v, want legacy entry", data, ok)
+	}
+	if age < 29*time.Second || age > 35*time.Second {
+		t.Errorf("GetWithAge() age = %v, want ~30s", age)
+	}
+}
+
+func TestFileCache_GetStale(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(dir, 60*time.Second)
+	if err != nil {
+		t.Fatalf("NewFileCache() error = %v", err)
+	}
+
+	key := "https://example.com/api/stale"
+	if _, _, ok := cache.GetStale(key); ok {
+		t.Error("GetStale() returned true for missing key")
+	}
+
+	// Entry stored an hour ago that expired long since
+	entry := cacheEntry{
+		Data:      []byte("stale"),
+		ExpiresAt: time.Now().Add(-59 * time.Minute),
+		StoredAt:  time.Now().Add(-time.Hour),
+	}
+	raw, _ := json.Marshal(entry)
+	if err := os.WriteFile(cache.keyToFilename(key), raw, 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	data, age, ok := cache.GetStale(key)
+	if !ok || string(data) != "stale" {
+		t.Fatalf("GetStale() = %q, %v, want stale entry", data, ok)
+	}
+	if age < 59*time.Minute || age > 61*time.Minute {
+		t.Errorf("GetStale() age = %v, want ~1h", age)
+	}
+
+	// The entry is kept for later offline reads, while Get drops it
+	if _, _, ok := cache.GetStale(key); !ok {
+		t.Error("GetStale() removed the expired entry")
+	}
+	if _, ok := cache.Get(key); ok {
+		t.Error("Get() returned true for expired key")
+	}
+	if _, _, ok := cache.GetStale(key); ok {
+		t.Error("GetStale() returned an entry Get() removed")
+	}
+}
+
 func TestFileCache_HashKey(t *testing.T) {
 	dir := t.TempDir()
 	cache, err := NewFileCache(dir, 60*time.Second)
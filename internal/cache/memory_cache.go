@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// MemoryCache implements an in-memory cache with TTL and least recently used
+// eviction. It is safe for concurrent use.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	order      *list.List // front is the most recently used entry
+	entries    map[string]*list.Element
+}
+
+// memoryEntry is the value of an element in MemoryCache.order
+type memoryEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time
+	storedAt  time.Time
+}
+
+// NewMemoryCache creates a new memory cache holding at most maxEntries
+// entries; a maxEntries of 0 or less means no limit
+func NewMemoryCache(maxEntries int, ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get retrieves a value from the cache
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	data, _, ok := c.GetWithAge(key)
+	return data, ok
+}
+
+// GetWithAge retrieves a value from the cache along with the time elapsed
+// since it was stored. A hit marks the entry as most recently used.
+func (c *MemoryCache) GetWithAge(key string) ([]byte, time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, 0, false
+	}
+	entry := elem.Value.(*memoryEntry)
+
+	// Check if expired
+	now := time.Now()
+	if now.After(entry.expiresAt) {
+		c.remove(elem)
+		return nil, 0, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.data, max(now.Sub(entry.storedAt), 0), true
+}
+
+// Set stores a value in the cache, evicting the least recently used entry
+// when the cache is full
+func (c *MemoryCache) Set(key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.data = value
+		entry.expiresAt = now.Add(c.ttl)
+		entry.storedAt = now
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryEntry{
+		key:       key,
+		data:      value,
+		expiresAt: now.Add(c.ttl),
+		storedAt:  now,
+	})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Len returns the number of entries, including expired ones not yet removed
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Clear removes all cache entries
+func (c *MemoryCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.entries)
+	return nil
+}
+
+// remove deletes elem from the cache; the caller holds c.mu
+func (c *MemoryCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*memoryEntry).key)
+}
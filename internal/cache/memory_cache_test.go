@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_SetAndGet(t *testing.T) {
+	cache := NewMemoryCache(10, time.Minute)
+
+	if err := cache.Set("key", []byte("value")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	got, age, ok := cache.GetWithAge("key")
+	if !ok {
+		t.Fatal("GetWithAge() returned false, want true")
+	}
+	if string(got) != "value" {
+		t.Errorf("GetWithAge() = %q, want %q", got, "value")
+	}
+	if age < 0 || age > time.Second {
+		t.Errorf("GetWithAge() age = %v, want about 0", age)
+	}
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Get() returned true for non-existent key")
+	}
+}
+
+func TestMemoryCache_EvictionOrder(t *testing.T) {
+	cache := NewMemoryCache(3, time.Minute)
+	for _, key := range []string{"a", "b", "c"} {
+		_ = cache.Set(key, []byte(key))
+	}
+
+	// Reading "a" makes "b" the least recently used entry
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Get(a) returned false, want true")
+	}
+	_ = cache.Set("d", []byte("d"))
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Get(b) returned true, want it evicted")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Get(%s) returned false, want true", key)
+		}
+	}
+
+	// Overwriting an entry refreshes it without growing the cache
+	_ = cache.Set("a", []byte("A"))
+	_ = cache.Set("e", []byte("e"))
+	if cache.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", cache.Len())
+	}
+	if got, _ := cache.Get("a"); string(got) != "A" {
+		t.Errorf("Get(a) = %q, want %q", got, "A")
+	}
+	if _, ok := cache.Get("c"); ok {
+		t.Error("Get(c) returned true, want it evicted")
+	}
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	cache := NewMemoryCache(10, 20*time.Millisecond)
+	_ = cache.Set("key", []byte("value"))
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Get() returned true for expired entry")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Len() = %d, want expired entry removed", cache.Len())
+	}
+}
+
+func TestMemoryCache_Clear(t *testing.T) {
+	cache := NewMemoryCache(0, time.Minute)
+	for i := range 100 {
+		_ = cache.Set(fmt.Sprint(i), []byte("value"))
+	}
+	if cache.Len() != 100 {
+		t.Errorf("Len() = %d, want 100 without a limit", cache.Len())
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, ok := cache.Get("1"); ok {
+		t.Error("Get() returned true after Clear()")
+	}
+}
+
+func TestMemoryCache_Concurrent(t *testing.T) {
+	cache := NewMemoryCache(50, time.Minute)
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				key := fmt.Sprintf("key-%d", (g*200+i)%80)
+				_ = cache.Set(key, []byte(key))
+				if got, ok := cache.Get(key); ok && string(got) != key {
+					t.Errorf("Get(%s) = %q", key, got)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if cache.Len() > 50 {
+		t.Errorf("Len() = %d, want at most 50", cache.Len())
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config holds user settings read from the config file
+type Config struct {
+	// Home is the default coordinate used by `nearby --here`
+	Home *Coordinate `json:"home,omitempty"`
+
+	// HomeEVA is the EVA number of the home station that `--highlight-home`
+	// looks for on boards
+	HomeEVA int64 `json:"home_eva,omitempty"`
+
+	// NotifyCmd is a shell command run after each successful board or
+	// journey fetch, with the result as JSON on stdin
+	NotifyCmd string `json:"notify_cmd,omitempty"`
+	// NotifyTimeout limits how long NotifyCmd may run, in seconds
+	NotifyTimeout int `json:"notify_timeout,omitempty"`
+
+	// DefaultCommand is run by bare `moko` in a terminal instead of the TUI,
+	// e.g. "departures @home"
+	DefaultCommand string `json:"default_command,omitempty"`
+
+	// Theme is the color theme used unless --theme is given
+	Theme string `json:"theme,omitempty"`
+
+	// TerminalTitle makes the TUI show the station and next departure in
+	// the terminal window title
+	TerminalTitle bool `json:"terminal_title,omitempty"`
+
+	// Markers overrides the journey view's stop markers and highlight
+	// colors of the theme
+	Markers *Markers `json:"markers,omitempty"`
+}
+
+// Markers are the glyphs and background colors (ANSI color numbers) marking
+// stops in the TUI journey view. Empty fields keep the theme's choice.
+type Markers struct {
+	Current       string `json:"current,omitempty"`
+	Scroll        string `json:"scroll,omitempty"`
+	Bookmark      string `json:"bookmark,omitempty"`
+	CurrentColor  string `json:"current_color,omitempty"`
+	BoardColor    string `json:"board_color,omitempty"`
+	BookmarkColor string `json:"bookmark_color,omitempty"`
+}
+
+// Coordinate is a geographic position in decimal degrees
+type Coordinate struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+}
+
+// DefaultPath returns the default config file location
+func DefaultPath() string {
+	// Check XDG_CONFIG_HOME first
+	if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
+		return filepath.Join(xdgConfig, "moko", "config.json")
+	}
+
+	// Fall back to ~/.config/moko
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "moko-config", "config.json")
+	}
+
+	return filepath.Join(home, ".config", "moko", "config.json")
+}
+
+// Load reads the config file at path. A missing file yields an empty config.
+func Load(path string) (*Config, error) {
+	// #nosec G304 -- path is the user's own config file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
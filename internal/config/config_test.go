@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		content  string
+		wantErr  bool
+		wantHome *Coordinate
+	}{
+		{"missing file", "", false, nil},
+		{"home coordinate", `{"home": {"lat": 50.943, "lon": 6.959}}`, false, &Coordinate{Latitude: 50.943, Longitude: 6.959}},
+		{"empty object", `{}`, false, nil},
+		{"invalid json", `{`, true, nil},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "config"+string(rune('a'+i))+".json")
+			if tt.content != "" {
+				testutil.AssertNil(t, os.WriteFile(path, []byte(tt.content), 0600))
+			}
+
+			cfg, err := Load(path)
+			if tt.wantErr {
+				testutil.AssertError(t, err)
+				return
+			}
+			testutil.AssertNil(t, err)
+			if tt.wantHome == nil {
+				testutil.AssertTrue(t, cfg.Home == nil)
+				return
+			}
+			testutil.AssertTrue(t, cfg.Home != nil)
+			testutil.AssertEqual(t, *cfg.Home, *tt.wantHome)
+		})
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	testutil.AssertEqual(t, DefaultPath(), filepath.Join("/tmp/xdg", "moko", "config.json"))
+}
+
+func TestLoad_NotifyCmd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	testutil.AssertNil(t, os.WriteFile(path, []byte(`{"notify_cmd": "notify-send moko", "notify_timeout": 5}`), 0600))
+
+	cfg, err := Load(path)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, cfg.NotifyCmd, "notify-send moko")
+	testutil.AssertEqual(t, cfg.NotifyTimeout, 5)
+}
+
+func TestLoad_DefaultCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	testutil.AssertNil(t, os.WriteFile(path, []byte(`{"default_command": "departures @home --via"}`), 0600))
+
+	cfg, err := Load(path)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, cfg.DefaultCommand, "departures @home --via")
+}
+
+func TestLoad_Theme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	testutil.AssertNil(t, os.WriteFile(path, []byte(`{"theme": "mono"}`), 0600))
+
+	cfg, err := Load(path)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, cfg.Theme, "mono")
+}
+
+func TestLoad_TerminalTitle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	testutil.AssertNil(t, os.WriteFile(path, []byte(`{"terminal_title": true}`), 0600))
+
+	cfg, err := Load(path)
+	testutil.AssertNil(t, err)
+	testutil.AssertTrue(t, cfg.TerminalTitle)
+}
+
+func TestLoad_HomeEVA(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	testutil.AssertNil(t, os.WriteFile(path, []byte(`{"home_eva": 8000044}`), 0600))
+
+	cfg, err := Load(path)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, cfg.HomeEVA, int64(8000044))
+}
+
+func TestLoad_Markers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"markers": {"current": "◆", "board_color": "4"}}`
+	testutil.AssertNil(t, os.WriteFile(path, []byte(data), 0600))
+
+	cfg, err := Load(path)
+	testutil.AssertNil(t, err)
+	testutil.AssertTrue(t, cfg.Markers != nil)
+	testutil.AssertEqual(t, *cfg.Markers, Markers{Current: "◆", BoardColor: "4"})
+}
@@ -0,0 +1,144 @@
+package favorites
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrExists indicates a favorite of the same name is already stored
+	ErrExists = errors.New("favorite already exists")
+
+	// ErrNotFound indicates no favorite of the given name is stored
+	ErrNotFound = errors.New("favorite not found")
+)
+
+// Prefix marks a station argument as a favorite name, as in "@home"
+const Prefix = "@"
+
+// nameRegex limits names to letters, digits and "_.-" so they can be typed
+// without quoting
+var nameRegex = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N}_.-]*$`)
+
+// Favorite is a named station
+type Favorite struct {
+	Name    string `json:"name"`
+	Station string `json:"station"` // EVA:ID as accepted by the board commands
+}
+
+// Favorites is the set of saved stations. Names are unique regardless of
+// case.
+type Favorites struct {
+	Entries []Favorite `json:"favorites"`
+}
+
+// DefaultPath returns the default favorites file location
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "moko-config", "favorites.json")
+	}
+	return filepath.Join(dir, "moko", "favorites.json")
+}
+
+// Load reads the favorites file at path. A missing file yields no favorites.
+func Load(path string) (*Favorites, error) {
+	// #nosec G304 -- path is the user's own favorites file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Favorites{}, nil
+		}
+		return nil, fmt.Errorf("failed to read favorites: %w", err)
+	}
+
+	var favs Favorites
+	if err := json.Unmarshal(data, &favs); err != nil {
+		return nil, fmt.Errorf("invalid favorites file %s: %w", path, err)
+	}
+	return &favs, nil
+}
+
+// Save writes the favorites to path, sorted by name, creating its directory
+// if needed
+func (f *Favorites) Save(path string) error {
+	slices.SortFunc(f.Entries, func(a, b Favorite) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create favorites directory: %w", err)
+	}
+	// Use 0600 so only the owner can read the saved stations
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// ValidateName checks that name can be used for a favorite
+func ValidateName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("invalid favorite name %q: use letters, digits, '_', '.' and '-'", name)
+	}
+	return nil
+}
+
+// Get returns the favorite called name, ignoring case
+func (f *Favorites) Get(name string) (Favorite, bool) {
+	if i := f.index(name); i >= 0 {
+		return f.Entries[i], true
+	}
+	return Favorite{}, false
+}
+
+// Add stores station under name. It fails with ErrExists when the name is
+// taken, ignoring case.
+func (f *Favorites) Add(name, station string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if f.index(name) >= 0 {
+		return fmt.Errorf("%w: %s", ErrExists, name)
+	}
+	f.Entries = append(f.Entries, Favorite{Name: name, Station: station})
+	return nil
+}
+
+// Remove deletes the favorite called name, ignoring case
+func (f *Favorites) Remove(name string) error {
+	i := f.index(name)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	f.Entries = slices.Delete(f.Entries, i, i+1)
+	return nil
+}
+
+// Resolve returns the stored station for an "@name" argument. Any other
+// argument is returned unchanged.
+func (f *Favorites) Resolve(arg string) (string, error) {
+	name, ok := strings.CutPrefix(strings.TrimSpace(arg), Prefix)
+	if !ok {
+		return arg, nil
+	}
+	fav, found := f.Get(name)
+	if !found {
+		return "", fmt.Errorf("%w: %s\nUse 'moko fav list' to see saved stations", ErrNotFound, name)
+	}
+	return fav.Station, nil
+}
+
+// index returns the position of the favorite called name, or -1
+func (f *Favorites) index(name string) int {
+	return slices.IndexFunc(f.Entries, func(fav Favorite) bool {
+		return strings.EqualFold(fav.Name, name)
+	})
+}
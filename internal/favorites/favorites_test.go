@@ -0,0 +1,93 @@
+package favorites
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+const (
+	koelnHbf = "8000207:A=1@O=Köln Hbf@X=6958730@Y=50943029@U=80@L=8000207@"
+	deutz    = "8003368:A=1@O=Köln Messe/Deutz@X=6975000@Y=50940872@U=80@L=8003368@"
+)
+
+func TestLoad_Missing(t *testing.T) {
+	favs, err := Load(filepath.Join(t.TempDir(), "favorites.json"))
+	testutil.AssertNil(t, err)
+	testutil.AssertLen(t, favs.Entries, 0)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "favorites.json")
+	testutil.AssertNil(t, os.WriteFile(path, []byte(`{`), 0600))
+	_, err := Load(path)
+	testutil.AssertError(t, err)
+}
+
+func TestSaveLoad_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "moko", "favorites.json")
+
+	favs := &Favorites{}
+	testutil.AssertNil(t, favs.Add("work", deutz))
+	testutil.AssertNil(t, favs.Add("Home", koelnHbf))
+	testutil.AssertNil(t, favs.Save(path))
+
+	loaded, err := Load(path)
+	testutil.AssertNil(t, err)
+	testutil.AssertLen(t, loaded.Entries, 2)
+
+	// Saved in name order
+	testutil.AssertEqual(t, loaded.Entries[0], Favorite{Name: "Home", Station: koelnHbf})
+	testutil.AssertEqual(t, loaded.Entries[1], Favorite{Name: "work", Station: deutz})
+
+	info, err := os.Stat(path)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, info.Mode().Perm(), os.FileMode(0600))
+}
+
+func TestAdd_NameCollision(t *testing.T) {
+	favs := &Favorites{}
+	testutil.AssertNil(t, favs.Add("home", koelnHbf))
+
+	// Names are compared without case
+	err := favs.Add("HOME", deutz)
+	testutil.AssertTrue(t, errors.Is(err, ErrExists))
+	testutil.AssertLen(t, favs.Entries, 1)
+
+	fav, ok := favs.Get("Home")
+	testutil.AssertTrue(t, ok)
+	testutil.AssertEqual(t, fav.Station, koelnHbf)
+
+	// Removing frees the name
+	testutil.AssertNil(t, favs.Remove("Home"))
+	testutil.AssertNil(t, favs.Add("HOME", deutz))
+	testutil.AssertTrue(t, errors.Is(favs.Remove("work"), ErrNotFound))
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"home", "Büro", "s-bahn_2", "a.b"} {
+		testutil.AssertNil(t, ValidateName(name))
+	}
+	for _, name := range []string{"", "@home", "my home", "-x", "a:b"} {
+		testutil.AssertError(t, ValidateName(name))
+	}
+}
+
+func TestResolve(t *testing.T) {
+	favs := &Favorites{Entries: []Favorite{{Name: "home", Station: koelnHbf}}}
+
+	got, err := favs.Resolve("@home")
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, got, koelnHbf)
+
+	// Other arguments pass through
+	got, err = favs.Resolve("8000105")
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, got, "8000105")
+
+	_, err = favs.Resolve("@gym")
+	testutil.AssertTrue(t, errors.Is(err, ErrNotFound))
+}
@@ -0,0 +1,135 @@
+package favorites
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrNoTrip indicates no trip of the given name is stored
+var ErrNoTrip = errors.New("trip not found")
+
+// LastTrip names the most recent connection query
+const LastTrip = "last"
+
+// Trip is a connection query that can be run again: its endpoints as given on
+// the command line and the preferences it was run with
+type Trip struct {
+	Name        string   `json:"name"`
+	From        string   `json:"from"`
+	To          string   `json:"to"`
+	Modes       []string `json:"modes,omitempty"`
+	PreferSched bool     `json:"prefer_sched,omitempty"`
+}
+
+// Trips holds the last connection query and any named ones. Names are
+// unique regardless of case.
+type Trips struct {
+	Last    *Trip  `json:"last,omitempty"`
+	Entries []Trip `json:"trips,omitempty"`
+}
+
+// DefaultTripsPath returns the default trips file location, next to the
+// favorites
+func DefaultTripsPath() string {
+	return filepath.Join(filepath.Dir(DefaultPath()), "trips.json")
+}
+
+// LoadTrips reads the trips file at path. A missing file yields no trips.
+func LoadTrips(path string) (*Trips, error) {
+	// #nosec G304 -- path is the user's own trips file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Trips{}, nil
+		}
+		return nil, fmt.Errorf("failed to read trips: %w", err)
+	}
+
+	var trips Trips
+	if err := json.Unmarshal(data, &trips); err != nil {
+		return nil, fmt.Errorf("invalid trips file %s: %w", path, err)
+	}
+	return &trips, nil
+}
+
+// Save writes the trips to path, sorted by name, creating its directory if
+// needed
+func (t *Trips) Save(path string) error {
+	slices.SortFunc(t.Entries, func(a, b Trip) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create trips directory: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Get returns the trip called name, ignoring case; LastTrip or an empty name
+// is the most recent query
+func (t *Trips) Get(name string) (Trip, error) {
+	if name == "" || strings.EqualFold(name, LastTrip) {
+		if t.Last == nil {
+			return Trip{}, fmt.Errorf("%w: no connection query to repeat yet", ErrNoTrip)
+		}
+		return *t.Last, nil
+	}
+	if i := t.index(name); i >= 0 {
+		return t.Entries[i], nil
+	}
+	names := make([]string, len(t.Entries))
+	for i, trip := range t.Entries {
+		names[i] = trip.Name
+	}
+	if len(names) == 0 {
+		return Trip{}, fmt.Errorf("%w: %s (none saved; use --save-trip)", ErrNoTrip, name)
+	}
+	return Trip{}, fmt.Errorf("%w: %s (saved: %s)", ErrNoTrip, name, strings.Join(names, ", "))
+}
+
+// Record stores trip as the most recent query
+func (t *Trips) Record(trip Trip) {
+	trip.Name = LastTrip
+	t.Last = &trip
+}
+
+// ValidateTripName checks that name can be used for a named trip
+func ValidateTripName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("invalid trip name %q: use letters, digits, '_', '.' and '-'", name)
+	}
+	if strings.EqualFold(name, LastTrip) {
+		return fmt.Errorf("trip name %q is reserved for the most recent query", LastTrip)
+	}
+	return nil
+}
+
+// Put stores trip under name, replacing a trip of the same name
+func (t *Trips) Put(name string, trip Trip) error {
+	if err := ValidateTripName(name); err != nil {
+		return err
+	}
+	trip.Name = name
+	if i := t.index(name); i >= 0 {
+		t.Entries[i] = trip
+		return nil
+	}
+	t.Entries = append(t.Entries, trip)
+	return nil
+}
+
+// index returns the position of the trip called name, or -1
+func (t *Trips) index(name string) int {
+	return slices.IndexFunc(t.Entries, func(trip Trip) bool {
+		return strings.EqualFold(trip.Name, name)
+	})
+}
@@ -0,0 +1,67 @@
+package favorites
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestTrips_Last(t *testing.T) {
+	trips := &Trips{}
+	_, err := trips.Get("")
+	testutil.AssertTrue(t, errors.Is(err, ErrNoTrip))
+
+	trips.Record(Trip{From: "@home", To: "8000105"})
+	trips.Record(Trip{From: "@home", To: "@work", Modes: []string{"REGIONAL"}})
+
+	// Only the most recent query is kept
+	trip, err := trips.Get(LastTrip)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, trip.Name, LastTrip)
+	testutil.AssertEqual(t, trip.To, "@work")
+	testutil.AssertLen(t, trip.Modes, 1)
+}
+
+func TestTrips_Named(t *testing.T) {
+	trips := &Trips{}
+	testutil.AssertNil(t, trips.Put("work", Trip{From: "@home", To: "@work"}))
+	testutil.AssertNil(t, trips.Put("Work", Trip{From: "@home", To: "@work", PreferSched: true}))
+	testutil.AssertLen(t, trips.Entries, 1)
+
+	trip, err := trips.Get("WORK")
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, trip.Name, "Work")
+	testutil.AssertTrue(t, trip.PreferSched)
+
+	_, err = trips.Get("gym")
+	testutil.AssertTrue(t, errors.Is(err, ErrNoTrip))
+	testutil.AssertContains(t, err.Error(), "saved: Work")
+
+	testutil.AssertError(t, trips.Put("last", Trip{}))
+	testutil.AssertError(t, trips.Put("to work", Trip{}))
+}
+
+func TestTrips_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "moko", "trips.json")
+
+	trips, err := LoadTrips(path)
+	testutil.AssertNil(t, err)
+	trips.Record(Trip{From: koelnHbf, To: deutz})
+	testutil.AssertNil(t, trips.Put("uni", Trip{From: "@home", To: "8000105", Modes: []string{"SBAHN", "TRAM"}}))
+	testutil.AssertNil(t, trips.Put("gym", Trip{From: "@home", To: deutz}))
+	testutil.AssertNil(t, trips.Save(path))
+
+	loaded, err := LoadTrips(path)
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, loaded.Last.From, koelnHbf)
+	testutil.AssertLen(t, loaded.Entries, 2)
+	testutil.AssertEqual(t, loaded.Entries[0].Name, "gym")
+	testutil.AssertEqual(t, loaded.Entries[1].Modes[1], "TRAM")
+
+	testutil.AssertNil(t, os.WriteFile(path, []byte(`{`), 0600))
+	_, err = LoadTrips(path)
+	testutil.AssertError(t, err)
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Accessibility tells whether a train can be boarded step-free, e.g. in a
+// wheelchair
+type Accessibility int
+
+// Accessibility as reported by bahn.de. The zero value means the train's
+// attributes don't say, which is common: the data is incomplete.
+const (
+	AccessibilityUnknown Accessibility = iota
+	AccessibilityNo
+	AccessibilityYes
+)
+
+// accessibilityNames are the JSON names of the known values
+var accessibilityNames = map[Accessibility]string{
+	AccessibilityNo:  "no",
+	AccessibilityYes: "yes",
+}
+
+// String returns the name, or "" when unknown
+func (a Accessibility) String() string {
+	return accessibilityNames[a]
+}
+
+// MarshalText encodes the value by name
+func (a Accessibility) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes a name; an empty name means unknown
+func (a *Accessibility) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*a = AccessibilityUnknown
+		return nil
+	}
+	for value, name := range accessibilityNames {
+		if name == string(text) {
+			*a = value
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown accessibility %q", text)
+}
+
+// TrainAttribute is a raw train amenity ("zugattribute" entries), e.g.
+// {"kategorie": "INFORMATION", "key": "EH", "value": "Fahrzeuggebundene
+// Einstiegshilfe vorhanden"}
+type TrainAttribute struct {
+	Kategorie string `json:"kategorie"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+}
+
+// accessibleAttributeKeys are the Hafas attribute codes for step-free
+// boarding and wheelchair spaces
+var accessibleAttributeKeys = map[string]bool{
+	"EA": true, // Behindertengerechte Ausstattung
+	"EH": true, // Fahrzeuggebundene Einstiegshilfe vorhanden
+	"ER": true, // Einstieg mit Rollstuhl stufenfrei
+	"RG": true, // Behindertengerechtes Fahrzeug
+	"RO": true, // Rollstuhlstellplatz
+}
+
+// Wording of attributes without a known code, checked in lower case
+var (
+	inaccessibleWords = []string{"nicht barrierefrei", "kein barrierefrei", "nicht stufenfrei", "kein stufenfrei"}
+	accessibleWords   = []string{"barrierefrei", "stufenfrei", "rollstuhl", "einstiegshilfe"}
+)
+
+// parseAccessibility derives the accessibility from a train's attributes. An
+// attribute ruling out step-free boarding wins over those offering it.
+func parseAccessibility(attrs []TrainAttribute) Accessibility {
+	result := AccessibilityUnknown
+	for _, attr := range attrs {
+		text := strings.ToLower(attr.Value)
+		if mentionsAny(text, inaccessibleWords) {
+			return AccessibilityNo
+		}
+		if accessibleAttributeKeys[strings.ToUpper(strings.TrimSpace(attr.Key))] || mentionsAny(text, accessibleWords) {
+			result = AccessibilityYes
+		}
+	}
+	return result
+}
+
+// mentionsAny reports whether text contains any of the phrases
+func mentionsAny(text string, phrases []string) bool {
+	for _, phrase := range phrases {
+		if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
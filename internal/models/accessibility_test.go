@@ -0,0 +1,116 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseAccessibility(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs []TrainAttribute
+		want  Accessibility
+	}{
+		{"none", nil, AccessibilityUnknown},
+		{"unrelated", []TrainAttribute{{Key: "BR", Value: "Bordrestaurant"}}, AccessibilityUnknown},
+		{"known key", []TrainAttribute{{Key: "EH", Value: "Fahrzeuggebundene Einstiegshilfe vorhanden"}}, AccessibilityYes},
+		{"lower-case key", []TrainAttribute{{Key: "ro"}}, AccessibilityYes},
+		{"wording only", []TrainAttribute{{Value: "Stufenfreier Einstieg"}}, AccessibilityYes},
+		{"ruled out", []TrainAttribute{{Value: "Fahrzeug nicht barrierefrei"}}, AccessibilityNo},
+		{"ruled out wins", []TrainAttribute{
+			{Key: "RO", Value: "Rollstuhlstellplatz"},
+			{Value: "Einstieg nicht stufenfrei"},
+		}, AccessibilityNo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseAccessibility(tt.attrs); got != tt.want {
+				t.Errorf("parseAccessibility() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDepartureResponse_Accessibility(t *testing.T) {
+	raw := `{
+		"journeyId": "j1",
+		"terminus": "Bonn Hbf",
+		"zeit": "2025-01-15T10:00:00",
+		"verkehrmittel": {
+			"kurzText": "RE", "name": "RE 5",
+			"zugattribute": [{"kategorie": "INFORMATION", "key": "EH", "value": "Fahrzeuggebundene Einstiegshilfe vorhanden"}]
+		}
+	}`
+
+	var r DepartureResponse
+	if err := json.Unmarshal([]byte(raw), &r); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	dep := r.ToDeparture(time.UTC)
+	if dep.Accessible != AccessibilityYes {
+		t.Fatalf("Accessible = %q, want yes", dep.Accessible)
+	}
+
+	out, err := json.Marshal(dep)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatalf("Unmarshal fields: %v", err)
+	}
+	if fields["accessible"] != "yes" {
+		t.Errorf(`accessible = %v, want "yes"`, fields["accessible"])
+	}
+
+	// Unknown is left out rather than reported as "no"
+	out, err = json.Marshal(Departure{})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	fields = nil
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatalf("Unmarshal fields: %v", err)
+	}
+	if _, ok := fields["accessible"]; ok {
+		t.Errorf("accessible present for unknown: %s", out)
+	}
+}
+
+func TestJourneyResponse_Accessibility(t *testing.T) {
+	raw := `{
+		"zugName": "RE 5",
+		"zugattribute": [{"key": "RO", "value": "Rollstuhlstellplatz"}],
+		"halte": [
+			{"name": "Köln Hbf", "abfahrtsZeitpunkt": "2025-01-15T10:00:00"},
+			{"name": "Bonn Hbf", "ankunftsZeitpunkt": "2025-01-15T10:30:00",
+			 "zugattribute": [{"value": "Einstieg nicht stufenfrei"}]}
+		]
+	}`
+
+	var r JourneyResponse
+	if err := json.Unmarshal([]byte(raw), &r); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	j := r.ToJourney("j1", time.UTC)
+	if len(j.Stops) != 2 {
+		t.Fatalf("got %d stops, want 2", len(j.Stops))
+	}
+	if j.Stops[0].Accessible != AccessibilityYes {
+		t.Errorf("stop 0 Accessible = %q, want yes (inherited)", j.Stops[0].Accessible)
+	}
+	if j.Stops[1].Accessible != AccessibilityNo {
+		t.Errorf("stop 1 Accessible = %q, want no", j.Stops[1].Accessible)
+	}
+}
+
+func TestAccessibility_UnmarshalText(t *testing.T) {
+	var a Accessibility
+	if err := a.UnmarshalText([]byte("yes")); err != nil || a != AccessibilityYes {
+		t.Errorf("UnmarshalText(yes) = %q, %v", a, err)
+	}
+	if err := a.UnmarshalText([]byte("maybe")); err == nil {
+		t.Error("UnmarshalText(maybe) succeeded, want error")
+	}
+}
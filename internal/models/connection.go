@@ -0,0 +1,184 @@
+package models
+
+import (
+	"time"
+)
+
+// Connection is one option of a point-to-point trip search: the legs from
+// origin to destination, including walks between stations
+type Connection struct {
+	Legs            []ConnectionLeg `json:"legs"`
+	Transfers       int             `json:"transfers"`
+	DurationMinutes int             `json:"durationMinutes"`
+}
+
+// ConnectionLeg is a single ride (or walk) within a connection
+type ConnectionLeg struct {
+	JourneyID   string     `json:"journeyId,omitempty"`
+	Line        string     `json:"line"`
+	Product     string     `json:"product,omitempty"`
+	Direction   string     `json:"direction,omitempty"`
+	Walk        bool       `json:"walk,omitempty"`
+	From        string     `json:"from"`
+	FromEVA     string     `json:"fromEva,omitempty"`
+	To          string     `json:"to"`
+	ToEVA       string     `json:"toEva,omitempty"`
+	DepPlatform string     `json:"depPlatform,omitempty"`
+	ArrPlatform string     `json:"arrPlatform,omitempty"`
+	SchedDep    *time.Time `json:"schedDep,omitempty"`
+	Dep         *time.Time `json:"dep,omitempty"`
+	SchedArr    *time.Time `json:"schedArr,omitempty"`
+	Arr         *time.Time `json:"arr,omitempty"`
+	DepDelay    int        `json:"depDelay"`
+	ArrDelay    int        `json:"arrDelay"`
+	IsCancelled bool       `json:"isCancelled"`
+}
+
+// Departure returns the first leg, or nil for a connection without legs
+func (c *Connection) Departure() *ConnectionLeg {
+	if len(c.Legs) == 0 {
+		return nil
+	}
+	return &c.Legs[0]
+}
+
+// Arrival returns the last leg, or nil for a connection without legs
+func (c *Connection) Arrival() *ConnectionLeg {
+	if len(c.Legs) == 0 {
+		return nil
+	}
+	return &c.Legs[len(c.Legs)-1]
+}
+
+// ConnectionsResponse represents the raw API response of a trip search
+type ConnectionsResponse struct {
+	Verbindungen []ConnectionResponse `json:"verbindungen"`
+}
+
+// ConnectionResponse represents a single connection in the raw response
+type ConnectionResponse struct {
+	UmstiegsAnzahl              int                     `json:"umstiegsAnzahl"`
+	VerbindungsDauerInSeconds   int                     `json:"verbindungsDauerInSeconds"`
+	EZVerbindungsDauerInSeconds int                     `json:"ezVerbindungsDauerInSeconds"`
+	Abschnitte                  []ConnectionLegResponse `json:"verbindungsAbschnitte"`
+}
+
+// ConnectionLegResponse represents a single leg in the raw response
+type ConnectionLegResponse struct {
+	JourneyID           string     `json:"journeyId"`
+	AbfahrtsZeitpunkt   string     `json:"abfahrtsZeitpunkt"`
+	EZAbfahrtsZeitpunkt string     `json:"ezAbfahrtsZeitpunkt"`
+	AnkunftsZeitpunkt   string     `json:"ankunftsZeitpunkt"`
+	EZAnkunftsZeitpunkt string     `json:"ezAnkunftsZeitpunkt"`
+	AbfahrtsOrt         string     `json:"abfahrtsOrt"`
+	AbfahrtsOrtExtID    FlexString `json:"abfahrtsOrtExtId"`
+	AnkunftsOrt         string     `json:"ankunftsOrt"`
+	AnkunftsOrtExtID    FlexString `json:"ankunftsOrtExtId"`
+	Verkehrsmittel      struct {
+		ProduktGattung string `json:"produktGattung"`
+		Name           string `json:"name"`
+		MittelText     string `json:"mittelText"`
+		Richtung       string `json:"richtung"`
+		Typ            string `json:"typ"`
+	} `json:"verkehrsmittel"`
+	Halte []struct {
+		Gleis   FlexString `json:"gleis"`
+		EZGleis FlexString `json:"ezGleis"`
+	} `json:"halte"`
+	RisNotizen []struct {
+		Key string `json:"key"`
+	} `json:"risNotizen"`
+}
+
+// ToConnection converts the raw response to a Connection
+func (r *ConnectionResponse) ToConnection(loc *time.Location) *Connection {
+	conn := &Connection{Legs: make([]ConnectionLeg, 0, len(r.Abschnitte))}
+	rides := 0
+	for i := range r.Abschnitte {
+		leg := r.Abschnitte[i].toLeg(loc)
+		if !leg.Walk {
+			rides++
+		}
+		conn.Legs = append(conn.Legs, leg)
+	}
+
+	// Fall back to counting rides when the API omits the number of changes;
+	// walks between stations are not changes
+	conn.Transfers = r.UmstiegsAnzahl
+	if conn.Transfers == 0 && rides > 1 {
+		conn.Transfers = rides - 1
+	}
+
+	secs := r.EZVerbindungsDauerInSeconds
+	if secs == 0 {
+		secs = r.VerbindungsDauerInSeconds
+	}
+	conn.DurationMinutes = secs / 60
+	if first, last := conn.Departure(), conn.Arrival(); secs == 0 && first != nil && first.Dep != nil && last.Arr != nil {
+		// Without durations in the response, use the effective end times
+		conn.DurationMinutes = int(last.Arr.Sub(*first.Dep).Minutes())
+	}
+
+	return conn
+}
+
+// toLeg converts a raw leg. Times fall back to the schedule when no real-time
+// estimate was sent.
+func (r *ConnectionLegResponse) toLeg(loc *time.Location) ConnectionLeg {
+	leg := ConnectionLeg{
+		JourneyID: r.JourneyID,
+		Line:      r.Verkehrsmittel.MittelText,
+		Product:   r.Verkehrsmittel.ProduktGattung,
+		Direction: r.Verkehrsmittel.Richtung,
+		Walk:      r.Verkehrsmittel.Typ == "WALK" || r.Verkehrsmittel.Typ == "TRANSFER",
+		From:      r.AbfahrtsOrt,
+		FromEVA:   string(r.AbfahrtsOrtExtID),
+		To:        r.AnkunftsOrt,
+		ToEVA:     string(r.AnkunftsOrtExtID),
+	}
+	if leg.Line == "" {
+		leg.Line = r.Verkehrsmittel.Name
+	}
+
+	if n := len(r.Halte); n > 0 {
+		leg.DepPlatform = firstNonEmpty(string(r.Halte[0].EZGleis), string(r.Halte[0].Gleis))
+		leg.ArrPlatform = firstNonEmpty(string(r.Halte[n-1].EZGleis), string(r.Halte[n-1].Gleis))
+	}
+
+	leg.SchedDep, leg.Dep, leg.DepDelay = parseLegTimes(r.AbfahrtsZeitpunkt, r.EZAbfahrtsZeitpunkt, loc)
+	leg.SchedArr, leg.Arr, leg.ArrDelay = parseLegTimes(r.AnkunftsZeitpunkt, r.EZAnkunftsZeitpunkt, loc)
+
+	for _, note := range r.RisNotizen {
+		if note.Key == "text.realtime.connection.cancelled" || note.Key == "text.realtime.stop.cancelled" {
+			leg.IsCancelled = true
+		}
+	}
+	return leg
+}
+
+// parseLegTimes returns the scheduled and effective time and the delay in
+// minutes for a scheduled and real-time timestamp pair
+func parseLegTimes(sched, rt string, loc *time.Location) (*time.Time, *time.Time, int) {
+	var schedTime, effective *time.Time
+	if t, err := parseTime(sched, loc); sched != "" && err == nil {
+		schedTime = &t
+		effective = schedTime
+	}
+	if t, err := parseTime(rt, loc); rt != "" && err == nil {
+		effective = &t
+	}
+	if schedTime == nil || effective == nil {
+		return schedTime, effective, 0
+	}
+	return schedTime, effective, int(effective.Sub(*schedTime).Minutes())
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestConnectionResponse_ToConnection(t *testing.T) {
+	var resp ConnectionsResponse
+	if err := json.Unmarshal([]byte(testutil.SampleConnectionResponse), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(resp.Verbindungen) != 1 {
+		t.Fatalf("got %d connections, want 1", len(resp.Verbindungen))
+	}
+
+	conn := resp.Verbindungen[0].ToConnection(time.UTC)
+	if len(conn.Legs) != 3 {
+		t.Fatalf("got %d legs, want 3", len(conn.Legs))
+	}
+	if conn.Transfers != 1 || conn.DurationMinutes != 81 {
+		t.Errorf("got %d transfers, %d min, want 1 and 81", conn.Transfers, conn.DurationMinutes)
+	}
+
+	ice := conn.Legs[0]
+	if ice.Line != "ICE 123" || ice.From != "Köln Hbf" || ice.To != "Frankfurt(Main)Hbf" || ice.Walk {
+		t.Errorf("unexpected first leg: %+v", ice)
+	}
+	if ice.DepDelay != 3 || ice.ArrDelay != 4 {
+		t.Errorf("got delays %+d/%+d, want +3/+4", ice.DepDelay, ice.ArrDelay)
+	}
+	if ice.Dep.Format("15:04") != "12:07" || ice.SchedDep.Format("15:04") != "12:04" {
+		t.Errorf("got departure %s (scheduled %s)", ice.Dep.Format("15:04"), ice.SchedDep.Format("15:04"))
+	}
+	if ice.DepPlatform != "4" || ice.ArrPlatform != "9" {
+		t.Errorf("got platforms %q/%q, want 4/9", ice.DepPlatform, ice.ArrPlatform)
+	}
+
+	if walk := conn.Legs[1]; !walk.Walk || walk.Line != "Fußweg" {
+		t.Errorf("second leg should be a walk: %+v", walk)
+	}
+	if s := conn.Legs[2]; s.ArrDelay != 0 || s.Arr == nil || s.ToEVA != "8070004" {
+		t.Errorf("unexpected last leg: %+v", s)
+	}
+
+	if conn.Departure() != &conn.Legs[0] || conn.Arrival() != &conn.Legs[2] {
+		t.Error("Departure/Arrival should return the first and last leg")
+	}
+}
+
+func TestConnectionResponse_FallbackCounts(t *testing.T) {
+	resp := ConnectionResponse{Abschnitte: []ConnectionLegResponse{
+		{AbfahrtsZeitpunkt: "2025-12-28T12:00:00", AnkunftsZeitpunkt: "2025-12-28T12:30:00"},
+		{AbfahrtsZeitpunkt: "2025-12-28T12:40:00", AnkunftsZeitpunkt: "2025-12-28T13:15:00"},
+	}}
+
+	conn := resp.ToConnection(time.UTC)
+	if conn.Transfers != 1 || conn.DurationMinutes != 75 {
+		t.Errorf("got %d transfers, %d min, want 1 and 75", conn.Transfers, conn.DurationMinutes)
+	}
+
+	if empty := (&Connection{}); empty.Departure() != nil || empty.Arrival() != nil {
+		t.Error("connection without legs should have no departure or arrival")
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"regexp"
 	"strings"
 	"time"
 )
@@ -16,6 +17,7 @@ type Departure struct {
 	TrainLong   string     `json:"trainLong"`
 	StopEVA     string     `json:"stopEva"`
 	Destination string     `json:"destination"`
+	Origin      string     `json:"origin,omitempty"`
 	Platform    string     `json:"platform"`
 	RTPlatform  string     `json:"rtPlatform"`
 	Via         []string   `json:"via,omitempty"`
@@ -25,7 +27,17 @@ type Departure struct {
 	Dep         *time.Time `json:"dep,omitempty"`
 	Delay       int        `json:"delay"`
 	IsCancelled bool       `json:"isCancelled"`
-	Messages    []Message  `json:"messages,omitempty"`
+	Product     string     `json:"product,omitempty"`
+	OnDemand    bool       `json:"onDemand"`
+	// Accessible tells whether the train can be boarded step-free
+	Accessible  Accessibility `json:"accessible,omitempty"`
+	StartsHere  bool          `json:"startsHere,omitempty"`
+	EndsHere    bool          `json:"endsHere,omitempty"`
+	CallsAtHome bool          `json:"callsAtHome,omitempty"`
+	Messages    []Message     `json:"messages,omitempty"`
+	// OccupancyFirst and OccupancySecond forecast how full the train is
+	OccupancyFirst  Occupancy `json:"occupancyFirst,omitempty"`
+	OccupancySecond Occupancy `json:"occupancySecond,omitempty"`
 }
 
 // Message represents an alert/notification for a departure
@@ -36,24 +48,27 @@ type Message struct {
 
 // DepartureResponse represents the raw JSON for a single departure entry
 type DepartureResponse struct {
-	JourneyID     string   `json:"journeyId"`
-	BahnhofsID    string   `json:"bahnhofsId"`
-	Terminus      string   `json:"terminus"`
-	Gleis         string   `json:"gleis"`
-	EZGleis       string   `json:"ezGleis"`
-	Zeit          string   `json:"zeit"`
-	EZZeit        string   `json:"ezZeit"`
-	Ueber         []string `json:"ueber"`
+	JourneyID     string     `json:"journeyId"`
+	BahnhofsID    FlexString `json:"bahnhofsId"`
+	Terminus      string     `json:"terminus"`
+	Gleis         FlexString `json:"gleis"`
+	EZGleis       FlexString `json:"ezGleis"`
+	Zeit          string     `json:"zeit"`
+	EZZeit        string     `json:"ezZeit"`
+	Ueber         []string   `json:"ueber"`
 	Verkehrmittel struct {
-		KurzText   string `json:"kurzText"`
-		MittelText string `json:"mittelText"`
-		LangText   string `json:"langText"`
-		Name       string `json:"name"`
+		KurzText       string           `json:"kurzText"`
+		MittelText     string           `json:"mittelText"`
+		LangText       string           `json:"langText"`
+		Name           string           `json:"name"`
+		ProduktGattung string           `json:"produktGattung"`
+		Zugattribute   []TrainAttribute `json:"zugattribute"`
 	} `json:"verkehrmittel"`
 	Meldungen []struct {
 		Type string `json:"type"`
 		Text string `json:"text"`
 	} `json:"meldungen"`
+	Auslastungsmeldungen []OccupancyReport `json:"auslastungsmeldungen"`
 }
 
 // DeparturesResponse represents the full API response for departures
@@ -61,9 +76,19 @@ type DeparturesResponse struct {
 	Entries []DepartureResponse `json:"entries"`
 }
 
+// departureAlloc backs a Departure together with its parsed times so that
+// ToDeparture needs a single allocation instead of one per time pointer.
+type departureAlloc struct {
+	dep   Departure
+	sched time.Time
+	rt    time.Time
+}
+
 // ToDeparture converts the raw response to a Departure
 func (r *DepartureResponse) ToDeparture(loc *time.Location) *Departure {
-	dep := &Departure{
+	a := &departureAlloc{}
+	dep := &a.dep
+	*dep = Departure{
 		JourneyID:   r.JourneyID,
 		Type:        r.Verkehrmittel.KurzText,
 		Line:        r.Verkehrmittel.MittelText,
@@ -71,11 +96,15 @@ func (r *DepartureResponse) ToDeparture(loc *time.Location) *Departure {
 		TrainShort:  r.Verkehrmittel.KurzText,
 		TrainMid:    r.Verkehrmittel.MittelText,
 		TrainLong:   r.Verkehrmittel.LangText,
-		StopEVA:     r.BahnhofsID,
+		StopEVA:     string(r.BahnhofsID),
 		Destination: r.Terminus,
-		Platform:    r.Gleis,
-		RTPlatform:  r.EZGleis,
+		Platform:    string(r.Gleis),
+		RTPlatform:  string(r.EZGleis),
+		Product:     r.Verkehrmittel.ProduktGattung,
 	}
+	dep.OnDemand = isOnDemand(dep.Product, dep.Type)
+	dep.Accessible = parseAccessibility(r.Verkehrmittel.Zugattribute)
+	dep.OccupancyFirst, dep.OccupancySecond = parseOccupancy(r.Auslastungsmeldungen)
 
 	// Process via stations (skip first entry as in Perl version)
 	if len(r.Ueber) > 1 {
@@ -88,12 +117,14 @@ func (r *DepartureResponse) ToDeparture(loc *time.Location) *Departure {
 	// Parse times
 	if r.Zeit != "" {
 		if t, err := parseTime(r.Zeit, loc); err == nil {
-			dep.SchedDep = &t
+			a.sched = t
+			dep.SchedDep = &a.sched
 		}
 	}
 	if r.EZZeit != "" {
 		if t, err := parseTime(r.EZZeit, loc); err == nil {
-			dep.RTDep = &t
+			a.rt = t
+			dep.RTDep = &a.rt
 		}
 	}
 
@@ -109,20 +140,33 @@ func (r *DepartureResponse) ToDeparture(loc *time.Location) *Departure {
 		dep.Delay = int(dep.RTDep.Sub(*dep.SchedDep).Minutes())
 	}
 
-	// Process messages
+	// Process messages; HALT_AUSFALL only marks the stop as cancelled
 	for _, msg := range r.Meldungen {
+		if msg.Type == "HALT_AUSFALL" {
+			dep.IsCancelled = true
+			continue
+		}
 		dep.Messages = append(dep.Messages, Message{
 			Type: msg.Type,
 			Text: msg.Text,
 		})
-		if msg.Type == "HALT_AUSFALL" {
-			dep.IsCancelled = true
-		}
 	}
 
 	return dep
 }
 
+// ToArrival converts the raw response of an arrival board entry. The API
+// sends the train's origin as the terminus there; it is exposed as Origin and
+// kept in Destination for existing consumers.
+func (r *DepartureResponse) ToArrival(loc *time.Location) *Departure {
+	arr := r.ToDeparture(loc)
+	arr.Origin = r.Terminus
+	return arr
+}
+
+// timeLayout is the layout of times returned by the API
+const timeLayout = "2006-01-02T15:04:05"
+
 // parseTime parses a time string in format "2006-01-02T15:04:05"
 func parseTime(s string, loc *time.Location) (time.Time, error) {
 	// Handle timezone suffix if present
@@ -130,7 +174,201 @@ func parseTime(s string, loc *time.Location) (time.Time, error) {
 	if idx := strings.Index(s, "+"); idx > 0 {
 		s = s[:idx]
 	}
-	return time.ParseInLocation("2006-01-02T15:04:05", s, loc)
+	if t, ok := parseTimeFast(s, loc); ok {
+		return t, nil
+	}
+	return time.ParseInLocation(timeLayout, s, loc)
+}
+
+// parseTimeFast parses well-formed timeLayout strings without going through
+// the generic layout parser. It reports false for anything it does not fully
+// validate, leaving errors and edge cases to time.ParseInLocation.
+func parseTimeFast(s string, loc *time.Location) (time.Time, bool) {
+	if len(s) != len(timeLayout) || s[4] != '-' || s[7] != '-' || s[10] != 'T' || s[13] != ':' || s[16] != ':' {
+		return time.Time{}, false
+	}
+	year, ok1 := atoiDigits(s[0:4])
+	month, ok2 := atoiDigits(s[5:7])
+	day, ok3 := atoiDigits(s[8:10])
+	hour, ok4 := atoiDigits(s[11:13])
+	minute, ok5 := atoiDigits(s[14:16])
+	sec, ok6 := atoiDigits(s[17:19])
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || !ok6 {
+		return time.Time{}, false
+	}
+	if month < 1 || month > 12 || day < 1 || day > daysIn(time.Month(month), year) ||
+		hour > 23 || minute > 59 || sec > 59 {
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, sec, 0, loc), true
+}
+
+// atoiDigits parses a string consisting only of ASCII digits
+func atoiDigits(s string) (int, bool) {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
+
+// daysIn returns the number of days in the given month
+func daysIn(m time.Month, year int) int {
+	return time.Date(year, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// LineParts splits the display line into its product category (e.g. "ICE", "S",
+// "Bus") and the line/train number. The category is driven by Type; when Line
+// already embeds it (as in "S 1" or "S1") the prefix is stripped from the number.
+// The prefix must end the line or be followed by a space or digit, so "SEV 5"
+// is not read as an S-Bahn. If Type is unknown or does not match, the whole
+// line is returned as number.
+func (d *Departure) LineParts() (category, number string) {
+	line := strings.TrimSpace(d.Line)
+	if line == "" {
+		line = strings.TrimSpace(d.TrainShort)
+	}
+	category = strings.TrimSpace(d.Type)
+	if category == "" {
+		return "", line
+	}
+
+	if len(line) >= len(category) && strings.EqualFold(line[:len(category)], category) {
+		if rest := line[len(category):]; rest == "" || rest[0] == ' ' || (rest[0] >= '0' && rest[0] <= '9') {
+			return category, strings.TrimSpace(rest)
+		}
+	}
+
+	// Bare numbers (e.g. "123") get the category prepended
+	if line != "" && line[0] >= '0' && line[0] <= '9' {
+		return category, line
+	}
+
+	return "", line
+}
+
+// Transit groups returned by Departure.TransitGroup
+const (
+	TransitRail  = "rail"
+	TransitLocal = "local"
+)
+
+// localTransitTypes lists product short texts of local (non-rail) transit
+var localTransitTypes = map[string]bool{
+	"BUS":    true,
+	"STR":    true, // Straßenbahn
+	"TRAM":   true,
+	"U":      true,
+	"SCHIFF": true,
+	"FÄHRE":  true,
+	"F":      true,
+	"AST":    true, // Anruf-Sammel-Taxi
+	"ALT":    true, // Anruf-Linien-Taxi
+	"RUF":    true,
+	"SEV":    true, // Schienenersatzverkehr (replacement bus)
+}
+
+// ProductOnDemand is the product category of call-ahead services that only
+// run when reserved in advance (Anrufbus, Anruf-Sammel-Taxi, ...)
+const ProductOnDemand = "ANRUFPFLICHTIG"
+
+// onDemandTypes lists product short texts of call-ahead services, used when
+// the response carries no product category
+var onDemandTypes = map[string]bool{
+	"AST": true, // Anruf-Sammel-Taxi
+	"ALT": true, // Anruf-Linien-Taxi
+	"RUF": true, // Rufbus
+}
+
+// isOnDemand reports whether a service must be reserved before travel
+func isOnDemand(product, typ string) bool {
+	if product != "" {
+		return strings.EqualFold(product, ProductOnDemand)
+	}
+	return onDemandTypes[strings.ToUpper(strings.TrimSpace(typ))]
+}
+
+// TransitGroup classifies the departure as rail (long-distance, regional,
+// S-Bahn) or local transit (bus, tram, U-Bahn, ferry, on-demand) based on Type
+func (d *Departure) TransitGroup() string {
+	t := strings.ToUpper(strings.TrimSpace(d.Type))
+	if localTransitTypes[t] || strings.HasPrefix(t, "BUS") {
+		return TransitLocal
+	}
+	return TransitRail
+}
+
+var hafasNameRegex = regexp.MustCompile(`(?:^|@)O=([^@]+)`)
+
+// StationNameFromID extracts the station name from a Hafas ID such as
+// "A=1@O=Köln Hbf@X=6958730@Y=50943029@U=80@L=8000207@". It returns "" if
+// the ID carries no name.
+func StationNameFromID(id string) string {
+	if m := hafasNameRegex.FindStringSubmatch(id); len(m) == 2 {
+		return m[1]
+	}
+	return ""
+}
+
+// MarkEndpoints flags board entries whose train starts or ends at the board
+// station. The API reports the far end of the run as terminus: the destination
+// on departure boards and the origin on arrival boards. A terminus equal to the
+// board station therefore means the train terminates here (departures) or
+// originates here (arrivals), and the board time has no real counterpart.
+func MarkEndpoints(deps []Departure, stationName string, arrivals bool) {
+	station := normalizeStationName(stationName)
+	if station == "" {
+		return
+	}
+	for i := range deps {
+		endpoint := deps[i].Destination
+		if arrivals && deps[i].Origin != "" {
+			endpoint = deps[i].Origin
+		}
+		if normalizeStationName(endpoint) != station {
+			continue
+		}
+		if arrivals {
+			deps[i].StartsHere = true
+		} else {
+			deps[i].EndsHere = true
+		}
+	}
+}
+
+// normalizeStationName folds case and surrounding whitespace for comparisons
+func normalizeStationName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ViaIncludes reports whether the named station appears among the via
+// stations or as the terminus (or origin, on arrival boards)
+func (d *Departure) ViaIncludes(name string) bool {
+	station := normalizeStationName(name)
+	if station == "" {
+		return false
+	}
+	for _, via := range d.Via {
+		if normalizeStationName(via) == station {
+			return true
+		}
+	}
+	return normalizeStationName(d.ViaLast) == station ||
+		normalizeStationName(d.Destination) == station ||
+		normalizeStationName(d.Origin) == station
+}
+
+// ArrivalOrigin returns where an arriving train comes from, falling back to
+// Destination for entries not parsed with ToArrival
+func (d *Departure) ArrivalOrigin() string {
+	if d.Origin != "" {
+		return d.Origin
+	}
+	return d.Destination
 }
 
 // EffectivePlatform returns the real-time platform if available, otherwise scheduled
@@ -140,3 +378,9 @@ func (d *Departure) EffectivePlatform() string {
 	}
 	return d.Platform
 }
+
+// PlatformChanged reports whether the train uses another platform than
+// scheduled
+func (d *Departure) PlatformChanged() bool {
+	return d.Platform != "" && d.RTPlatform != "" && d.RTPlatform != d.Platform
+}
@@ -2,6 +2,9 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -28,10 +31,12 @@ func TestDepartureResponse_ToDeparture(t *testing.T) {
 				Zeit:      "2025-01-15T10:00:00",
 				EZZeit:    "2025-01-15T10:05:00",
 				Verkehrmittel: struct {
-					KurzText   string `json:"kurzText"`
-					MittelText string `json:"mittelText"`
-					LangText   string `json:"langText"`
-					Name       string `json:"name"`
+					KurzText       string           `json:"kurzText"`
+					MittelText     string           `json:"mittelText"`
+					LangText       string           `json:"langText"`
+					Name           string           `json:"name"`
+					ProduktGattung string           `json:"produktGattung"`
+					Zugattribute   []TrainAttribute `json:"zugattribute"`
 				}{
 					KurzText:   "ICE",
 					MittelText: "ICE 123",
@@ -52,10 +57,12 @@ func TestDepartureResponse_ToDeparture(t *testing.T) {
 				Zeit:      "2025-01-15T14:30:00",
 				EZZeit:    "2025-01-15T14:30:00",
 				Verkehrmittel: struct {
-					KurzText   string `json:"kurzText"`
-					MittelText string `json:"mittelText"`
-					LangText   string `json:"langText"`
-					Name       string `json:"name"`
+					KurzText       string           `json:"kurzText"`
+					MittelText     string           `json:"mittelText"`
+					LangText       string           `json:"langText"`
+					Name           string           `json:"name"`
+					ProduktGattung string           `json:"produktGattung"`
+					Zugattribute   []TrainAttribute `json:"zugattribute"`
 				}{
 					KurzText:   "RE",
 					MittelText: "RE 50",
@@ -80,10 +87,12 @@ func TestDepartureResponse_ToDeparture(t *testing.T) {
 					{Type: "HALT_AUSFALL", Text: "Zug fällt aus"},
 				},
 				Verkehrmittel: struct {
-					KurzText   string `json:"kurzText"`
-					MittelText string `json:"mittelText"`
-					LangText   string `json:"langText"`
-					Name       string `json:"name"`
+					KurzText       string           `json:"kurzText"`
+					MittelText     string           `json:"mittelText"`
+					LangText       string           `json:"langText"`
+					Name           string           `json:"name"`
+					ProduktGattung string           `json:"produktGattung"`
+					Zugattribute   []TrainAttribute `json:"zugattribute"`
 				}{
 					KurzText:   "ICE",
 					MittelText: "ICE 500",
@@ -113,6 +122,54 @@ func TestDepartureResponse_ToDeparture(t *testing.T) {
 	}
 }
 
+func TestDepartureResponse_Messages(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		wantTexts     []string
+		wantCancelled bool
+	}{
+		{
+			name:      "none",
+			raw:       `[]`,
+			wantTexts: nil,
+		},
+		{
+			name:      "two messages",
+			raw:       `[{"type": "QUALITAET", "text": "Reparatur an einem Signal"}, {"type": "QUALITAET", "text": "Bauarbeiten"}]`,
+			wantTexts: []string{"Reparatur an einem Signal", "Bauarbeiten"},
+		},
+		{
+			name:          "cancellation marker dropped",
+			raw:           `[{"type": "HALT_AUSFALL", "text": "Halt entfällt"}, {"type": "QUALITAET", "text": "Bauarbeiten"}]`,
+			wantTexts:     []string{"Bauarbeiten"},
+			wantCancelled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r DepartureResponse
+			raw := `{"journeyId": "j1", "terminus": "Bonn Hbf", "zeit": "2025-01-15T10:00:00", "meldungen": ` + tt.raw + `}`
+			if err := json.Unmarshal([]byte(raw), &r); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			dep := r.ToDeparture(time.UTC)
+			if dep.IsCancelled != tt.wantCancelled {
+				t.Errorf("IsCancelled = %v, want %v", dep.IsCancelled, tt.wantCancelled)
+			}
+			if len(dep.Messages) != len(tt.wantTexts) {
+				t.Fatalf("got %d messages, want %d", len(dep.Messages), len(tt.wantTexts))
+			}
+			for i, want := range tt.wantTexts {
+				if dep.Messages[i].Text != want {
+					t.Errorf("Messages[%d] = %q, want %q", i, dep.Messages[i].Text, want)
+				}
+			}
+		})
+	}
+}
+
 func TestDeparture_EffectivePlatform(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -153,6 +210,30 @@ func TestDeparture_EffectivePlatform(t *testing.T) {
 	}
 }
 
+func TestPlatformChanged(t *testing.T) {
+	tests := []struct {
+		platform, rtPlatform string
+		want                 bool
+	}{
+		{"5", "7", true},
+		{"5", "5", false},
+		{"5", "", false},
+		{"", "7", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		dep := &Departure{Platform: tt.platform, RTPlatform: tt.rtPlatform}
+		if got := dep.PlatformChanged(); got != tt.want {
+			t.Errorf("Departure{%q, %q}.PlatformChanged() = %v, want %v", tt.platform, tt.rtPlatform, got, tt.want)
+		}
+		stop := &Stop{Platform: tt.platform, RTPlatform: tt.rtPlatform}
+		if got := stop.PlatformChanged(); got != tt.want {
+			t.Errorf("Stop{%q, %q}.PlatformChanged() = %v, want %v", tt.platform, tt.rtPlatform, got, tt.want)
+		}
+	}
+}
+
 func TestDeparturesResponse_JSON(t *testing.T) {
 	jsonData := `{
 		"entries": [
@@ -233,3 +314,305 @@ func TestParseTime(t *testing.T) {
 		})
 	}
 }
+
+func TestDeparture_LineParts(t *testing.T) {
+	tests := []struct {
+		name         string
+		dep          Departure
+		wantCategory string
+		wantNumber   string
+	}{
+		{"ICE with number", Departure{Type: "ICE", Line: "ICE 123"}, "ICE", "123"},
+		{"S-Bahn with space", Departure{Type: "S", Line: "S 1"}, "S", "1"},
+		{"S-Bahn embedded", Departure{Type: "S", Line: "S1"}, "S", "1"},
+		{"U-Bahn embedded", Departure{Type: "U", Line: "U4"}, "U", "4"},
+		{"bus mixed case", Departure{Type: "Bus", Line: "BUS 136"}, "Bus", "136"},
+		{"bare number", Departure{Type: "STR", Line: "18"}, "STR", "18"},
+		{"category only", Departure{Type: "ICE", TrainShort: "ICE"}, "ICE", ""},
+		{"unknown type", Departure{Line: "ICE 123"}, "", "ICE 123"},
+		{"mismatched type", Departure{Type: "STR", Line: "Tram 1"}, "", "Tram 1"},
+		{"prefix of a word", Departure{Type: "S", Line: "SEV 5"}, "", "SEV 5"},
+		{"prefix of a longer category", Departure{Type: "RE", Line: "RE-X 10"}, "", "RE-X 10"},
+		{"prefix of a name", Departure{Type: "Bus", Line: "Bussard 3"}, "", "Bussard 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, number := tt.dep.LineParts()
+			if category != tt.wantCategory {
+				t.Errorf("category = %q, want %q", category, tt.wantCategory)
+			}
+			if number != tt.wantNumber {
+				t.Errorf("number = %q, want %q", number, tt.wantNumber)
+			}
+		})
+	}
+}
+
+func TestDeparture_TransitGroup(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want string
+	}{
+		{"ICE", TransitRail},
+		{"RE", TransitRail},
+		{"S", TransitRail},
+		{"Bus", TransitLocal},
+		{"BusSEV", TransitLocal},
+		{"STR", TransitLocal},
+		{"U", TransitLocal},
+		{"Fähre", TransitLocal},
+		{"", TransitRail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			dep := Departure{Type: tt.typ}
+			if got := dep.TransitGroup(); got != tt.want {
+				t.Errorf("TransitGroup() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDepartureResponse_OnDemand(t *testing.T) {
+	tests := []struct {
+		name    string
+		product string
+		typ     string
+		want    bool
+	}{
+		{"call-ahead bus", "ANRUFPFLICHTIG", "Bus", true},
+		{"regular bus", "BUS", "Bus", false},
+		{"taxi without product", "", "AST", true},
+		{"train without product", "", "RE", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r DepartureResponse
+			r.Verkehrmittel.ProduktGattung = tt.product
+			r.Verkehrmittel.KurzText = tt.typ
+			dep := r.ToDeparture(time.UTC)
+			if dep.OnDemand != tt.want {
+				t.Errorf("OnDemand = %v, want %v", dep.OnDemand, tt.want)
+			}
+			if dep.Product != tt.product {
+				t.Errorf("Product = %q, want %q", dep.Product, tt.product)
+			}
+		})
+	}
+
+	// The flag is part of the JSON output
+	data, err := json.Marshal(Departure{OnDemand: true})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"onDemand":true`) {
+		t.Errorf("JSON = %s, want onDemand field", data)
+	}
+}
+
+func TestStationNameFromID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"A=1@O=Köln Hbf@X=6958730@Y=50943029@U=80@L=8000207@", "Köln Hbf"},
+		{"O=Frankfurt(Main)Hbf@L=8000105@", "Frankfurt(Main)Hbf"},
+		{"A=1@L=8000207@", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := StationNameFromID(tt.id); got != tt.want {
+			t.Errorf("StationNameFromID(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestMarkEndpoints(t *testing.T) {
+	deps := []Departure{
+		{Destination: "Köln Hbf"},
+		{Destination: "München Hbf"},
+	}
+	MarkEndpoints(deps, " köln hbf", false)
+	if !deps[0].EndsHere || deps[0].StartsHere {
+		t.Errorf("departure to board station: EndsHere = %v, StartsHere = %v", deps[0].EndsHere, deps[0].StartsHere)
+	}
+	if deps[1].EndsHere {
+		t.Error("through departure marked as ending here")
+	}
+
+	arrs := []Departure{{Destination: "Köln Hbf"}, {Destination: "Bonn Hbf", Origin: "Köln Hbf"}}
+	MarkEndpoints(arrs, "Köln Hbf", true)
+	if !arrs[0].StartsHere || arrs[0].EndsHere {
+		t.Errorf("arrival from board station: StartsHere = %v, EndsHere = %v", arrs[0].StartsHere, arrs[0].EndsHere)
+	}
+	if !arrs[1].StartsHere {
+		t.Error("arrival with the board station as origin not marked as starting here")
+	}
+
+	// Unknown station name leaves entries untouched
+	other := []Departure{{Destination: ""}}
+	MarkEndpoints(other, "", false)
+	if other[0].EndsHere {
+		t.Error("entry marked without a station name")
+	}
+}
+
+func TestDepartureResponse_ToArrival(t *testing.T) {
+	r := DepartureResponse{Terminus: "Aachen Hbf", Zeit: "2025-01-15T10:00:00"}
+	arr := r.ToArrival(time.UTC)
+	if arr.Origin != "Aachen Hbf" || arr.Destination != "Aachen Hbf" {
+		t.Errorf("got origin %q, destination %q", arr.Origin, arr.Destination)
+	}
+	if got := arr.ArrivalOrigin(); got != "Aachen Hbf" {
+		t.Errorf("ArrivalOrigin() = %q", got)
+	}
+
+	// Departures don't carry an origin; ArrivalOrigin falls back
+	dep := r.ToDeparture(time.UTC)
+	if dep.Origin != "" || dep.ArrivalOrigin() != "Aachen Hbf" {
+		t.Errorf("got origin %q, ArrivalOrigin() %q", dep.Origin, dep.ArrivalOrigin())
+	}
+}
+
+// benchmarkBoardJSON builds a departures payload with n entries resembling a
+// busy board (via stations, delays, messages on some entries).
+func benchmarkBoardJSON(n int) []byte {
+	var resp DeparturesResponse
+	base := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		var e DepartureResponse
+		sched := base.Add(time.Duration(i) * time.Minute)
+		e.JourneyID = fmt.Sprintf("2|#VN#1#ST#1736870400#PI#0#ZI#%d#TA#0#DA#150125#", i)
+		e.BahnhofsID = "8000207"
+		e.Terminus = "München Hbf"
+		e.Gleis = FlexString(strconv.Itoa(i%12 + 1))
+		e.Zeit = sched.Format("2006-01-02T15:04:05")
+		if i%3 != 0 {
+			e.EZZeit = sched.Add(time.Duration(i%9) * time.Minute).Format("2006-01-02T15:04:05")
+		}
+		e.Ueber = []string{"Köln Hbf", "Köln Messe/Deutz", "Siegburg/Bonn", "Montabaur", "Frankfurt(Main)Hbf"}
+		e.Verkehrmittel.KurzText = "ICE"
+		e.Verkehrmittel.MittelText = fmt.Sprintf("ICE %d", 500+i)
+		e.Verkehrmittel.LangText = fmt.Sprintf("ICE %d", 500+i)
+		e.Verkehrmittel.Name = fmt.Sprintf("ICE %d", 500+i)
+		if i%5 == 0 {
+			e.Meldungen = append(e.Meldungen, struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			}{Type: "QUALITAET", Text: "Reparatur an einem Signal"})
+		}
+		resp.Entries = append(resp.Entries, e)
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func TestParseTime_MatchesParseInLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	inputs := []string{
+		"2025-01-15T10:00:00",
+		"2025-03-30T02:30:00", // non-existent local time (DST gap)
+		"2025-10-26T02:30:00", // ambiguous local time (DST overlap)
+		"2024-02-29T23:59:59",
+		"2025-02-29T10:00:00",
+		"2025-13-01T10:00:00",
+		"2025-01-15T24:00:00",
+		"2025-01-15T10:60:00",
+		"2025-01-15 10:00:00",
+		"2025-1-15T10:00:00",
+		"2025-01-15T10:00",
+		"2025-01-15T10:00:00.5",
+		"2025-01-15T10:00:00-01:00",
+		"",
+	}
+
+	for _, in := range inputs {
+		want, wantErr := time.ParseInLocation("2006-01-02T15:04:05", in, loc)
+		got, gotErr := parseTime(in, loc)
+		if (gotErr != nil) != (wantErr != nil) {
+			t.Errorf("parseTime(%q) error = %v, want %v", in, gotErr, wantErr)
+			continue
+		}
+		if !got.Equal(want) || got.Location() != want.Location() {
+			t.Errorf("parseTime(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func BenchmarkDeparturesResponse_Unmarshal(b *testing.B) {
+	data := benchmarkBoardJSON(200)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var resp DeparturesResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkToDeparture(b *testing.B) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		b.Fatal(err)
+	}
+	var resp DeparturesResponse
+	if err := json.Unmarshal(benchmarkBoardJSON(200), &resp); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range resp.Entries {
+			_ = resp.Entries[j].ToDeparture(loc)
+		}
+	}
+}
+
+func BenchmarkParseTime(b *testing.B) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = parseTime("2025-01-15T10:05:00", loc)
+	}
+}
+
+func TestDeparture_ViaIncludes(t *testing.T) {
+	dep := Departure{Destination: "München Hbf", Via: []string{"Bonn Hbf", "Koblenz Hbf"}, ViaLast: "Koblenz Hbf"}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Bonn Hbf", true},
+		{" koblenz hbf ", true},
+		{"München Hbf", true},
+		{"Mainz Hbf", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := dep.ViaIncludes(tt.name); got != tt.want {
+			t.Errorf("ViaIncludes(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	arr := Departure{Destination: "Köln Hbf", Origin: "Aachen Hbf"}
+	if !arr.ViaIncludes("Aachen Hbf") {
+		t.Error("arrival origin not matched")
+	}
+}
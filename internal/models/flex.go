@@ -0,0 +1,58 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// FlexString is a string field the API sometimes sends as a number (e.g.
+// platforms or EVA numbers). Numbers keep their JSON text, null is empty.
+type FlexString string
+
+// UnmarshalJSON accepts a JSON string, number or null
+func (s *FlexString) UnmarshalJSON(data []byte) error {
+	switch {
+	case len(data) == 0 || bytes.Equal(data, []byte("null")):
+		*s = ""
+		return nil
+	case data[0] == '"':
+		// Fast path for the common case without escapes
+		if len(data) >= 2 && bytes.IndexByte(data, '\\') < 0 {
+			*s = FlexString(data[1 : len(data)-1])
+			return nil
+		}
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		*s = FlexString(str)
+		return nil
+	case data[0] == '-' || (data[0] >= '0' && data[0] <= '9'):
+		*s = FlexString(data)
+		return nil
+	}
+	// Booleans, objects and arrays carry no usable text
+	*s = ""
+	return nil
+}
+
+// FlexInt is an integer field the API sometimes sends as a string. Strings
+// that are not plain integers (including "") decode to 0 instead of failing
+// the whole response.
+type FlexInt int64
+
+// UnmarshalJSON accepts a JSON number, numeric string or null
+func (n *FlexInt) UnmarshalJSON(data []byte) error {
+	text := string(bytes.Trim(data, `"`))
+	if v, err := strconv.ParseInt(text, 10, 64); err == nil {
+		*n = FlexInt(v)
+		return nil
+	}
+	if v, err := strconv.ParseFloat(text, 64); err == nil {
+		*n = FlexInt(v)
+		return nil
+	}
+	*n = 0
+	return nil
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFlexString_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want FlexString
+	}{
+		{`"7"`, "7"},
+		{`7`, "7"},
+		{`-1`, "-1"},
+		{`"10 A"`, "10 A"},
+		{`"Köln"`, "Köln"},
+		{`null`, ""},
+		{`true`, ""},
+	}
+	for _, tt := range tests {
+		var got FlexString
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFlexInt_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want FlexInt
+	}{
+		{`8000105`, 8000105},
+		{`"8000105"`, 8000105},
+		{`8.000105e6`, 8000105},
+		{`""`, 0},
+		{`"abc"`, 0},
+		{`null`, 0},
+	}
+	for _, tt := range tests {
+		var got FlexInt
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDepartureResponse_NumericFields(t *testing.T) {
+	for _, body := range []string{
+		`{"bahnhofsId": "8000207", "gleis": "7", "ezGleis": "8", "terminus": "Bonn"}`,
+		`{"bahnhofsId": 8000207, "gleis": 7, "ezGleis": 8, "terminus": "Bonn"}`,
+	} {
+		var resp DepartureResponse
+		if err := json.Unmarshal([]byte(body), &resp); err != nil {
+			t.Fatalf("%s: unmarshal: %v", body, err)
+		}
+		dep := resp.ToDeparture(time.UTC)
+		if dep.StopEVA != "8000207" || dep.Platform != "7" || dep.RTPlatform != "8" {
+			t.Errorf("%s: got eva %q, platform %q/%q", body, dep.StopEVA, dep.Platform, dep.RTPlatform)
+		}
+	}
+}
+
+func TestJourneyResponse_NumericFields(t *testing.T) {
+	for _, body := range []string{
+		`{"halte": [{"name": "Köln Hbf", "evaNumber": 8000207, "gleis": "7", "nummer": "10523", "adminID": "800725"}]}`,
+		`{"halte": [{"name": "Köln Hbf", "evaNumber": "8000207", "gleis": 7, "nummer": 10523, "adminID": 800725}]}`,
+	} {
+		var resp JourneyResponse
+		if err := json.Unmarshal([]byte(body), &resp); err != nil {
+			t.Fatalf("%s: unmarshal: %v", body, err)
+		}
+		j := resp.ToJourney("id", time.UTC)
+		if stop := j.Stops[0]; stop.EVA != 8000207 || stop.Platform != "7" {
+			t.Errorf("%s: got eva %d, platform %q", body, stop.EVA, stop.Platform)
+		}
+		if j.TripNo != "10523" {
+			t.Errorf("%s: got trip number %q", body, j.TripNo)
+		}
+	}
+}
+
+func TestLocationResponse_NumericFields(t *testing.T) {
+	for _, body := range []string{
+		`{"extId": "8000207", "name": "Köln Hbf"}`,
+		`{"extId": 8000207, "name": "Köln Hbf"}`,
+		`{"evaNumber": "8000207", "name": "Köln Hbf"}`,
+	} {
+		var resp LocationResponse
+		if err := json.Unmarshal([]byte(body), &resp); err != nil {
+			t.Fatalf("%s: unmarshal: %v", body, err)
+		}
+		if loc := resp.ToLocation(); loc.EVA != 8000207 {
+			t.Errorf("%s: got eva %d", body, loc.EVA)
+		}
+	}
+}
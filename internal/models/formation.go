@@ -15,6 +15,171 @@ type Formation struct {
 	Groups       []Group    `json:"groups"`
 	Destinations []string   `json:"destinations,omitempty"`
 	TrainNumbers []string   `json:"trainNumbers,omitempty"`
+
+	// Derived holds values computed from the fields above rather than
+	// taken from the API response
+	Derived FormationDerived `json:"derived"`
+}
+
+// FormationDerived contains computed boarding information for a formation
+type FormationDerived struct {
+	Boarding []BoardingHint    `json:"boarding,omitempty"`
+	Front    *TrainFront       `json:"front,omitempty"`
+	Coverage *PlatformCoverage `json:"coverage,omitempty"`
+}
+
+// PlatformCoverage describes which platform sectors the train occupies
+type PlatformCoverage struct {
+	Sectors     []string `json:"sectors"` // in platform order
+	Partial     bool     `json:"partial"` // at least one sector stays empty
+	TrainMeters float64  `json:"trainMeters,omitempty"`
+}
+
+// TrainFront describes which end of the formation leads in the direction of travel
+type TrainFront struct {
+	Side        string `json:"side"` // "left" or "right" end of the platform diagram
+	Carriage    string `json:"carriage,omitempty"`
+	Sector      string `json:"sector,omitempty"`
+	Destination string `json:"destination,omitempty"`
+}
+
+// BoardingHint maps a carriage attribute to where it stops on the platform
+type BoardingHint struct {
+	Attribute string   `json:"attribute"` // e.g. "firstClass", "bistro"
+	Sectors   []string `json:"sectors"`
+	Carriages []string `json:"carriages"`
+}
+
+// boardingAttributes lists the attributes for which boarding hints are derived,
+// in output order
+var boardingAttributes = []struct {
+	name  string
+	match func(c *Carriage) bool
+}{
+	{"firstClass", func(c *Carriage) bool { return hasClass(c, 1) }},
+	{"secondClass", func(c *Carriage) bool { return hasClass(c, 2) }},
+	{"bistro", func(c *Carriage) bool { return c.HasBistro }},
+	{"quietZone", func(c *Carriage) bool { return c.HasQuietZone }},
+	{"familyZone", func(c *Carriage) bool { return c.HasFamilyZone }},
+	{"wheelchairSpace", func(c *Carriage) bool { return c.HasWheelchairSpace }},
+	{"bahnComfort", func(c *Carriage) bool { return c.HasBahnComfort }},
+}
+
+// hasClass reports whether a carriage offers the given class (1 or 2). The
+// explicit API flags win; the construction type heuristic is only used when
+// neither flag is set.
+func hasClass(c *Carriage, class int) bool {
+	if c.HasFirstClass || c.HasSecondClass {
+		if class == 1 {
+			return c.HasFirstClass
+		}
+		return c.HasSecondClass
+	}
+	return c.ClassType == class || c.ClassType == 12
+}
+
+// computeBoarding derives, for each boarding attribute, the platform sectors
+// and carriage numbers where it can be found. Closed carriages, locomotives
+// and power cars are skipped. Carriages must already be sorted by position.
+func (f *Formation) computeBoarding() {
+	f.Derived.Boarding = nil
+	for _, attr := range boardingAttributes {
+		hint := BoardingHint{Attribute: attr.name}
+		seenSector := make(map[string]bool)
+		for i := range f.Carriages {
+			c := &f.Carriages[i]
+			if c.IsClosed || c.IsLocomotive || c.IsPowercar || !attr.match(c) {
+				continue
+			}
+			if c.Section != "" && !seenSector[c.Section] {
+				seenSector[c.Section] = true
+				hint.Sectors = append(hint.Sectors, c.Section)
+			}
+			if c.Number != "" {
+				hint.Carriages = append(hint.Carriages, c.Number)
+			}
+		}
+		if len(hint.Sectors) > 0 || len(hint.Carriages) > 0 {
+			f.Derived.Boarding = append(f.Derived.Boarding, hint)
+		}
+	}
+}
+
+// computeFront derives the leading end of the train from Direction. With
+// direction 100 the train travels toward the right end of the platform
+// diagram, otherwise toward the left. Carriages and groups must already be
+// sorted by position.
+func (f *Formation) computeFront() {
+	f.Derived.Front = nil
+	if len(f.Carriages) == 0 {
+		return
+	}
+
+	front := &TrainFront{Side: "left"}
+	idx := func(i int) int { return i }
+	if f.Direction == 100 {
+		front.Side = "right"
+		idx = func(i int) int { return len(f.Carriages) - 1 - i }
+	}
+
+	// Sector comes from the very first vehicle, the carriage number from the
+	// first numbered passenger carriage behind any locomotive
+	front.Sector = f.Carriages[idx(0)].Section
+	for i := range f.Carriages {
+		c := &f.Carriages[idx(i)]
+		if c.IsLocomotive || c.IsPowercar || c.Number == "" {
+			continue
+		}
+		front.Carriage = c.Number
+		if front.Sector == "" {
+			front.Sector = c.Section
+		}
+		break
+	}
+
+	// Trains that split are headed by the group at the leading end
+	if len(f.Groups) > 0 {
+		g := f.Groups[0]
+		if f.Direction == 100 {
+			g = f.Groups[len(f.Groups)-1]
+		}
+		front.Destination = g.Destination
+	}
+
+	f.Derived.Front = front
+}
+
+// computeCoverage derives the sectors the train occupies from carriage
+// positions, falling back to the carriage's sector name. It is left nil
+// without sector or carriage data.
+func (f *Formation) computeCoverage() {
+	f.Derived.Coverage = nil
+	if len(f.Sectors) == 0 || len(f.Carriages) == 0 {
+		return
+	}
+
+	cov := &PlatformCoverage{}
+	for _, s := range f.Sectors {
+		for i := range f.Carriages {
+			c := &f.Carriages[i]
+			overlaps := c.StartPercent < s.EndPercent && c.EndPercent > s.StartPercent
+			if overlaps || c.Section == s.Name {
+				cov.Sectors = append(cov.Sectors, s.Name)
+				break
+			}
+		}
+	}
+	if len(cov.Sectors) == 0 {
+		return
+	}
+	cov.Partial = len(cov.Sectors) < len(f.Sectors)
+
+	first, last := f.Carriages[0], f.Carriages[len(f.Carriages)-1]
+	if length := last.EndMeters - first.StartMeters; length > 0 {
+		cov.TrainMeters = length
+	}
+
+	f.Derived.Coverage = cov
 }
 
 // Sector represents a platform sector/zone
@@ -151,6 +316,11 @@ func (r *FormationResponse) ToFormation(trainType string) *Formation {
 	destSet := make(map[string]bool)
 	trainNoSet := make(map[string]bool)
 
+	// Positions of the first and last vehicle in API order, which lists
+	// vehicles front to back
+	var firstStart, lastStart float64
+	haveFirst := false
+
 	for _, g := range r.Groups {
 		// Handle number which can be string or int
 		trainNo := ""
@@ -182,6 +352,11 @@ func (r *FormationResponse) ToFormation(trainType string) *Formation {
 			carriage := parseCarriage(v, r.Platform.Start, platformLength)
 			group.Carriages = append(group.Carriages, carriage)
 			f.Carriages = append(f.Carriages, carriage)
+			if !haveFirst {
+				firstStart = carriage.StartPercent
+				haveFirst = true
+			}
+			lastStart = carriage.StartPercent
 
 			if carriage.Section != "" {
 				sectorSet[carriage.Section] = true
@@ -221,11 +396,9 @@ func (r *FormationResponse) ToFormation(trainType string) *Formation {
 		return f.Carriages[i].StartPercent < f.Carriages[j].StartPercent
 	})
 
-	// Determine direction
-	if len(f.Carriages) > 1 {
-		if f.Carriages[0].StartPercent > f.Carriages[len(f.Carriages)-1].StartPercent {
-			f.Direction = 100
-		}
+	// Determine direction from API order; the sorted slice always ascends
+	if len(f.Carriages) > 1 && firstStart > lastStart {
+		f.Direction = 100
 	}
 
 	// Collect destinations and train numbers
@@ -236,6 +409,10 @@ func (r *FormationResponse) ToFormation(trainType string) *Formation {
 		f.TrainNumbers = append(f.TrainNumbers, t)
 	}
 
+	f.computeBoarding()
+	f.computeFront()
+	f.computeCoverage()
+
 	return f
 }
 
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFormationResponse_ToFormation_Boarding(t *testing.T) {
+	jsonData := `{
+		"departurePlatform": "7",
+		"platform": {
+			"start": 0,
+			"end": 400,
+			"sectors": [
+				{"name": "A", "start": 0, "end": 100},
+				{"name": "B", "start": 100, "end": 200},
+				{"name": "C", "start": 200, "end": 300}
+			]
+		},
+		"groups": [{
+			"name": "ICE0301",
+			"transport": {"category": "ICE", "number": 623, "destination": {"name": "München Hbf"}},
+			"vehicles": [
+				{"wagonIdentificationNumber": 11, "type": {"constructionType": "Apmzf", "category": "PASSENGERCARRIAGE_FIRST_CLASS", "hasFirstClass": true},
+				 "platformPosition": {"start": 0, "end": 50, "sector": "A"},
+				 "amenities": [{"type": "ZONE_QUIET"}]},
+				{"wagonIdentificationNumber": 12, "type": {"constructionType": "WRmz", "category": "DININGCAR"},
+				 "platformPosition": {"start": 100, "end": 150, "sector": "B"}},
+				{"wagonIdentificationNumber": 13, "status": "CLOSED", "type": {"constructionType": "Bpmz", "category": "PASSENGERCARRIAGE_ECONOMY_CLASS", "hasEconomyClass": true},
+				 "platformPosition": {"start": 150, "end": 200, "sector": "B"}},
+				{"wagonIdentificationNumber": 14, "type": {"constructionType": "Bpmz", "category": "PASSENGERCARRIAGE_ECONOMY_CLASS", "hasEconomyClass": true},
+				 "platformPosition": {"start": 200, "end": 250, "sector": "C"},
+				 "amenities": [{"type": "ZONE_FAMILY"}, {"type": "WHEELCHAIR_SPACE"}]}
+			]
+		}]
+	}`
+
+	var resp FormationResponse
+	if err := json.Unmarshal([]byte(jsonData), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	f := resp.ToFormation("ICE")
+
+	hints := make(map[string]BoardingHint)
+	for _, h := range f.Derived.Boarding {
+		hints[h.Attribute] = h
+	}
+
+	tests := []struct {
+		attribute     string
+		wantSectors   string
+		wantCarriages string
+	}{
+		{"firstClass", "A", "11"},
+		{"secondClass", "BC", "1214"}, // closed carriage 13 is skipped
+		{"bistro", "B", "12"},
+		{"quietZone", "A", "11"},
+		{"familyZone", "C", "14"},
+		{"wheelchairSpace", "C", "14"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.attribute, func(t *testing.T) {
+			h, ok := hints[tt.attribute]
+			if !ok {
+				t.Fatalf("missing boarding hint for %s", tt.attribute)
+			}
+			if got := strings.Join(h.Sectors, ""); got != tt.wantSectors {
+				t.Errorf("sectors = %q, want %q", got, tt.wantSectors)
+			}
+			if got := strings.Join(h.Carriages, ""); got != tt.wantCarriages {
+				t.Errorf("carriages = %q, want %q", got, tt.wantCarriages)
+			}
+		})
+	}
+
+	if _, ok := hints["bahnComfort"]; ok {
+		t.Error("bahnComfort hint should be omitted when no carriage has it")
+	}
+}
+
+func TestFormationResponse_ToFormation_Coverage(t *testing.T) {
+	jsonData := `{
+		"platform": {
+			"start": 0,
+			"end": 400,
+			"sectors": [
+				{"name": "A", "start": 0, "end": 100},
+				{"name": "B", "start": 100, "end": 200},
+				{"name": "C", "start": 200, "end": 300},
+				{"name": "D", "start": 300, "end": 400}
+			]
+		},
+		"groups": [{
+			"transport": {"category": "RE", "number": 5},
+			"vehicles": [
+				{"wagonIdentificationNumber": 1, "platformPosition": {"start": 120, "end": 190}},
+				{"wagonIdentificationNumber": 2, "platformPosition": {"start": 190, "end": 260}}
+			]
+		}]
+	}`
+
+	var resp FormationResponse
+	if err := json.Unmarshal([]byte(jsonData), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	cov := resp.ToFormation("RE").Derived.Coverage
+	if cov == nil {
+		t.Fatal("expected coverage")
+	}
+	if got := strings.Join(cov.Sectors, ""); got != "BC" {
+		t.Errorf("sectors = %q, want %q", got, "BC")
+	}
+	if !cov.Partial {
+		t.Error("expected partial coverage")
+	}
+	if cov.TrainMeters != 140 {
+		t.Errorf("train length = %v, want 140", cov.TrainMeters)
+	}
+
+	// Without sector data there is nothing to compare against
+	resp.Platform.Sectors = nil
+	if cov := resp.ToFormation("RE").Derived.Coverage; cov != nil {
+		t.Errorf("coverage = %+v, want nil", cov)
+	}
+}
+
+func TestFormationResponse_ToFormation_Front(t *testing.T) {
+	tests := []struct {
+		name         string
+		vehicles     string
+		wantDir      int
+		wantSide     string
+		wantCarriage string
+		wantSector   string
+	}{
+		{
+			name: "front at left end",
+			vehicles: `[
+				{"wagonIdentificationNumber": 1, "platformPosition": {"start": 0, "end": 50, "sector": "A"}},
+				{"wagonIdentificationNumber": 2, "platformPosition": {"start": 50, "end": 100, "sector": "B"}}
+			]`,
+			wantDir: 0, wantSide: "left", wantCarriage: "1", wantSector: "A",
+		},
+		{
+			name: "front at right end behind locomotive",
+			vehicles: `[
+				{"type": {"category": "LOCOMOTIVE"}, "platformPosition": {"start": 100, "end": 120, "sector": "C"}},
+				{"wagonIdentificationNumber": 1, "platformPosition": {"start": 50, "end": 100, "sector": "B"}},
+				{"wagonIdentificationNumber": 2, "platformPosition": {"start": 0, "end": 50, "sector": "A"}}
+			]`,
+			wantDir: 100, wantSide: "right", wantCarriage: "1", wantSector: "C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonData := `{"platform": {"start": 0, "end": 120}, "groups": [{
+				"name": "IC2010",
+				"transport": {"category": "IC", "number": 2010, "destination": {"name": "München Hbf"}},
+				"vehicles": ` + tt.vehicles + `}]}`
+
+			var resp FormationResponse
+			if err := json.Unmarshal([]byte(jsonData), &resp); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			f := resp.ToFormation("IC")
+
+			if f.Direction != tt.wantDir {
+				t.Errorf("Direction = %d, want %d", f.Direction, tt.wantDir)
+			}
+			front := f.Derived.Front
+			if front == nil {
+				t.Fatal("expected front to be derived")
+			}
+			if front.Side != tt.wantSide || front.Carriage != tt.wantCarriage || front.Sector != tt.wantSector {
+				t.Errorf("front = %+v, want side %s, carriage %s, sector %s", *front, tt.wantSide, tt.wantCarriage, tt.wantSector)
+			}
+			if front.Destination != "München Hbf" {
+				t.Errorf("Destination = %q, want %q", front.Destination, "München Hbf")
+			}
+		})
+	}
+}
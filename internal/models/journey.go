@@ -23,6 +23,13 @@ type Journey struct {
 	IsCancelled bool       `json:"isCancelled"`
 	Stops       []Stop     `json:"stops"`
 	Messages    []Message  `json:"messages,omitempty"`
+	Polyline    []Coord    `json:"polyline,omitempty"`
+}
+
+// Coord is a single point of a journey's route geometry
+type Coord struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
 }
 
 // Stop represents a single stop along a journey route
@@ -44,6 +51,24 @@ type Stop struct {
 	Delay        int        `json:"delay,omitempty"`
 	IsCancelled  bool       `json:"isCancelled"`
 	IsAdditional bool       `json:"isAdditional"`
+	Train        string     `json:"train,omitempty"`    // e.g. "RE 10523", for journeys with several trains
+	Operator     string     `json:"operator,omitempty"` // operator serving this stop
+	// OccupancyFirst and OccupancySecond forecast how full the train is
+	// when leaving this stop
+	OccupancyFirst  Occupancy `json:"occupancyFirst,omitempty"`
+	OccupancySecond Occupancy `json:"occupancySecond,omitempty"`
+	// Accessible tells whether the train can be boarded step-free at this
+	// stop
+	Accessible Accessibility `json:"accessible,omitempty"`
+}
+
+// Leg is a run of consecutive journey stops served by the same train and
+// operator. From and To are inclusive stop indices.
+type Leg struct {
+	Train    string
+	Operator string
+	From     int
+	To       int
 }
 
 // JourneyResponse represents the raw API response for a journey
@@ -52,21 +77,21 @@ type JourneyResponse struct {
 	ZugName   string `json:"zugName"`
 	Cancelled bool   `json:"cancelled"`
 	Halte     []struct {
-		Name                  string `json:"name"`
-		ExtID                 string `json:"extId"`
-		EVANumber             int64  `json:"evaNumber"`
-		ID                    string `json:"id"`
-		Gleis                 string `json:"gleis"`
-		EZGleis               string `json:"ezGleis"`
-		AbfahrtsZeitpunkt     string `json:"abfahrtsZeitpunkt"`
-		EZAbfahrtsZeitpunkt   string `json:"ezAbfahrtsZeitpunkt"`
-		AnkunftsZeitpunkt     string `json:"ankunftsZeitpunkt"`
-		EZAnkunftsZeitpunkt   string `json:"ezAnkunftsZeitpunkt"`
-		AdminID               string `json:"adminID"`
-		Nummer                string `json:"nummer"`
-		Kategorie             string `json:"kategorie"`
-		Canceled              bool   `json:"canceled"`
-		Additional            bool   `json:"additional"`
+		Name                  string     `json:"name"`
+		ExtID                 FlexString `json:"extId"`
+		EVANumber             FlexInt    `json:"evaNumber"`
+		ID                    string     `json:"id"`
+		Gleis                 FlexString `json:"gleis"`
+		EZGleis               FlexString `json:"ezGleis"`
+		AbfahrtsZeitpunkt     string     `json:"abfahrtsZeitpunkt"`
+		EZAbfahrtsZeitpunkt   string     `json:"ezAbfahrtsZeitpunkt"`
+		AnkunftsZeitpunkt     string     `json:"ankunftsZeitpunkt"`
+		EZAnkunftsZeitpunkt   string     `json:"ezAnkunftsZeitpunkt"`
+		AdminID               FlexString `json:"adminID"`
+		Nummer                FlexString `json:"nummer"`
+		Kategorie             string     `json:"kategorie"`
+		Canceled              bool       `json:"canceled"`
+		Additional            bool       `json:"additional"`
 		PriorisierteMeldungen []struct {
 			Type string `json:"type"`
 			Text string `json:"text"`
@@ -75,7 +100,10 @@ type JourneyResponse struct {
 			Key   string `json:"key"`
 			Value string `json:"value"`
 		} `json:"risMeldungen"`
+		Auslastungsmeldungen []OccupancyReport `json:"auslastungsmeldungen"`
+		Zugattribute         []TrainAttribute  `json:"zugattribute"`
 	} `json:"halte"`
+	Zugattribute []TrainAttribute `json:"zugattribute"`
 	HimMeldungen []struct {
 		Prioritaet   string `json:"prioritaet"`
 		Ueberschrift string `json:"ueberschrift"`
@@ -85,6 +113,14 @@ type JourneyResponse struct {
 		Type string `json:"type"`
 		Text string `json:"text"`
 	} `json:"priorisierteMeldungen"`
+	PolylineGroup *struct {
+		PolylineDescriptions []struct {
+			Coordinates []struct {
+				Lng float64 `json:"lng"`
+				Lat float64 `json:"lat"`
+			} `json:"coordinates"`
+		} `json:"polylineDescriptions"`
+	} `json:"polylineGroup"`
 }
 
 // ToJourney converts the raw response to a Journey
@@ -123,17 +159,28 @@ func (r *JourneyResponse) ToJourney(id string, loc *time.Location) *Journey {
 	for _, h := range r.Halte {
 		stop := Stop{
 			Name:         h.Name,
-			EVA:          h.EVANumber,
-			Platform:     h.Gleis,
-			RTPlatform:   h.EZGleis,
+			EVA:          int64(h.EVANumber),
+			Platform:     string(h.Gleis),
+			RTPlatform:   string(h.EZGleis),
 			IsCancelled:  h.Canceled,
 			IsAdditional: h.Additional,
+			Train:        strings.TrimSpace(h.Kategorie + " " + string(h.Nummer)),
+		}
+		stop.OccupancyFirst, stop.OccupancySecond = parseOccupancy(h.Auslastungsmeldungen)
+		// Stops served by another train carry its attributes; the others
+		// inherit those of the journey
+		stop.Accessible = parseAccessibility(h.Zugattribute)
+		if len(h.Zugattribute) == 0 {
+			stop.Accessible = parseAccessibility(r.Zugattribute)
+		}
+		if h.AdminID != "" {
+			stop.Operator = operators.GetOperatorName(string(h.AdminID))
 		}
 
 		// Parse EVA from extId if needed
 		if stop.EVA == 0 && h.ExtID != "" {
 			// Try to parse as int
-			stop.EVA = parseIntFromString(h.ExtID)
+			stop.EVA = parseIntFromString(string(h.ExtID))
 		}
 
 		// Parse coordinates from ID
@@ -212,13 +259,13 @@ func (r *JourneyResponse) ToJourney(id string, loc *time.Location) *Journey {
 
 		// Count for most common values
 		if h.AdminID != "" {
-			adminIDCount[h.AdminID]++
+			adminIDCount[string(h.AdminID)]++
 		}
 		if h.Kategorie != "" {
 			typeCount[h.Kategorie]++
 		}
 		if h.Nummer != "" {
-			tripNoCount[h.Nummer]++
+			tripNoCount[string(h.Nummer)]++
 		}
 	}
 
@@ -251,9 +298,68 @@ func (r *JourneyResponse) ToJourney(id string, loc *time.Location) *Journey {
 		})
 	}
 
+	// Route geometry, only present when requested with poly=true
+	if r.PolylineGroup != nil {
+		for _, desc := range r.PolylineGroup.PolylineDescriptions {
+			for _, c := range desc.Coordinates {
+				if c.Lat == 0 && c.Lng == 0 {
+					continue
+				}
+				j.Polyline = append(j.Polyline, Coord{Lat: c.Lat, Lon: c.Lng})
+			}
+		}
+	}
+
 	return j
 }
 
+// CallsAt reports whether the journey has a stop at the given EVA number
+// that is not cancelled
+func (j *Journey) CallsAt(eva int64) bool {
+	for _, stop := range j.Stops {
+		if stop.EVA == eva && !stop.IsCancelled {
+			return true
+		}
+	}
+	return false
+}
+
+// Legs splits the stops into legs wherever the train or operator changes.
+// Stops without attribution belong to the surrounding leg. A journey served
+// by a single train has one leg.
+func (j *Journey) Legs() []Leg {
+	var legs []Leg
+	for i, stop := range j.Stops {
+		if len(legs) == 0 {
+			legs = append(legs, Leg{Train: stop.Train, Operator: stop.Operator, From: i, To: i})
+			continue
+		}
+
+		// Attribution missing on earlier stops is taken from later ones
+		leg := &legs[len(legs)-1]
+		if leg.Train == "" {
+			leg.Train = stop.Train
+		}
+		if leg.Operator == "" {
+			leg.Operator = stop.Operator
+		}
+
+		if (stop.Train != "" && stop.Train != leg.Train) || (stop.Operator != "" && stop.Operator != leg.Operator) {
+			next := Leg{Train: stop.Train, Operator: stop.Operator, From: i, To: i}
+			if next.Train == "" {
+				next.Train = leg.Train
+			}
+			if next.Operator == "" {
+				next.Operator = leg.Operator
+			}
+			legs = append(legs, next)
+			continue
+		}
+		leg.To = i
+	}
+	return legs
+}
+
 // Helper to get the platform (effective)
 func (s *Stop) EffectivePlatform() string {
 	if s.RTPlatform != "" {
@@ -262,6 +368,12 @@ func (s *Stop) EffectivePlatform() string {
 	return s.Platform
 }
 
+// PlatformChanged reports whether the train calls at another platform than
+// scheduled
+func (s *Stop) PlatformChanged() bool {
+	return s.Platform != "" && s.RTPlatform != "" && s.RTPlatform != s.Platform
+}
+
 // Helper to find most common value
 func mostCommon(m map[string]int) string {
 	var maxKey string
@@ -1,7 +1,10 @@
 package models
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseIntFromString_PlainNumber(t *testing.T) {
@@ -40,21 +43,21 @@ func TestToJourney_EVAFromHafasExtID(t *testing.T) {
 	resp := &JourneyResponse{
 		ZugName: "Bus 150",
 		Halte: []struct {
-			Name                  string `json:"name"`
-			ExtID                 string `json:"extId"`
-			EVANumber             int64  `json:"evaNumber"`
-			ID                    string `json:"id"`
-			Gleis                 string `json:"gleis"`
-			EZGleis               string `json:"ezGleis"`
-			AbfahrtsZeitpunkt     string `json:"abfahrtsZeitpunkt"`
-			EZAbfahrtsZeitpunkt   string `json:"ezAbfahrtsZeitpunkt"`
-			AnkunftsZeitpunkt     string `json:"ankunftsZeitpunkt"`
-			EZAnkunftsZeitpunkt   string `json:"ezAnkunftsZeitpunkt"`
-			AdminID               string `json:"adminID"`
-			Nummer                string `json:"nummer"`
-			Kategorie             string `json:"kategorie"`
-			Canceled              bool   `json:"canceled"`
-			Additional            bool   `json:"additional"`
+			Name                  string     `json:"name"`
+			ExtID                 FlexString `json:"extId"`
+			EVANumber             FlexInt    `json:"evaNumber"`
+			ID                    string     `json:"id"`
+			Gleis                 FlexString `json:"gleis"`
+			EZGleis               FlexString `json:"ezGleis"`
+			AbfahrtsZeitpunkt     string     `json:"abfahrtsZeitpunkt"`
+			EZAbfahrtsZeitpunkt   string     `json:"ezAbfahrtsZeitpunkt"`
+			AnkunftsZeitpunkt     string     `json:"ankunftsZeitpunkt"`
+			EZAnkunftsZeitpunkt   string     `json:"ezAnkunftsZeitpunkt"`
+			AdminID               FlexString `json:"adminID"`
+			Nummer                FlexString `json:"nummer"`
+			Kategorie             string     `json:"kategorie"`
+			Canceled              bool       `json:"canceled"`
+			Additional            bool       `json:"additional"`
 			PriorisierteMeldungen []struct {
 				Type string `json:"type"`
 				Text string `json:"text"`
@@ -63,6 +66,8 @@ func TestToJourney_EVAFromHafasExtID(t *testing.T) {
 				Key   string `json:"key"`
 				Value string `json:"value"`
 			} `json:"risMeldungen"`
+			Auslastungsmeldungen []OccupancyReport `json:"auslastungsmeldungen"`
+			Zugattribute         []TrainAttribute  `json:"zugattribute"`
 		}{
 			{
 				Name:      "Mülheim Keupstr., Köln",
@@ -85,21 +90,21 @@ func TestToJourney_EVAFromEVANumber(t *testing.T) {
 	resp := &JourneyResponse{
 		ZugName: "ICE 123",
 		Halte: []struct {
-			Name                  string `json:"name"`
-			ExtID                 string `json:"extId"`
-			EVANumber             int64  `json:"evaNumber"`
-			ID                    string `json:"id"`
-			Gleis                 string `json:"gleis"`
-			EZGleis               string `json:"ezGleis"`
-			AbfahrtsZeitpunkt     string `json:"abfahrtsZeitpunkt"`
-			EZAbfahrtsZeitpunkt   string `json:"ezAbfahrtsZeitpunkt"`
-			AnkunftsZeitpunkt     string `json:"ankunftsZeitpunkt"`
-			EZAnkunftsZeitpunkt   string `json:"ezAnkunftsZeitpunkt"`
-			AdminID               string `json:"adminID"`
-			Nummer                string `json:"nummer"`
-			Kategorie             string `json:"kategorie"`
-			Canceled              bool   `json:"canceled"`
-			Additional            bool   `json:"additional"`
+			Name                  string     `json:"name"`
+			ExtID                 FlexString `json:"extId"`
+			EVANumber             FlexInt    `json:"evaNumber"`
+			ID                    string     `json:"id"`
+			Gleis                 FlexString `json:"gleis"`
+			EZGleis               FlexString `json:"ezGleis"`
+			AbfahrtsZeitpunkt     string     `json:"abfahrtsZeitpunkt"`
+			EZAbfahrtsZeitpunkt   string     `json:"ezAbfahrtsZeitpunkt"`
+			AnkunftsZeitpunkt     string     `json:"ankunftsZeitpunkt"`
+			EZAnkunftsZeitpunkt   string     `json:"ezAnkunftsZeitpunkt"`
+			AdminID               FlexString `json:"adminID"`
+			Nummer                FlexString `json:"nummer"`
+			Kategorie             string     `json:"kategorie"`
+			Canceled              bool       `json:"canceled"`
+			Additional            bool       `json:"additional"`
 			PriorisierteMeldungen []struct {
 				Type string `json:"type"`
 				Text string `json:"text"`
@@ -108,6 +113,8 @@ func TestToJourney_EVAFromEVANumber(t *testing.T) {
 				Key   string `json:"key"`
 				Value string `json:"value"`
 			} `json:"risMeldungen"`
+			Auslastungsmeldungen []OccupancyReport `json:"auslastungsmeldungen"`
+			Zugattribute         []TrainAttribute  `json:"zugattribute"`
 		}{
 			{
 				Name:      "Frankfurt Hbf",
@@ -122,3 +129,139 @@ func TestToJourney_EVAFromEVANumber(t *testing.T) {
 		t.Errorf("EVA: got %d, want 8000105", journey.Stops[0].EVA)
 	}
 }
+
+func TestToJourney_Polyline(t *testing.T) {
+	body := `{
+		"zugName": "RE 1",
+		"halte": [{"name": "Köln Hbf", "evaNumber": 8000207}],
+		"polylineGroup": {"polylineDescriptions": [
+			{"coordinates": [{"lng": 6.958, "lat": 50.943}, {"lng": 6.962, "lat": 50.947}]},
+			{"coordinates": [{"lng": 0, "lat": 0}, {"lng": 6.975, "lat": 50.941}]}
+		]}
+	}`
+	var resp JourneyResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	journey := resp.ToJourney("test-id", time.UTC)
+	want := []Coord{{Lat: 50.943, Lon: 6.958}, {Lat: 50.947, Lon: 6.962}, {Lat: 50.941, Lon: 6.975}}
+	if len(journey.Polyline) != len(want) {
+		t.Fatalf("Polyline: got %d points, want %d", len(journey.Polyline), len(want))
+	}
+	for i, c := range want {
+		if journey.Polyline[i] != c {
+			t.Errorf("Polyline[%d]: got %+v, want %+v", i, journey.Polyline[i], c)
+		}
+	}
+}
+
+func TestJourney_PolylineJSON(t *testing.T) {
+	body := `{
+		"zugName": "S 11",
+		"halte": [],
+		"polylineGroup": {"polylineDescriptions": [
+			{"coordinates": [{"lng": 6.958, "lat": 50.943}, {"lng": 6.97, "lat": 50.95}]}
+		]}
+	}`
+	var resp JourneyResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	data, err := json.Marshal(resp.ToJourney("test-id", time.UTC))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `"polyline":[{"lat":50.943,"lon":6.958},{"lat":50.95,"lon":6.97}]`
+	if !strings.Contains(string(data), want) {
+		t.Errorf("JSON %s lacks %s", data, want)
+	}
+
+	// Journeys fetched without the polyline leave the field out
+	data, _ = json.Marshal(Journey{ID: "test-id"})
+	if strings.Contains(string(data), "polyline") {
+		t.Errorf("JSON %s has a polyline", data)
+	}
+}
+
+func TestToJourney_NoPolyline(t *testing.T) {
+	var resp JourneyResponse
+	if err := json.Unmarshal([]byte(`{"zugName": "RE 1", "halte": []}`), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if journey := resp.ToJourney("test-id", time.UTC); journey.Polyline != nil {
+		t.Errorf("Polyline: got %v, want nil", journey.Polyline)
+	}
+}
+
+func TestToJourney_StopAttribution(t *testing.T) {
+	body := `{"zugName": "RE 5", "halte": [
+		{"name": "Köln Hbf", "kategorie": "RE", "nummer": "10523", "adminID": "800725"},
+		{"name": "Bonn Hbf"}
+	]}`
+	var resp JourneyResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	j := resp.ToJourney("id", time.UTC)
+
+	if got := j.Stops[0].Train; got != "RE 10523" {
+		t.Errorf("Train = %q, want %q", got, "RE 10523")
+	}
+	if j.Stops[0].Operator == "" {
+		t.Error("expected the operator to be resolved from the admin ID")
+	}
+	if j.Stops[1].Train != "" || j.Stops[1].Operator != "" {
+		t.Errorf("unattributed stop got %q/%q", j.Stops[1].Train, j.Stops[1].Operator)
+	}
+}
+
+func TestJourney_Legs(t *testing.T) {
+	j := &Journey{Stops: []Stop{
+		{Name: "Wien Hbf"},
+		{Name: "Linz Hbf", Train: "RJ 62", Operator: "ÖBB"},
+		{Name: "Salzburg Hbf", Train: "RJ 62"},
+		{Name: "München Hbf", Train: "RJ 62", Operator: "DB Fernverkehr AG"},
+		{Name: "Augsburg Hbf", Train: "ICE 1012", Operator: "DB Fernverkehr AG"},
+		{Name: "Ulm Hbf"},
+	}}
+
+	legs := j.Legs()
+	want := []Leg{
+		{Train: "RJ 62", Operator: "ÖBB", From: 0, To: 2},
+		{Train: "RJ 62", Operator: "DB Fernverkehr AG", From: 3, To: 3},
+		{Train: "ICE 1012", Operator: "DB Fernverkehr AG", From: 4, To: 5},
+	}
+	if len(legs) != len(want) {
+		t.Fatalf("got %d legs, want %d: %+v", len(legs), len(want), legs)
+	}
+	for i := range want {
+		if legs[i] != want[i] {
+			t.Errorf("leg %d = %+v, want %+v", i, legs[i], want[i])
+		}
+	}
+
+	single := &Journey{Stops: []Stop{{Name: "A", Train: "S 11"}, {Name: "B"}, {Name: "C", Train: "S 11"}}}
+	if legs := single.Legs(); len(legs) != 1 || legs[0].To != 2 {
+		t.Errorf("single train journey: got %+v", legs)
+	}
+}
+
+func TestJourney_CallsAt(t *testing.T) {
+	j := &Journey{Stops: []Stop{
+		{EVA: 8000207, Name: "Köln Hbf"},
+		{EVA: 8000044, Name: "Bonn Hbf", IsCancelled: true},
+		{EVA: 8000206, Name: "Koblenz Hbf"},
+	}}
+
+	if !j.CallsAt(8000206) {
+		t.Error("CallsAt(Koblenz) = false, want true")
+	}
+	if j.CallsAt(8000044) {
+		t.Error("CallsAt(cancelled Bonn) = true, want false")
+	}
+	if j.CallsAt(8000105) {
+		t.Error("CallsAt(Frankfurt) = true, want false")
+	}
+}
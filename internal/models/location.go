@@ -1,8 +1,12 @@
 package models
 
 import (
+	"cmp"
+	"math"
 	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 // Location represents a station/stop from search results or route entries
@@ -14,18 +18,20 @@ type Location struct {
 	Lon      float64  `json:"lon"`
 	Type     string   `json:"type"`
 	Products []string `json:"products,omitempty"`
+	// Distance is the distance in meters from the point of a nearby search
+	Distance float64 `json:"distance,omitempty"`
 }
 
 // LocationResponse represents the raw JSON response for location search
 type LocationResponse struct {
-	ExtID     string   `json:"extId"`     // API returns as string
-	EVANumber int64    `json:"evaNumber"` // Used in some responses
-	ID        string   `json:"id"`
-	Name      string   `json:"name"`
-	Lat       float64  `json:"lat"`
-	Lon       float64  `json:"lon"`
-	Type      string   `json:"type"`
-	Products  []string `json:"products"`
+	ExtID     FlexString `json:"extId"`     // API usually returns a string
+	EVANumber FlexInt    `json:"evaNumber"` // Used in some responses
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Lat       float64    `json:"lat"`
+	Lon       float64    `json:"lon"`
+	Type      string     `json:"type"`
+	Products  []string   `json:"products"`
 }
 
 // ToLocation converts the raw response to a Location
@@ -33,12 +39,12 @@ func (r *LocationResponse) ToLocation() *Location {
 	// Parse EVA from string
 	var eva int64
 	if r.ExtID != "" {
-		if parsed, err := strconv.ParseInt(r.ExtID, 10, 64); err == nil {
+		if parsed, err := strconv.ParseInt(string(r.ExtID), 10, 64); err == nil {
 			eva = parsed
 		}
 	}
 	if eva == 0 {
-		eva = r.EVANumber
+		eva = int64(r.EVANumber)
 	}
 
 	loc := &Location{
@@ -73,3 +79,151 @@ func (l *Location) parseCoordinatesFromID() {
 		}
 	}
 }
+
+// earthRadiusMeters is the mean Earth radius used for distance calculations
+const earthRadiusMeters = 6371000.0
+
+// DistanceTo returns the great-circle distance in meters from the location to
+// the given coordinate (haversine formula)
+func (l *Location) DistanceTo(lat, lon float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat - l.Lat)
+	dLon := toRad(lon - l.Lon)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(l.Lat))*math.Cos(toRad(lat))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
+
+// SortByDistance sets the distance of each location from the coordinate and
+// orders the locations nearest first
+func SortByDistance(locations []Location, lat, lon float64) {
+	for i := range locations {
+		locations[i].Distance = locations[i].DistanceTo(lat, lon)
+	}
+	slices.SortStableFunc(locations, func(a, b Location) int {
+		return cmp.Compare(a.Distance, b.Distance)
+	})
+}
+
+// IsMainStation reports whether the location looks like a city's main
+// station: its name contains "Hbf" or "Hauptbahnhof".
+func (l *Location) IsMainStation() bool {
+	name := strings.ToLower(l.Name)
+	return strings.Contains(name, "hbf") || strings.Contains(name, "hauptbahnhof")
+}
+
+// searchRank scores a location for RankLocations. A main station name and
+// long-distance service each count, so the Hbf served by ICEs beats the bus
+// stop "Hbf/Breslauer Platz", which beats other stations.
+func (l *Location) searchRank() int {
+	rank := 0
+	if l.IsMainStation() {
+		rank += 2
+	}
+	if slices.Contains(l.Products, "ICE") || slices.Contains(l.Products, "EC_IC") {
+		rank++
+	}
+	return rank
+}
+
+// RankLocations reorders search results so the primary station of a city
+// comes first. The order is stable, so the API order is kept within a rank.
+func RankLocations(locations []Location) {
+	slices.SortStableFunc(locations, func(a, b Location) int {
+		return cmp.Compare(b.searchRank(), a.searchRank())
+	})
+}
+
+// ServesAny reports whether the location serves at least one of the modes.
+// Locations without product data serve none.
+func (l *Location) ServesAny(modes []string) bool {
+	for _, mode := range modes {
+		if slices.Contains(l.Products, mode) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterLocationsByModes keeps the locations serving at least one of the
+// modes. When no location carries product data the filter can't be applied;
+// all locations are returned and ok is false.
+func FilterLocationsByModes(locations []Location, modes []string) (filtered []Location, ok bool) {
+	if !slices.ContainsFunc(locations, func(l Location) bool { return len(l.Products) > 0 }) {
+		return locations, false
+	}
+	filtered = make([]Location, 0, len(locations))
+	for _, l := range locations {
+		if l.ServesAny(modes) {
+			filtered = append(filtered, l)
+		}
+	}
+	return filtered, true
+}
+
+// StrongMatches returns the stations among search results that are equally
+// good matches for the query: the one station named exactly like the query,
+// or else all stations of the best rank used by RankLocations. Results
+// without an EVA number or ID, such as addresses, are skipped.
+func StrongMatches(locations []Location, query string) []Location {
+	var stations, exact []Location
+	for _, l := range locations {
+		if l.EVA == 0 || l.ID == "" {
+			continue
+		}
+		stations = append(stations, l)
+		if strings.EqualFold(strings.TrimSpace(l.Name), strings.TrimSpace(query)) {
+			exact = append(exact, l)
+		}
+	}
+	if len(exact) == 1 {
+		return exact
+	}
+
+	best := -1
+	var matches []Location
+	for _, l := range stations {
+		switch rank := l.searchRank(); {
+		case rank > best:
+			best, matches = rank, []Location{l}
+		case rank == best:
+			matches = append(matches, l)
+		}
+	}
+	return matches
+}
+
+// stationTiers groups modes by the kind of station they characterize, from
+// mainline to local stops. AutoModes picks the first tier a station serves.
+var stationTiers = [][]string{
+	{"ICE", "EC_IC", "IR", "REGIONAL"},
+	{"REGIONAL", "SBAHN"},
+	{"UBAHN", "TRAM"},
+}
+
+// AutoModes returns the modes worth showing by default at this station,
+// judged by the products it serves: long-distance and regional trains at a
+// mainline station, regional trains and S-Bahn at other railway stations,
+// and U-Bahn and tram at tram stops. Stops served only by other modes, such
+// as buses, get all their products. It returns nil when the products are
+// unknown, meaning all modes.
+func (l *Location) AutoModes() []string {
+	if len(l.Products) == 0 {
+		return nil
+	}
+	for i, tier := range stationTiers {
+		var modes []string
+		for _, mode := range tier {
+			if slices.Contains(l.Products, mode) {
+				modes = append(modes, mode)
+			}
+		}
+		// A mainline station is only recognized by its long-distance modes,
+		// not by the regional trains it shares with the next tier
+		if len(modes) > 0 && (i > 0 || modes[0] != "REGIONAL") {
+			return modes
+		}
+	}
+	return slices.Clone(l.Products)
+}
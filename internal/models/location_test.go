@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"math"
+	"slices"
 	"testing"
 )
 
@@ -168,3 +170,166 @@ func abs(x float64) float64 {
 	}
 	return x
 }
+
+func TestLocation_DistanceTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		loc      Location
+		lat, lon float64
+		want     float64
+	}{
+		{"same point", Location{Lat: 50.943, Lon: 6.959}, 50.943, 6.959, 0},
+		{"Köln Hbf to Köln Messe/Deutz", Location{Lat: 50.943029, Lon: 6.958730}, 50.940871, 6.974917, 1160},
+		{"Frankfurt Hbf to Köln Hbf", Location{Lat: 50.107145, Lon: 8.663003}, 50.943029, 6.958730, 152000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.loc.DistanceTo(tt.lat, tt.lon)
+			if math.Abs(got-tt.want) > tt.want*0.02+1 {
+				t.Errorf("DistanceTo() = %.0f, want about %.0f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankLocations(t *testing.T) {
+	locations := []Location{
+		{Name: "Köln Hbf/Breslauer Platz", Products: []string{"BUS"}},
+		{Name: "Köln-Deutz", Products: []string{"ICE", "REGIONAL"}},
+		{Name: "Köln Neumarkt", Products: []string{"TRAM", "BUS"}},
+		{Name: "Köln Hbf", Products: []string{"ICE", "SBAHN"}},
+		{Name: "Köln Dom/Hbf", Products: []string{"UBAHN"}},
+	}
+
+	RankLocations(locations)
+
+	want := []string{"Köln Hbf", "Köln Hbf/Breslauer Platz", "Köln Dom/Hbf", "Köln-Deutz", "Köln Neumarkt"}
+	for i, name := range want {
+		if locations[i].Name != name {
+			t.Errorf("position %d = %q, want %q", i, locations[i].Name, name)
+		}
+	}
+}
+
+func TestLocation_IsMainStation(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Frankfurt(Main)Hbf":      true,
+		"Wien Hauptbahnhof":       true,
+		"Frankfurt(Main) Süd":     false,
+		"Köln Messe/Deutz (tief)": false,
+	} {
+		loc := Location{Name: name}
+		if got := loc.IsMainStation(); got != want {
+			t.Errorf("IsMainStation(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestLocation_AutoModes(t *testing.T) {
+	tests := []struct {
+		products []string
+		want     []string
+	}{
+		{[]string{"ICE", "EC_IC", "REGIONAL", "SBAHN", "BUS", "TRAM"}, []string{"ICE", "EC_IC", "REGIONAL"}},
+		{[]string{"REGIONAL", "SBAHN", "BUS"}, []string{"REGIONAL", "SBAHN"}},
+		{[]string{"SBAHN", "UBAHN"}, []string{"SBAHN"}},
+		{[]string{"TRAM", "BUS"}, []string{"TRAM"}},
+		{[]string{"BUS", "ANRUFPFLICHTIG"}, []string{"BUS", "ANRUFPFLICHTIG"}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		loc := Location{Products: tt.products}
+		if got := loc.AutoModes(); !slices.Equal(got, tt.want) {
+			t.Errorf("AutoModes(%v) = %v, want %v", tt.products, got, tt.want)
+		}
+	}
+}
+
+func TestFilterLocationsByModes(t *testing.T) {
+	locations := []Location{
+		{Name: "Köln Hbf", Products: []string{"ICE", "REGIONAL", "SBAHN"}},
+		{Name: "Dom/Hbf", Products: []string{"UBAHN", "TRAM"}},
+		{Name: "Breslauer Platz", Products: []string{"BUS"}},
+		{Name: "Unknown"},
+	}
+
+	got, ok := FilterLocationsByModes(locations, []string{"TRAM", "SBAHN"})
+	if !ok {
+		t.Fatal("filter not applied despite product data")
+	}
+	var names []string
+	for _, l := range got {
+		names = append(names, l.Name)
+	}
+	if want := []string{"Köln Hbf", "Dom/Hbf"}; !slices.Equal(names, want) {
+		t.Errorf("filtered = %v, want %v", names, want)
+	}
+
+	// Without any product data everything is kept
+	bare := []Location{{Name: "A"}, {Name: "B"}}
+	got, ok = FilterLocationsByModes(bare, []string{"TRAM"})
+	if ok || len(got) != 2 {
+		t.Errorf("FilterLocationsByModes without products = %d locations, ok %v; want 2, false", len(got), ok)
+	}
+}
+
+func TestSortByDistance(t *testing.T) {
+	// Around Köln Hbf (50.9430, 6.9587)
+	locations := []Location{
+		{Name: "Köln Messe/Deutz", Lat: 50.9409, Lon: 6.9744},
+		{Name: "Köln Hbf", Lat: 50.9430, Lon: 6.9587},
+		{Name: "Dom/Hbf", Lat: 50.9419, Lon: 6.9571},
+	}
+
+	SortByDistance(locations, 50.9430, 6.9587)
+
+	var names []string
+	for _, l := range locations {
+		names = append(names, l.Name)
+	}
+	if want := []string{"Köln Hbf", "Dom/Hbf", "Köln Messe/Deutz"}; !slices.Equal(names, want) {
+		t.Errorf("order = %v, want %v", names, want)
+	}
+	if locations[0].Distance > 1 {
+		t.Errorf("distance of the station at the point = %.0f m, want 0", locations[0].Distance)
+	}
+	if d := locations[2].Distance; d < 1000 || d > 1250 {
+		t.Errorf("Messe/Deutz distance = %.0f m, want about 1.1 km", d)
+	}
+}
+
+func TestStrongMatches(t *testing.T) {
+	hbf := Location{Name: "Köln Hbf", EVA: 8000207, ID: "A=1@L=8000207@", Products: []string{"ICE"}}
+	messe := Location{Name: "Köln Messe/Deutz", EVA: 8003368, ID: "A=1@L=8003368@", Products: []string{"ICE"}}
+	airport := Location{Name: "Köln/Bonn Flughafen", EVA: 8003330, ID: "A=1@L=8003330@", Products: []string{"ICE"}}
+	stop := Location{Name: "Köln Breslauer Platz", EVA: 8089103, ID: "A=1@L=8089103@"}
+	address := Location{Name: "Köln, Domkloster 4"}
+
+	names := func(locs []Location) []string {
+		var out []string
+		for _, l := range locs {
+			out = append(out, l.Name)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		locations []Location
+		query     string
+		want      []string
+	}{
+		{"best rank", []Location{stop, hbf, address}, "Köln", []string{"Köln Hbf"}},
+		{"tie", []Location{stop, messe, airport}, "Köln", []string{"Köln Messe/Deutz", "Köln/Bonn Flughafen"}},
+		{"exact name wins", []Location{hbf, messe}, "köln messe/deutz", []string{"Köln Messe/Deutz"}},
+		{"addresses skipped", []Location{address}, "Domkloster", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(StrongMatches(tt.locations, tt.query)); !slices.Equal(got, tt.want) {
+				t.Errorf("StrongMatches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
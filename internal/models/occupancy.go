@@ -0,0 +1,72 @@
+package models
+
+import "fmt"
+
+// Occupancy is a forecast of how full a train is in one class
+type Occupancy int
+
+// Occupancy levels as forecast by bahn.de. The zero value means no forecast.
+const (
+	OccupancyUnknown Occupancy = iota
+	OccupancyLow
+	OccupancyMedium
+	OccupancyHigh
+	OccupancyVeryHigh
+)
+
+// occupancyNames are the JSON names of the known levels
+var occupancyNames = map[Occupancy]string{
+	OccupancyLow:      "low",
+	OccupancyMedium:   "medium",
+	OccupancyHigh:     "high",
+	OccupancyVeryHigh: "very-high",
+}
+
+// String returns the level name, or "" when there is no forecast
+func (o Occupancy) String() string {
+	return occupancyNames[o]
+}
+
+// MarshalText encodes the level by name
+func (o Occupancy) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText decodes a level name; an empty name means no forecast
+func (o *Occupancy) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*o = OccupancyUnknown
+		return nil
+	}
+	for level, name := range occupancyNames {
+		if name == string(text) {
+			*o = level
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown occupancy %q", text)
+}
+
+// OccupancyReport is a raw per-class occupancy forecast
+// ("auslastungsmeldungen" entries)
+type OccupancyReport struct {
+	Klasse string `json:"klasse"` // KLASSE_1 or KLASSE_2
+	Stufe  int    `json:"stufe"`  // 1 (low) to 4 (very high); 0 unknown
+}
+
+// parseOccupancy returns the first and second class levels of the reports.
+// Levels outside 1-4 and unknown classes are ignored.
+func parseOccupancy(reports []OccupancyReport) (first, second Occupancy) {
+	for _, r := range reports {
+		if r.Stufe < int(OccupancyLow) || r.Stufe > int(OccupancyVeryHigh) {
+			continue
+		}
+		switch r.Klasse {
+		case "KLASSE_1":
+			first = Occupancy(r.Stufe)
+		case "KLASSE_2":
+			second = Occupancy(r.Stufe)
+		}
+	}
+	return first, second
+}
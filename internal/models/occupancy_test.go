@@ -0,0 +1,108 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDepartureResponse_Occupancy(t *testing.T) {
+	raw := `{
+		"journeyId": "j1",
+		"terminus": "München Hbf",
+		"zeit": "2025-01-15T10:00:00",
+		"verkehrmittel": {"kurzText": "ICE", "mittelText": "ICE 623", "name": "ICE 623"},
+		"auslastungsmeldungen": [
+			{"klasse": "KLASSE_1", "stufe": 1},
+			{"klasse": "KLASSE_2", "stufe": 3}
+		]
+	}`
+
+	var r DepartureResponse
+	if err := json.Unmarshal([]byte(raw), &r); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	dep := r.ToDeparture(time.UTC)
+	if dep.OccupancyFirst != OccupancyLow {
+		t.Errorf("OccupancyFirst = %v, want low", dep.OccupancyFirst)
+	}
+	if dep.OccupancySecond != OccupancyHigh {
+		t.Errorf("OccupancySecond = %v, want high", dep.OccupancySecond)
+	}
+
+	out, err := json.Marshal(dep)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var back Departure
+	if err := json.Unmarshal(out, &back); err != nil {
+		t.Fatalf("Unmarshal round trip: %v", err)
+	}
+	if back.OccupancyFirst != OccupancyLow || back.OccupancySecond != OccupancyHigh {
+		t.Errorf("round trip = %v/%v, want low/high", back.OccupancyFirst, back.OccupancySecond)
+	}
+}
+
+func TestJourneyResponse_Occupancy(t *testing.T) {
+	raw := `{
+		"zugName": "ICE 623",
+		"halte": [
+			{"name": "Köln Hbf", "abfahrtsZeitpunkt": "2025-01-15T10:00:00",
+			 "auslastungsmeldungen": [{"klasse": "KLASSE_1", "stufe": 2}, {"klasse": "KLASSE_2", "stufe": 4}]},
+			{"name": "München Hbf", "ankunftsZeitpunkt": "2025-01-15T14:30:00",
+			 "auslastungsmeldungen": [{"klasse": "KLASSE_2", "stufe": 0}]}
+		]
+	}`
+
+	var r JourneyResponse
+	if err := json.Unmarshal([]byte(raw), &r); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	j := r.ToJourney("j1", time.UTC)
+	if len(j.Stops) != 2 {
+		t.Fatalf("got %d stops, want 2", len(j.Stops))
+	}
+	if j.Stops[0].OccupancyFirst != OccupancyMedium || j.Stops[0].OccupancySecond != OccupancyVeryHigh {
+		t.Errorf("first stop = %v/%v, want medium/very-high", j.Stops[0].OccupancyFirst, j.Stops[0].OccupancySecond)
+	}
+	if j.Stops[1].OccupancyFirst != OccupancyUnknown || j.Stops[1].OccupancySecond != OccupancyUnknown {
+		t.Errorf("last stop = %v/%v, want no forecast", j.Stops[1].OccupancyFirst, j.Stops[1].OccupancySecond)
+	}
+
+	// Stops without a forecast leave the fields out of JSON
+	out, err := json.Marshal(j.Stops[1])
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if _, ok := fields["occupancySecond"]; ok {
+		t.Error("occupancySecond present without a forecast")
+	}
+}
+
+func TestParseOccupancy(t *testing.T) {
+	tests := []struct {
+		name          string
+		reports       []OccupancyReport
+		first, second Occupancy
+	}{
+		{"none", nil, OccupancyUnknown, OccupancyUnknown},
+		{"both", []OccupancyReport{{"KLASSE_2", 2}, {"KLASSE_1", 1}}, OccupancyLow, OccupancyMedium},
+		{"out of range", []OccupancyReport{{"KLASSE_1", 5}, {"KLASSE_2", -1}}, OccupancyUnknown, OccupancyUnknown},
+		{"unknown class", []OccupancyReport{{"KLASSE_3", 2}}, OccupancyUnknown, OccupancyUnknown},
+	}
+	for _, tt := range tests {
+		first, second := parseOccupancy(tt.reports)
+		if first != tt.first || second != tt.second {
+			t.Errorf("%s: got %v/%v, want %v/%v", tt.name, first, second, tt.first, tt.second)
+		}
+	}
+
+	var o Occupancy
+	if err := o.UnmarshalText([]byte("packed")); err == nil {
+		t.Error("UnmarshalText accepted an unknown level")
+	}
+}
@@ -0,0 +1,181 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+// OnTimeThreshold is the delay in minutes from which a train no longer counts
+// as on time, following Deutsche Bahn's punctuality statistics
+const OnTimeThreshold = 6
+
+// BoardStats summarizes the punctuality of a set of departures. Delays and
+// the on-time share only count trains that run; trains without real-time
+// data count as on time.
+type BoardStats struct {
+	Mode         string  `json:"mode,omitempty"`
+	Trains       int     `json:"trains"`
+	Cancelled    int     `json:"cancelled"`
+	OnTime       int     `json:"onTime"`
+	OnTimePct    float64 `json:"onTimePercent"`
+	CancelledPct float64 `json:"cancelledPercent"`
+	AvgDelay     float64 `json:"avgDelayMinutes"`
+	WorstDelay   int     `json:"worstDelayMinutes"`
+	WorstLine    string  `json:"worstLine,omitempty"`
+
+	delaySum int
+}
+
+// BoardAggregate holds the punctuality of a whole board and per mode of
+// transport, busiest mode first
+type BoardAggregate struct {
+	Total  BoardStats   `json:"total"`
+	ByMode []BoardStats `json:"byMode"`
+}
+
+// add counts dep in s
+func (s *BoardStats) add(dep models.Departure) {
+	s.Trains++
+	if dep.IsCancelled {
+		s.Cancelled++
+		return
+	}
+	if dep.Delay < OnTimeThreshold {
+		s.OnTime++
+	}
+	s.delaySum += max(dep.Delay, 0)
+	if dep.Delay > s.WorstDelay {
+		s.WorstDelay = dep.Delay
+		s.WorstLine = dep.Line
+		if s.WorstLine == "" {
+			s.WorstLine = dep.TrainShort
+		}
+	}
+}
+
+// finish computes the averages and shares once all departures are added
+func (s *BoardStats) finish() {
+	if s.Trains > 0 {
+		s.CancelledPct = percent(s.Cancelled, s.Trains)
+	}
+	if running := s.Trains - s.Cancelled; running > 0 {
+		s.OnTimePct = percent(s.OnTime, running)
+		s.AvgDelay = float64(s.delaySum) / float64(running)
+	}
+}
+
+// percent returns part of total in percent, rounded to one decimal
+func percent(part, total int) float64 {
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
+
+// AggregateDepartures computes punctuality statistics over deps, in total and
+// per product (ICE, REGIONAL, SBAHN, BUS, ...)
+func AggregateDepartures(deps []models.Departure) BoardAggregate {
+	var agg BoardAggregate
+	byMode := make(map[string]*BoardStats)
+	for _, dep := range deps {
+		agg.Total.add(dep)
+
+		mode := dep.Product
+		if mode == "" {
+			mode = "OTHER"
+		}
+		s, ok := byMode[mode]
+		if !ok {
+			s = &BoardStats{Mode: mode}
+			byMode[mode] = s
+		}
+		s.add(dep)
+	}
+
+	agg.Total.finish()
+	agg.ByMode = make([]BoardStats, 0, len(byMode))
+	for _, s := range byMode {
+		s.finish()
+		agg.ByMode = append(agg.ByMode, *s)
+	}
+	sort.Slice(agg.ByMode, func(i, j int) bool {
+		a, b := agg.ByMode[i], agg.ByMode[j]
+		if a.Trains != b.Trains {
+			return a.Trains > b.Trains
+		}
+		return a.Mode < b.Mode
+	})
+	return agg
+}
+
+// RenderAggregate writes the punctuality summary of a board followed by a
+// table with one row per mode of transport
+func RenderAggregate(w io.Writer, agg BoardAggregate, opts TableOptions) {
+	c := opts.Colors
+	if c == nil {
+		c = NewColors(ColorNever)
+	}
+
+	total := agg.Total
+	if total.Trains == 0 {
+		_, _ = fmt.Fprintln(w, "No departures found.")
+		return
+	}
+
+	opts.header(w, c.Header(fmt.Sprintf("Punctuality of %d trains", total.Trains)))
+	opts.blankLine(w)
+	_, _ = fmt.Fprintf(w, "%-13s %s %s\n", "On time:", formatPercent(total.OnTimePct),
+		c.Muted(fmt.Sprintf("(less than %d min late)", OnTimeThreshold)))
+	_, _ = fmt.Fprintf(w, "%-13s %s %s\n", "Cancelled:", formatPercent(total.CancelledPct),
+		c.Muted(fmt.Sprintf("(%d)", total.Cancelled)))
+	_, _ = fmt.Fprintf(w, "%-13s %s\n", "Avg delay:", formatAvgDelay(total.AvgDelay))
+	worst := "none"
+	if total.WorstDelay > 0 {
+		style := c.Delay
+		if total.WorstDelay >= DelayHighThreshold {
+			style = c.DelayHigh
+		}
+		worst = style("%+d min", total.WorstDelay)
+		if total.WorstLine != "" {
+			worst += " " + c.Line("(%s)", total.WorstLine)
+		}
+	}
+	_, _ = fmt.Fprintf(w, "%-13s %s\n", "Worst delay:", worst)
+
+	if len(agg.ByMode) < 2 {
+		return
+	}
+
+	modeWidth := len("Mode")
+	for _, s := range agg.ByMode {
+		modeWidth = max(modeWidth, len(s.Mode))
+	}
+	row := "%-*s  %6s  %7s  %9s  %9s  %5s\n"
+	opts.blankLine(w)
+	opts.header(w, c.Header(fmt.Sprintf(strings.TrimSuffix(row, "\n"), modeWidth, "Mode", "Trains", "On time", "Cancelled", "Avg delay", "Worst")))
+	for _, s := range agg.ByMode {
+		worst := ""
+		if s.WorstDelay > 0 {
+			worst = fmt.Sprintf("%+d", s.WorstDelay)
+		}
+		_, _ = fmt.Fprintf(w, row, modeWidth, s.Mode,
+			fmt.Sprint(s.Trains),
+			formatPercent(s.OnTimePct),
+			fmt.Sprint(s.Cancelled),
+			formatAvgDelay(s.AvgDelay),
+			worst,
+		)
+	}
+}
+
+// formatPercent formats a share such as 87.5 as "87.5%", dropping ".0"
+func formatPercent(p float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", p), ".0") + "%"
+}
+
+// formatAvgDelay formats an average delay in minutes, e.g. "+2.4 min"
+func formatAvgDelay(d float64) string {
+	return fmt.Sprintf("%+.1f min", d)
+}
@@ -0,0 +1,90 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func aggregateFixture() []models.Departure {
+	return []models.Departure{
+		{Line: "ICE 101", Product: "ICE", Delay: 0},
+		{Line: "ICE 103", Product: "ICE", Delay: 12},
+		{Line: "ICE 105", Product: "ICE", IsCancelled: true},
+		{Line: "ICE 107", Product: "ICE", Delay: 5},
+		{Line: "S 11", Product: "SBAHN", Delay: 2},
+		{Line: "S 12", Product: "SBAHN", Delay: -1},
+		{Line: "X", Delay: 30},
+	}
+}
+
+func TestAggregateDepartures(t *testing.T) {
+	agg := AggregateDepartures(aggregateFixture())
+
+	total := agg.Total
+	testutil.AssertEqual(t, total.Trains, 7)
+	testutil.AssertEqual(t, total.Cancelled, 1)
+	testutil.AssertEqual(t, total.OnTime, 4)
+	testutil.AssertEqual(t, total.OnTimePct, 66.7)
+	testutil.AssertEqual(t, total.CancelledPct, 14.3)
+	testutil.AssertEqual(t, total.AvgDelay, 49.0/6)
+	testutil.AssertEqual(t, total.WorstDelay, 30)
+	testutil.AssertEqual(t, total.WorstLine, "X")
+
+	testutil.AssertLen(t, agg.ByMode, 3)
+	ice := agg.ByMode[0]
+	testutil.AssertEqual(t, ice.Mode, "ICE")
+	testutil.AssertEqual(t, ice.Trains, 4)
+	testutil.AssertEqual(t, ice.OnTime, 2)
+	testutil.AssertEqual(t, ice.CancelledPct, 25.0)
+	testutil.AssertEqual(t, ice.WorstLine, "ICE 103")
+	testutil.AssertEqual(t, agg.ByMode[1].Mode, "SBAHN")
+	testutil.AssertEqual(t, agg.ByMode[1].OnTimePct, 100.0)
+	testutil.AssertEqual(t, agg.ByMode[1].AvgDelay, 1.0)
+	testutil.AssertEqual(t, agg.ByMode[2].Mode, "OTHER")
+}
+
+func TestAggregateDepartures_Empty(t *testing.T) {
+	agg := AggregateDepartures(nil)
+	testutil.AssertEqual(t, agg.Total.Trains, 0)
+	testutil.AssertLen(t, agg.ByMode, 0)
+
+	var buf bytes.Buffer
+	RenderAggregate(&buf, agg, TableOptions{})
+	testutil.AssertContains(t, buf.String(), "No departures found")
+}
+
+func TestAggregateDepartures_AllCancelled(t *testing.T) {
+	agg := AggregateDepartures([]models.Departure{{Product: "BUS", IsCancelled: true}})
+	testutil.AssertEqual(t, agg.Total.CancelledPct, 100.0)
+	testutil.AssertEqual(t, agg.Total.OnTimePct, 0.0)
+	testutil.AssertEqual(t, agg.Total.AvgDelay, 0.0)
+}
+
+func TestRenderAggregate(t *testing.T) {
+	var buf bytes.Buffer
+	RenderAggregate(&buf, AggregateDepartures(aggregateFixture()), TableOptions{Colors: NewColors(ColorNever)})
+
+	out := buf.String()
+	testutil.AssertContains(t, out, "Punctuality of 7 trains")
+	testutil.AssertContains(t, out, "On time:      66.7%")
+	testutil.AssertContains(t, out, "Cancelled:    14.3% (1)")
+	testutil.AssertContains(t, out, "Avg delay:    +8.2 min")
+	testutil.AssertContains(t, out, "Worst delay:  +30 min (X)")
+	testutil.AssertContains(t, out, "Mode   Trains  On time  Cancelled  Avg delay  Worst")
+	testutil.AssertContains(t, out, "ICE         4    66.7%          1   +5.7 min    +12")
+	testutil.AssertContains(t, out, "SBAHN       2     100%          0   +1.0 min     +2")
+}
+
+func TestRenderAggregate_SingleModeBare(t *testing.T) {
+	var buf bytes.Buffer
+	deps := []models.Departure{{Line: "ICE 1", Product: "ICE", Delay: 3}}
+	RenderAggregate(&buf, AggregateDepartures(deps), TableOptions{NoDecoration: true})
+
+	out := buf.String()
+	testutil.AssertNotContains(t, out, "Punctuality of")
+	testutil.AssertNotContains(t, out, "Mode")
+	testutil.AssertContains(t, out, "On time:      100%")
+}
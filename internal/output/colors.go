@@ -1,7 +1,10 @@
 package output
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/mattn/go-isatty"
@@ -26,16 +29,27 @@ type Colors struct {
 	DelayHigh func(format string, a ...interface{}) string
 	OnTime    func(format string, a ...interface{}) string
 	Line      func(format string, a ...interface{}) string
+	Category  func(format string, a ...interface{}) string
 	Platform  func(format string, a ...interface{}) string
-	Dest      func(format string, a ...interface{}) string
-	Canceled  func(format string, a ...interface{}) string
-	Via       func(format string, a ...interface{}) string
-	Header    func(format string, a ...interface{}) string
-	Muted     func(format string, a ...interface{}) string
+	// PlatformChange draws platforms that differ from the schedule
+	PlatformChange func(format string, a ...interface{}) string
+	Dest           func(format string, a ...interface{}) string
+	Canceled       func(format string, a ...interface{}) string
+	Via            func(format string, a ...interface{}) string
+	Header         func(format string, a ...interface{}) string
+	Muted          func(format string, a ...interface{}) string
+	Badge          func(format string, a ...interface{}) string
 }
 
-// NewColors creates a new Colors instance based on the color mode
+// NewColors creates a new Colors instance based on the color mode, using the
+// default theme
 func NewColors(mode ColorMode) *Colors {
+	return NewColorsWithTheme(mode, DefaultTheme)
+}
+
+// NewColorsWithTheme creates a new Colors instance drawing in the palette of
+// theme when the color mode enables colors
+func NewColorsWithTheme(mode ColorMode, theme Theme) *Colors {
 	// Determine if we should use colors
 	useColors := false
 	switch mode {
@@ -57,43 +71,52 @@ func NewColors(mode ColorMode) *Colors {
 			return color.New().Sprintf(format, a...)
 		}
 		return &Colors{
-			Time:      noColor,
-			Delay:     noColor,
-			DelayHigh: noColor,
-			OnTime:    noColor,
-			Line:      noColor,
-			Platform:  noColor,
-			Dest:      noColor,
-			Canceled:  noColor,
-			Via:       noColor,
-			Header:    noColor,
-			Muted:     noColor,
+			Time:           noColor,
+			Delay:          noColor,
+			DelayHigh:      noColor,
+			OnTime:         noColor,
+			Line:           noColor,
+			Category:       noColor,
+			Platform:       noColor,
+			PlatformChange: noColor,
+			Dest:           noColor,
+			Canceled:       noColor,
+			Via:            noColor,
+			Header:         noColor,
+			Muted:          noColor,
+			Badge:          noColor,
 		}
 	}
 
 	// Create colored functions
 	return &Colors{
-		Time:      color.New(color.FgWhite, color.Bold).SprintfFunc(),
-		Delay:     color.New(color.FgYellow).SprintfFunc(),
-		DelayHigh: color.New(color.FgRed, color.Bold).SprintfFunc(),
-		OnTime:    color.New(color.FgGreen).SprintfFunc(),
-		Line:      color.New(color.FgCyan, color.Bold).SprintfFunc(),
-		Platform:  color.New(color.FgMagenta).SprintfFunc(),
-		Dest:      color.New(color.FgWhite).SprintfFunc(),
-		Canceled:  color.New(color.FgRed, color.Bold).SprintfFunc(),
-		Via:       color.New(color.FgHiBlack).SprintfFunc(),
-		Header:    color.New(color.FgWhite, color.Bold).SprintfFunc(),
-		Muted:     color.New(color.FgHiBlack).SprintfFunc(),
+		Time:           theme.Time.sprintf(),
+		Delay:          theme.Delay.sprintf(),
+		DelayHigh:      theme.DelayHigh.sprintf(),
+		OnTime:         theme.OnTime.sprintf(),
+		Line:           theme.Line.sprintf(),
+		Category:       theme.Category.sprintf(),
+		Platform:       theme.Platform.sprintf(),
+		PlatformChange: theme.PlatformChange.sprintf(),
+		Dest:           theme.Dest.sprintf(),
+		Canceled:       theme.Canceled.sprintf(),
+		Via:            theme.Via.sprintf(),
+		Header:         theme.Header.sprintf(),
+		Muted:          theme.Muted.sprintf(),
+		Badge:          theme.Badge.sprintf(),
 	}
 }
 
+// DelayHighThreshold is the delay in minutes from which a delay is shown as major
+const DelayHighThreshold = 10
+
 // FormatDelay formats a delay value with appropriate color (fixed 4-char width)
 func (c *Colors) FormatDelay(delay int) string {
 	if delay == 0 {
 		return "    " // 4 spaces for alignment
 	}
 	if delay > 0 {
-		if delay >= 10 {
+		if delay >= DelayHighThreshold {
 			return c.DelayHigh("%+4d", delay)
 		}
 		return c.Delay("%+4d", delay)
@@ -101,6 +124,82 @@ func (c *Colors) FormatDelay(delay int) string {
 	return c.OnTime("%4d", delay)
 }
 
+// Compact delay glyphs, two cells wide, with ASCII fallbacks
+var (
+	compactDelayGlyphs = [4]string{"· ", "↑ ", "↑↑", "✕ "}
+	compactDelayASCII  = [4]string{". ", "+ ", "++", "x "}
+)
+
+// FormatDelayCompact formats a delay as a single glyph (fixed 2-char width):
+// on time or early, minor delay, major delay or cancelled. ascii selects
+// plain ASCII glyphs for terminals without Unicode support.
+func (c *Colors) FormatDelayCompact(delay int, cancelled, ascii bool) string {
+	glyphs := compactDelayGlyphs
+	if ascii {
+		glyphs = compactDelayASCII
+	}
+	switch {
+	case cancelled:
+		return c.Canceled(glyphs[3])
+	case delay >= DelayHighThreshold:
+		return c.DelayHigh(glyphs[2])
+	case delay > 0:
+		return c.Delay(glyphs[1])
+	}
+	return c.OnTime(glyphs[0])
+}
+
+// RenderCompactDelayLegend writes a one-line explanation of the compact delay glyphs
+func RenderCompactDelayLegend(w io.Writer, c *Colors, ascii bool) {
+	glyphs := compactDelayGlyphs
+	if ascii {
+		glyphs = compactDelayASCII
+	}
+	_, _ = fmt.Fprintf(w, "%s %s on time  %s minor delay (1-%d min)  %s major delay (%d+ min)  %s cancelled\n",
+		c.Muted("Legend:"),
+		c.OnTime(strings.TrimSpace(glyphs[0])),
+		c.Delay(strings.TrimSpace(glyphs[1])),
+		DelayHighThreshold-1,
+		c.DelayHigh(glyphs[2]),
+		DelayHighThreshold,
+		c.Canceled(strings.TrimSpace(glyphs[3])),
+	)
+}
+
+// RenderDelayLegend writes a one-line explanation of the delay colors and markers
+func RenderDelayLegend(w io.Writer, c *Colors) {
+	_, _ = fmt.Fprintf(w, "%s %s on time  %s early  %s minor delay (1-%d min)  %s major delay (%d+ min)  %s cancelled\n",
+		c.Muted("Legend:"),
+		c.Muted("blank"),
+		c.OnTime("-2"),
+		c.Delay("+5"),
+		DelayHighThreshold-1,
+		c.DelayHigh(fmt.Sprintf("%+d", DelayHighThreshold+2)),
+		DelayHighThreshold,
+		c.Canceled("[CANCELED]"),
+	)
+}
+
+// ColorModeFromEnv resolves ColorAuto with the NO_COLOR, FORCE_COLOR and
+// CLICOLOR_FORCE conventions: a non-empty NO_COLOR turns colors off, and
+// FORCE_COLOR or CLICOLOR_FORCE set to anything but "0" or "false" turn them
+// on, taking precedence over NO_COLOR. Other modes were chosen explicitly
+// and are returned unchanged.
+func ColorModeFromEnv(mode ColorMode) ColorMode {
+	if mode != ColorAuto {
+		return mode
+	}
+	for _, name := range []string{"FORCE_COLOR", "CLICOLOR_FORCE"} {
+		if v, ok := os.LookupEnv(name); ok && v != "0" && !strings.EqualFold(v, "false") {
+			return ColorAlways
+		}
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return ColorNever
+	}
+	return ColorAuto
+}
+
 // ParseColorMode parses a color mode string
 func ParseColorMode(s string) ColorMode {
 	switch s {
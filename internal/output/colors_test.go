@@ -1,6 +1,8 @@
 package output
 
 import (
+	"bytes"
+	"os"
 	"strings"
 	"testing"
 
@@ -28,6 +30,40 @@ func TestParseColorMode(t *testing.T) {
 	}
 }
 
+func TestColorModeFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		mode ColorMode
+		want ColorMode
+	}{
+		{"no env", nil, ColorAuto, ColorAuto},
+		{"NO_COLOR", map[string]string{"NO_COLOR": "1"}, ColorAuto, ColorNever},
+		{"empty NO_COLOR", map[string]string{"NO_COLOR": ""}, ColorAuto, ColorAuto},
+		{"FORCE_COLOR", map[string]string{"FORCE_COLOR": "1"}, ColorAuto, ColorAlways},
+		{"empty FORCE_COLOR", map[string]string{"FORCE_COLOR": ""}, ColorAuto, ColorAlways},
+		{"FORCE_COLOR=0", map[string]string{"FORCE_COLOR": "0"}, ColorAuto, ColorAuto},
+		{"CLICOLOR_FORCE", map[string]string{"CLICOLOR_FORCE": "1"}, ColorAuto, ColorAlways},
+		{"force beats NO_COLOR", map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, ColorAuto, ColorAlways},
+		{"--color always wins", map[string]string{"NO_COLOR": "1"}, ColorAlways, ColorAlways},
+		{"--color never wins", map[string]string{"FORCE_COLOR": "1"}, ColorNever, ColorNever},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"NO_COLOR", "FORCE_COLOR", "CLICOLOR_FORCE"} {
+				t.Setenv(name, "") // restored after the test
+				if v, ok := tt.env[name]; ok {
+					t.Setenv(name, v)
+				} else {
+					_ = os.Unsetenv(name)
+				}
+			}
+			testutil.AssertEqual(t, ColorModeFromEnv(tt.mode), tt.want)
+		})
+	}
+}
+
 func TestNewColors_NeverMode(t *testing.T) {
 	// Save and restore color state
 	oldNoColor := color.NoColor
@@ -192,6 +228,18 @@ func stripANSI(s string) string {
 	return result.String()
 }
 
+func TestRenderDelayLegend(t *testing.T) {
+	var buf bytes.Buffer
+	RenderDelayLegend(&buf, NewColors(ColorNever))
+
+	out := buf.String()
+	testutil.AssertContains(t, out, "Legend:")
+	testutil.AssertContains(t, out, "-2 early")
+	testutil.AssertContains(t, out, "+5 minor delay (1-9 min)")
+	testutil.AssertContains(t, out, "+12 major delay (10+ min)")
+	testutil.AssertContains(t, out, "[CANCELED] cancelled")
+}
+
 func formatDelayValue(delay int) string {
 	if delay > 0 {
 		return strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(
@@ -220,3 +268,24 @@ func formatInt(val int, positive bool) string {
 	}
 	return s + string(digits)
 }
+
+func TestFormatDelayCompact(t *testing.T) {
+	c := NewColors(ColorNever)
+
+	tests := []struct {
+		delay     int
+		cancelled bool
+		want      string
+		ascii     string
+	}{
+		{0, false, "· ", ". "},
+		{-2, false, "· ", ". "},
+		{3, false, "↑ ", "+ "},
+		{DelayHighThreshold, false, "↑↑", "++"},
+		{5, true, "✕ ", "x "},
+	}
+	for _, tt := range tests {
+		testutil.AssertEqual(t, stripANSI(c.FormatDelayCompact(tt.delay, tt.cancelled, false)), tt.want)
+		testutil.AssertEqual(t, stripANSI(c.FormatDelayCompact(tt.delay, tt.cancelled, true)), tt.ascii)
+	}
+}
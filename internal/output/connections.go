@@ -0,0 +1,123 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+// RenderConnections renders trip search results as one block per connection:
+// a header with departure, arrival, duration and number of changes, then one
+// row per leg with times, delays, line and the stations it runs between.
+func RenderConnections(w io.Writer, connections []models.Connection, opts TableOptions) {
+	if len(connections) == 0 {
+		_, _ = fmt.Fprintln(w, "No connections found.")
+		return
+	}
+
+	c := opts.Colors
+	if c == nil {
+		c = NewColors(ColorNever)
+	}
+
+	for i, conn := range connections {
+		if i > 0 {
+			opts.blankLine(w)
+		}
+		renderConnection(w, c, conn, opts)
+	}
+}
+
+func renderConnection(w io.Writer, c *Colors, conn models.Connection, opts TableOptions) {
+	first, last := conn.Departure(), conn.Arrival()
+	if first == nil {
+		return
+	}
+
+	// Header: 12:07 → 13:42  1h21m, 1 change
+	header := "?"
+	if t := opts.pickTime(first.SchedDep, first.Dep); t != nil {
+		header = c.Time(strings.TrimSpace(opts.formatTime(*t)))
+	}
+	header += " → "
+	if t := opts.pickTime(last.SchedArr, last.Arr); t != nil {
+		header += c.Time(strings.TrimSpace(opts.formatTime(*t)))
+	} else {
+		header += "?"
+	}
+	summary := formatDurationMinutes(conn.DurationMinutes) + ", " + formatChanges(conn.Transfers)
+	_, _ = fmt.Fprintf(w, "%s  %s\n", header, c.Muted(summary))
+
+	lineWidth := 0
+	for _, leg := range conn.Legs {
+		lineWidth = max(lineWidth, lipgloss.Width(legLabel(leg)))
+	}
+
+	for _, leg := range conn.Legs {
+		label := padDisplay(legLabel(leg), lineWidth)
+		if leg.Walk {
+			label = c.Muted(label)
+		} else {
+			label = c.Line(label)
+		}
+
+		route := leg.From + legPlatform(leg.DepPlatform) + " → " + leg.To + legPlatform(leg.ArrPlatform)
+		if leg.IsCancelled {
+			route += " " + c.Canceled("cancelled")
+		}
+
+		row := fmt.Sprintf("  %s %s  %s  %s  %s %s",
+			legTime(c, opts, leg.SchedDep, leg.Dep), c.FormatDelay(opts.shownDelay(leg.DepDelay)),
+			label, route,
+			legTime(c, opts, leg.SchedArr, leg.Arr), c.FormatDelay(opts.shownDelay(leg.ArrDelay)))
+		_, _ = fmt.Fprintln(w, strings.TrimRight(row, " "))
+	}
+}
+
+// legLabel returns the line name of a leg, or "Walk" for walks
+func legLabel(leg models.ConnectionLeg) string {
+	if leg.Walk {
+		return "Walk"
+	}
+	return leg.Line
+}
+
+// legPlatform formats a platform suffix such as " [4]"
+func legPlatform(platform string) string {
+	if platform == "" {
+		return ""
+	}
+	return " [" + platform + "]"
+}
+
+// legTime formats a leg time column, blank when the time is unknown
+func legTime(c *Colors, opts TableOptions, sched, effective *time.Time) string {
+	t := opts.pickTime(sched, effective)
+	if t == nil {
+		return strings.Repeat(" ", opts.timeWidth())
+	}
+	return c.Time(opts.formatTime(*t))
+}
+
+// formatDurationMinutes formats a duration in minutes as "45m" or "1h21m"
+func formatDurationMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+// formatChanges describes the number of changes of a connection
+func formatChanges(n int) string {
+	switch n {
+	case 0:
+		return "direct"
+	case 1:
+		return "1 change"
+	}
+	return fmt.Sprintf("%d changes", n)
+}
@@ -0,0 +1,46 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func sampleConnections(t *testing.T) []models.Connection {
+	t.Helper()
+	var resp models.ConnectionsResponse
+	testutil.AssertNil(t, json.Unmarshal([]byte(testutil.SampleConnectionResponse), &resp))
+	return []models.Connection{*resp.Verbindungen[0].ToConnection(time.UTC)}
+}
+
+func TestRenderConnections(t *testing.T) {
+	out := RenderConnectionsString(sampleConnections(t), TableOptions{Colors: NewColors(ColorNever)})
+	lines := strings.Split(strings.TrimRight(stripANSI(out), "\n"), "\n")
+	testutil.AssertLen(t, lines, 4)
+
+	testutil.AssertEqual(t, lines[0], "12:07 → 13:42  1h21m, 1 change")
+	testutil.AssertEqual(t, lines[1], "  12:07   +3  ICE 123  Köln Hbf [4] → Frankfurt(Main)Hbf [9]  13:14   +4")
+	testutil.AssertContains(t, lines[2], "Walk     Frankfurt(Main)Hbf → Frankfurt(Main)Hbf (tief)")
+	testutil.AssertContains(t, lines[3], "S 8      Frankfurt(Main)Hbf (tief) [101] → Frankfurt Flughafen Regionalbf. [1]  13:42")
+}
+
+func TestRenderConnections_PreferScheduled(t *testing.T) {
+	out := stripANSI(RenderConnectionsString(sampleConnections(t), TableOptions{PreferScheduled: true}))
+	testutil.AssertContains(t, out, "12:04 → 13:42")
+	testutil.AssertContains(t, out, "  12:04       ICE 123")
+}
+
+func TestRenderConnections_Empty(t *testing.T) {
+	testutil.AssertEqual(t, RenderConnectionsString(nil, TableOptions{}), "No connections found.\n")
+}
+
+func TestFormatChanges(t *testing.T) {
+	testutil.AssertEqual(t, formatChanges(0), "direct")
+	testutil.AssertEqual(t, formatChanges(2), "2 changes")
+	testutil.AssertEqual(t, formatDurationMinutes(45), "45m")
+	testutil.AssertEqual(t, formatDurationMinutes(125), "2h05m")
+}
@@ -3,6 +3,7 @@ package output
 import (
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/mobil-koeln/moko-cli/internal/models"
@@ -21,7 +22,8 @@ func RenderFormation(w io.Writer, formation *models.Formation, opts TableOptions
 	}
 
 	// Platform header
-	_, _ = fmt.Fprintf(w, "%s %s\n\n", c.Header("Platform:"), c.Platform(formation.Platform))
+	opts.header(w, c.Header("Platform:")+" "+c.Platform(formation.Platform))
+	opts.blankLine(w)
 
 	// Render sectors
 	if len(formation.Sectors) > 0 {
@@ -33,11 +35,21 @@ func RenderFormation(w io.Writer, formation *models.Formation, opts TableOptions
 		renderCarriages(w, formation, c)
 	}
 
-	_, _ = fmt.Fprintln(w)
+	// Render which end leads toward the destination
+	if formation.Derived.Front != nil {
+		renderFront(w, formation.Derived.Front, c)
+	}
+
+	// Warn when the train does not fill the platform
+	if cov := formation.Derived.Coverage; cov != nil && cov.Partial {
+		renderCoverage(w, formation, cov, c)
+	}
+
+	opts.blankLine(w)
 
 	// Render groups with details
 	for _, group := range formation.Groups {
-		renderGroup(w, &group, c)
+		renderGroup(w, &group, c, opts)
 	}
 }
 
@@ -161,7 +173,57 @@ func renderCarriages(w io.Writer, formation *models.Formation, c *Colors) {
 	_, _ = fmt.Fprintln(w, sb.String())
 }
 
-func renderGroup(w io.Writer, group *models.Group, c *Colors) {
+func renderFront(w io.Writer, front *models.TrainFront, c *Colors) {
+	var parts []string
+	if front.Carriage != "" {
+		parts = append(parts, "car "+front.Carriage)
+	}
+	if front.Sector != "" {
+		parts = append(parts, "sector "+front.Sector)
+	}
+
+	text := "Front of train"
+	if len(parts) > 0 {
+		text += " (" + strings.Join(parts, ", ") + ")"
+	}
+	if front.Destination != "" {
+		text += " points toward " + c.Dest(front.Destination)
+	}
+
+	if front.Side == "right" {
+		_, _ = fmt.Fprintf(w, "%s %s\n", text, c.Muted("->"))
+	} else {
+		_, _ = fmt.Fprintf(w, "%s %s\n", c.Muted("<-"), text)
+	}
+}
+
+func renderCoverage(w io.Writer, formation *models.Formation, cov *models.PlatformCoverage, c *Colors) {
+	// Contiguous sectors read as a range ("A–C"), others as a list
+	names := make([]string, len(formation.Sectors))
+	for i, s := range formation.Sectors {
+		names[i] = s.Name
+	}
+	sectors := strings.Join(cov.Sectors, ", ")
+	if len(cov.Sectors) > 1 {
+		first := slices.Index(names, cov.Sectors[0])
+		last := slices.Index(names, cov.Sectors[len(cov.Sectors)-1])
+		if first >= 0 && last-first == len(cov.Sectors)-1 {
+			sectors = cov.Sectors[0] + "–" + cov.Sectors[len(cov.Sectors)-1]
+		}
+	}
+
+	label := "sectors"
+	if len(cov.Sectors) == 1 {
+		label = "sector"
+	}
+	text := fmt.Sprintf("Train occupies %s %s only", label, sectors)
+	if cov.TrainMeters > 0 {
+		text += fmt.Sprintf(" (%.0f m)", cov.TrainMeters)
+	}
+	_, _ = fmt.Fprintln(w, c.Badge("%s", text))
+}
+
+func renderGroup(w io.Writer, group *models.Group, c *Colors, opts TableOptions) {
 	// Group header
 	desc := group.Description
 	if desc == "" {
@@ -178,13 +240,14 @@ func renderGroup(w io.Writer, group *models.Group, c *Colors) {
 		sectors = " (" + strings.Join(group.Sectors, "") + ")"
 	}
 
-	_, _ = fmt.Fprintf(w, "%s%s%s\n", c.Header(desc), c.Muted(designation), c.Muted(sectors))
-	_, _ = fmt.Fprintf(w, "%s %s  %s %s\n\n",
+	opts.header(w, c.Header(desc)+c.Muted(designation)+c.Muted(sectors))
+	_, _ = fmt.Fprintf(w, "%s %s  %s %s\n",
 		c.Line(group.TrainType),
 		c.Line(group.TrainNo),
 		c.Muted("→"),
 		group.Destination,
 	)
+	opts.blankLine(w)
 
 	// Carriage details
 	for _, carriage := range group.Carriages {
@@ -249,5 +312,5 @@ func renderGroup(w io.Writer, group *models.Group, c *Colors) {
 		)
 	}
 
-	_, _ = fmt.Fprintln(w)
+	opts.blankLine(w)
 }
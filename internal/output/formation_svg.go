@@ -0,0 +1,156 @@
+package output
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+// SVG layout in user units; positions from the API are percentages of the
+// platform length and are scaled onto svgTrackWidth
+const (
+	svgWidth      = 1000.0
+	svgMargin     = 30.0
+	svgTrackWidth = svgWidth - 2*svgMargin
+	svgHeight     = 200.0
+	svgSectorY    = 50.0
+	svgCarriageY  = 80.0
+	svgCarriageH  = 40.0
+	svgPlatformY  = 130.0
+	svgLegendY    = 175.0
+)
+
+// svgClassFills maps carriage kinds to fill colors, matching the terminal
+// colors of RenderFormation (first class red, second cyan, mixed yellow)
+var svgClassFills = []struct {
+	label string
+	fill  string
+}{
+	{"1st class", "#e4572e"},
+	{"2nd class", "#3fa7d6"},
+	{"1st/2nd class", "#f2c14e"},
+	{"Locomotive", "#555555"},
+	{"Closed", "#dddddd"},
+}
+
+// RenderFormationSVG writes the formation as a standalone SVG image: the
+// platform with its sector labels, one rectangle per carriage colored by
+// class and an arrow at the end of the train that leads. The output has no
+// external references, so it can be opened in a browser or attached to a
+// message as is.
+func RenderFormationSVG(w io.Writer, formation *models.Formation) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g" font-family="sans-serif" font-size="14">`+"\n",
+		svgWidth, svgHeight, svgWidth, svgHeight)
+	sb.WriteString(`<rect width="100%" height="100%" fill="#ffffff"/>` + "\n")
+
+	if formation == nil {
+		fmt.Fprintf(&sb, `<text x="%g" y="%g">No formation data available.</text>`+"\n", svgMargin, svgSectorY)
+		sb.WriteString("</svg>\n")
+		_, _ = io.WriteString(w, sb.String())
+		return
+	}
+
+	// Title
+	title := "Platform " + formation.Platform
+	if train := strings.TrimSpace(formation.TrainType + " " + strings.Join(formation.TrainNumbers, "/")); train != "" {
+		title = train + " · " + title
+	}
+	fmt.Fprintf(&sb, `<text x="%g" y="24" font-size="18" font-weight="bold">%s</text>`+"\n", svgMargin, svgText(title))
+
+	// Platform edge and sectors
+	fmt.Fprintf(&sb, `<rect x="%g" y="%g" width="%g" height="6" fill="#999999"/>`+"\n", svgMargin, svgPlatformY, svgTrackWidth)
+	for _, sector := range formation.Sectors {
+		x, width := svgSpan(sector.StartPercent, sector.EndPercent)
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%g" x2="%.1f" y2="%g" stroke="#bbbbbb" stroke-dasharray="4 3"/>`+"\n",
+			x, svgSectorY-16, x, svgPlatformY+6)
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%g" text-anchor="middle" font-weight="bold">%s</text>`+"\n",
+			x+width/2, svgSectorY, svgText(sector.Name))
+	}
+	if n := len(formation.Sectors); n > 0 {
+		end, _ := svgSpan(formation.Sectors[n-1].EndPercent, 0)
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%g" x2="%.1f" y2="%g" stroke="#bbbbbb" stroke-dasharray="4 3"/>`+"\n",
+			end, svgSectorY-16, end, svgPlatformY+6)
+	}
+
+	// Carriages
+	first, last := 100.0, 0.0
+	for _, carriage := range formation.Carriages {
+		first = min(first, carriage.StartPercent)
+		last = max(last, carriage.EndPercent)
+
+		x, width := svgSpan(carriage.StartPercent, carriage.EndPercent)
+		fill := svgCarriageFill(&carriage)
+		fmt.Fprintf(&sb, `<rect x="%.1f" y="%g" width="%.1f" height="%g" rx="6" fill="%s" stroke="#333333"/>`+"\n",
+			x+1, svgCarriageY, max(width-2, 1), svgCarriageH, fill)
+
+		label := carriage.Number
+		switch {
+		case carriage.IsLocomotive || carriage.IsPowercar:
+			label = ""
+		case carriage.IsClosed:
+			label = "X"
+		}
+		if label != "" {
+			fmt.Fprintf(&sb, `<text x="%.1f" y="%g" text-anchor="middle">%s</text>`+"\n",
+				x+width/2, svgCarriageY+svgCarriageH/2+5, svgText(label))
+		}
+	}
+
+	// Direction arrow just outside the leading end
+	if len(formation.Carriages) > 0 {
+		y := svgCarriageY + svgCarriageH/2
+		if formation.Direction == 100 {
+			x, _ := svgSpan(last, 0)
+			fmt.Fprintf(&sb, `<polygon points="%.1f,%g %.1f,%g %.1f,%g" fill="#333333"/>`+"\n",
+				x+4, y-10, x+4, y+10, x+20, y)
+		} else {
+			x, _ := svgSpan(first, 0)
+			fmt.Fprintf(&sb, `<polygon points="%.1f,%g %.1f,%g %.1f,%g" fill="#333333"/>`+"\n",
+				x-4, y-10, x-4, y+10, x-20, y)
+		}
+	}
+
+	// Legend
+	x := svgMargin
+	for _, entry := range svgClassFills {
+		fmt.Fprintf(&sb, `<rect x="%g" y="%g" width="14" height="14" fill="%s" stroke="#333333"/>`+"\n", x, svgLegendY-12, entry.fill)
+		fmt.Fprintf(&sb, `<text x="%g" y="%g" font-size="12">%s</text>`+"\n", x+20, svgLegendY, svgText(entry.label))
+		x += 140
+	}
+
+	sb.WriteString("</svg>\n")
+	_, _ = io.WriteString(w, sb.String())
+}
+
+// svgSpan converts a start and end percentage of the platform into an x
+// position and width in SVG units
+func svgSpan(start, end float64) (x, width float64) {
+	scale := svgTrackWidth / 100
+	return svgMargin + start*scale, (end - start) * scale
+}
+
+// svgCarriageFill picks the fill color for a carriage
+func svgCarriageFill(c *models.Carriage) string {
+	switch {
+	case c.IsLocomotive || c.IsPowercar:
+		return svgClassFills[3].fill
+	case c.IsClosed:
+		return svgClassFills[4].fill
+	case c.ClassType == 1:
+		return svgClassFills[0].fill
+	case c.ClassType == 2:
+		return svgClassFills[1].fill
+	case c.ClassType == 12:
+		return svgClassFills[2].fill
+	}
+	return "#ffffff"
+}
+
+// svgText escapes s for use as SVG text content
+func svgText(s string) string {
+	return html.EscapeString(s)
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestRenderFormationSVG(t *testing.T) {
+	formation := &models.Formation{
+		Platform:     "7",
+		Direction:    100,
+		TrainType:    "ICE",
+		TrainNumbers: []string{"623"},
+		Sectors: []models.Sector{
+			{Name: "A", StartPercent: 0, EndPercent: 50},
+			{Name: "B", StartPercent: 50, EndPercent: 100},
+		},
+		Carriages: []models.Carriage{
+			{IsLocomotive: true, StartPercent: 10, EndPercent: 20},
+			{Number: "11", ClassType: 1, StartPercent: 20, EndPercent: 40},
+			{Number: "12", ClassType: 2, StartPercent: 40, EndPercent: 60},
+			{Number: "13", IsClosed: true, StartPercent: 60, EndPercent: 80},
+		},
+	}
+
+	svg := RenderFormationSVGString(formation)
+
+	// Well-formed XML
+	dec := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		testutil.AssertNil(t, err)
+	}
+
+	testutil.AssertTrue(t, strings.HasPrefix(svg, "<svg "))
+	testutil.AssertContains(t, svg, "ICE 623 · Platform 7")
+	testutil.AssertContains(t, svg, `font-weight="bold">A</text>`)
+	testutil.AssertContains(t, svg, `font-weight="bold">B</text>`)
+
+	// Carriages are placed by percent on the 940 unit wide track
+	testutil.AssertContains(t, svg, `<rect x="219.0" y="80" width="186.0" height="40" rx="6" fill="#e4572e"`)
+	testutil.AssertContains(t, svg, `fill="#3fa7d6"`)
+	testutil.AssertContains(t, svg, `>12</text>`)
+	testutil.AssertContains(t, svg, `>X</text>`)
+	testutil.AssertFalse(t, strings.Contains(svg, ">13</text>"))
+
+	// Arrow at the right end of the train
+	testutil.AssertContains(t, svg, `<polygon points="786.0,90 786.0,110 802.0,100"`)
+}
+
+func TestRenderFormationSVG_LeftAndEscaping(t *testing.T) {
+	formation := &models.Formation{
+		Platform:  "5 <D-F>",
+		Carriages: []models.Carriage{{Number: "1", ClassType: 2, StartPercent: 50, EndPercent: 60}},
+	}
+
+	svg := RenderFormationSVGString(formation)
+	testutil.AssertContains(t, svg, "Platform 5 &lt;D-F&gt;")
+	testutil.AssertContains(t, svg, `<polygon points="496.0,90 496.0,110 480.0,100"`)
+}
+
+func TestRenderFormationSVG_Nil(t *testing.T) {
+	svg := RenderFormationSVGString(nil)
+	testutil.AssertContains(t, svg, "No formation data available.")
+	testutil.AssertContains(t, svg, "</svg>")
+}
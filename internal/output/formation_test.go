@@ -2,6 +2,7 @@ package output
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/mobil-koeln/moko-cli/internal/models"
@@ -341,3 +342,99 @@ func TestRenderFormation_Direction(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderFormation_Front(t *testing.T) {
+	tests := []struct {
+		name  string
+		front *models.TrainFront
+		want  []string
+	}{
+		{
+			name:  "right",
+			front: &models.TrainFront{Side: "right", Carriage: "1", Sector: "A", Destination: "München Hbf"},
+			want:  []string{"Front of train (car 1, sector A) points toward München Hbf ->"},
+		},
+		{
+			name:  "left without destination",
+			front: &models.TrainFront{Side: "left", Carriage: "9"},
+			want:  []string{"<- Front of train (car 9)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formation := &models.Formation{
+				Platform:  "7",
+				Carriages: []models.Carriage{{Number: "1", StartPercent: 10, LengthPercent: 10}},
+				Derived:   models.FormationDerived{Front: tt.front},
+			}
+
+			var buf bytes.Buffer
+			RenderFormation(&buf, formation, TableOptions{Colors: NewColors(ColorNever)})
+
+			output := buf.String()
+			for _, w := range tt.want {
+				testutil.AssertContains(t, output, w)
+			}
+		})
+	}
+}
+
+func TestRenderFormation_Coverage(t *testing.T) {
+	sectors := []models.Sector{{Name: "A"}, {Name: "B"}, {Name: "C"}, {Name: "D"}}
+	tests := []struct {
+		name string
+		cov  *models.PlatformCoverage
+		want string
+	}{
+		{"range", &models.PlatformCoverage{Sectors: []string{"A", "B", "C"}, Partial: true, TrainMeters: 210}, "Train occupies sectors A–C only (210 m)"},
+		{"single", &models.PlatformCoverage{Sectors: []string{"D"}, Partial: true}, "Train occupies sector D only"},
+		{"gap", &models.PlatformCoverage{Sectors: []string{"A", "C"}, Partial: true}, "Train occupies sectors A, C only"},
+		{"full", &models.PlatformCoverage{Sectors: []string{"A", "B", "C", "D"}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formation := &models.Formation{
+				Platform: "7",
+				Sectors:  sectors,
+				Derived:  models.FormationDerived{Coverage: tt.cov},
+			}
+
+			var buf bytes.Buffer
+			RenderFormation(&buf, formation, TableOptions{Colors: NewColors(ColorNever)})
+
+			output := stripANSI(buf.String())
+			if tt.want == "" {
+				testutil.AssertFalse(t, strings.Contains(output, "occupies"))
+				return
+			}
+			testutil.AssertContains(t, output, tt.want)
+		})
+	}
+}
+
+func TestRenderFormation_NoDecoration(t *testing.T) {
+	formation := &models.Formation{
+		Platform: "7",
+		Groups: []models.Group{
+			{
+				Description: "ICE 4",
+				TrainType:   "ICE",
+				TrainNo:     "623",
+				Destination: "München Hbf",
+				Carriages:   []models.Carriage{{Number: "1", Type: "FirstClass", ClassType: 1}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderFormation(&buf, formation, TableOptions{Colors: NewColors(ColorNever), NoDecoration: true})
+
+	out := buf.String()
+	testutil.AssertFalse(t, strings.Contains(out, "Platform:"))
+	testutil.AssertFalse(t, strings.Contains(out, "ICE 4"))
+	testutil.AssertFalse(t, strings.Contains(out, "\n\n"))
+	testutil.AssertContains(t, out, "ICE 623  → München Hbf\n")
+	testutil.AssertContains(t, out, "FirstClass")
+}
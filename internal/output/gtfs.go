@@ -0,0 +1,88 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+// GTFSJSONVersion is the version of the gtfs-json schema. Fields may be added
+// within a version; renaming or removing one, or changing its meaning,
+// requires a new version.
+const GTFSJSONVersion = 1
+
+// GTFSBoard is the documented, versioned JSON shape of a departure board,
+// modeled on GTFS-Realtime trip updates. Unlike the --json output it does not
+// follow the internal structs, so integrators are unaffected by refactors.
+type GTFSBoard struct {
+	Version    int             `json:"version"`
+	Stop       GTFSStop        `json:"stop"`
+	Departures []GTFSDeparture `json:"departures"`
+}
+
+// GTFSStop identifies the station of a board
+type GTFSStop struct {
+	ID   string `json:"id"` // EVA number
+	Name string `json:"name,omitempty"`
+}
+
+// GTFSDeparture is one departure in the gtfs-json schema. Times are RFC 3339
+// with the station's UTC offset; realtime is null without a live estimate.
+type GTFSDeparture struct {
+	TripID            string  `json:"tripId"`
+	Route             string  `json:"route"`
+	RouteType         string  `json:"routeType,omitempty"`
+	Headsign          string  `json:"headsign"`
+	Scheduled         *string `json:"scheduled"`
+	Realtime          *string `json:"realtime"`
+	DelayMinutes      int     `json:"delayMinutes"`
+	Platform          string  `json:"platform,omitempty"`
+	ScheduledPlatform string  `json:"scheduledPlatform,omitempty"`
+	Cancelled         bool    `json:"cancelled"`
+}
+
+// NewGTFSBoard maps departures at stop into the gtfs-json schema
+func NewGTFSBoard(stop GTFSStop, deps []models.Departure) GTFSBoard {
+	board := GTFSBoard{
+		Version:    GTFSJSONVersion,
+		Stop:       stop,
+		Departures: make([]GTFSDeparture, 0, len(deps)),
+	}
+	for _, dep := range deps {
+		route := dep.Line
+		if route == "" {
+			route = dep.TrainShort
+		}
+		board.Departures = append(board.Departures, GTFSDeparture{
+			TripID:            dep.JourneyID,
+			Route:             route,
+			RouteType:         dep.Product,
+			Headsign:          dep.Destination,
+			Scheduled:         rfc3339(dep.SchedDep),
+			Realtime:          rfc3339(dep.RTDep),
+			DelayMinutes:      dep.Delay,
+			Platform:          dep.EffectivePlatform(),
+			ScheduledPlatform: dep.Platform,
+			Cancelled:         dep.IsCancelled,
+		})
+	}
+	return board
+}
+
+// RenderDeparturesGTFSJSON writes departures at stop as indented gtfs-json
+func RenderDeparturesGTFSJSON(w io.Writer, stop GTFSStop, deps []models.Departure) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(NewGTFSBoard(stop, deps))
+}
+
+// rfc3339 formats t as RFC 3339 with its offset, or nil for no time
+func rfc3339(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
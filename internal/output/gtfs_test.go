@@ -0,0 +1,77 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// assertGolden compares got with testdata/name, rewriting it with -update
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		testutil.AssertNil(t, os.WriteFile(path, got, 0600))
+	}
+	want, err := os.ReadFile(path)
+	testutil.AssertNil(t, err)
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file (run go test -update if intended):\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func TestRenderDeparturesGTFSJSON(t *testing.T) {
+	berlin := time.FixedZone("CEST", 2*3600)
+	sched := time.Date(2024, 6, 3, 14, 30, 0, 0, berlin)
+	rt := sched.Add(4 * time.Minute)
+	bus := time.Date(2024, 6, 3, 14, 35, 0, 0, berlin)
+
+	deps := []models.Departure{
+		{
+			JourneyID:   "2|#VN#1#ST#1717322400#PI#0#ZI#123#TA#0#DA#30624#",
+			Line:        "ICE 123",
+			Product:     "ICE",
+			Destination: "München Hbf",
+			Platform:    "7",
+			RTPlatform:  "8",
+			SchedDep:    &sched,
+			RTDep:       &rt,
+			Dep:         &rt,
+			Delay:       4,
+		},
+		{
+			JourneyID:   "2|#VN#1#ST#1717322400#PI#0#ZI#456#TA#0#DA#30624#",
+			TrainShort:  "Bus 61",
+			Product:     "BUS",
+			Destination: "Flughafen",
+			SchedDep:    &bus,
+			Dep:         &bus,
+			IsCancelled: true,
+		},
+	}
+
+	var buf bytes.Buffer
+	err := RenderDeparturesGTFSJSON(&buf, GTFSStop{ID: "8000105", Name: "Frankfurt(Main)Hbf"}, deps)
+	testutil.AssertNil(t, err)
+	assertGolden(t, "departures.gtfs.json", buf.Bytes())
+}
+
+func TestRenderDeparturesGTFSJSON_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	testutil.AssertNil(t, RenderDeparturesGTFSJSON(&buf, GTFSStop{ID: "8000105"}, nil))
+
+	var board map[string]any
+	testutil.AssertNil(t, json.Unmarshal(buf.Bytes(), &board))
+	testutil.AssertEqual(t, board["version"], any(float64(GTFSJSONVersion)))
+	// An empty board is an empty list, not null
+	testutil.AssertContains(t, buf.String(), `"departures": []`)
+}
@@ -0,0 +1,195 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+// icsTZID is the time zone calendar times are written in
+const icsTZID = "Europe/Berlin"
+
+// icsMaxLine is the longest content line in octets before folding (RFC 5545)
+const icsMaxLine = 75
+
+// icsNow returns the DTSTAMP time; replaceable in tests
+var icsNow = time.Now
+
+// icsBerlinTimezone defines Europe/Berlin for calendars that don't know the
+// TZID, with the EU daylight saving rules
+var icsBerlinTimezone = []string{
+	"BEGIN:VTIMEZONE",
+	"TZID:" + icsTZID,
+	"BEGIN:DAYLIGHT",
+	"TZOFFSETFROM:+0100",
+	"TZOFFSETTO:+0200",
+	"TZNAME:CEST",
+	"DTSTART:19700329T020000",
+	"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+	"END:DAYLIGHT",
+	"BEGIN:STANDARD",
+	"TZOFFSETFROM:+0200",
+	"TZOFFSETTO:+0100",
+	"TZNAME:CET",
+	"DTSTART:19701025T030000",
+	"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+	"END:STANDARD",
+	"END:VTIMEZONE",
+}
+
+// RenderJourneyICS writes the journey as an iCalendar file with a single
+// event from the departure at the first stop to the arrival at the last.
+// The summary names the train and its endpoints, the location holds the
+// departure station and platform and the description lists every stop.
+// Cancelled journeys are marked STATUS:CANCELLED. Journeys without times
+// fall back to an all-day event on the operating day.
+func RenderJourneyICS(w io.Writer, journey *models.Journey) {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//mobil-koeln//moko//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+	if journey != nil && len(journey.Stops) > 0 {
+		lines = append(lines, icsBerlinTimezone...)
+		lines = append(lines, icsJourneyEvent(journey)...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(icsFold(line))
+		sb.WriteString("\r\n")
+	}
+	_, _ = io.WriteString(w, sb.String())
+}
+
+// icsJourneyEvent returns the VEVENT lines for a journey with stops
+func icsJourneyEvent(journey *models.Journey) []string {
+	first, last := journey.Stops[0], journey.Stops[len(journey.Stops)-1]
+
+	hash := sha256.Sum256([]byte(journey.ID))
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + hex.EncodeToString(hash[:16]) + "@moko",
+		"DTSTAMP:" + icsNow().UTC().Format("20060102T150405Z"),
+	}
+
+	start := firstTime(first.Dep, first.SchedDep)
+	end := firstTime(last.Arr, last.SchedArr)
+	switch {
+	case start != nil:
+		lines = append(lines, "DTSTART;TZID="+icsTZID+":"+icsLocalTime(*start))
+		if end != nil && end.After(*start) {
+			lines = append(lines, "DTEND;TZID="+icsTZID+":"+icsLocalTime(*end))
+		}
+	case journey.Day != nil:
+		lines = append(lines, "DTSTART;VALUE=DATE:"+journey.Day.Format("20060102"))
+	}
+
+	summary := journey.Name
+	if len(journey.Stops) > 1 {
+		summary += ": " + first.Name + " → " + last.Name
+	}
+	lines = append(lines, "SUMMARY:"+icsEscape(strings.TrimPrefix(summary, ": ")))
+
+	location := first.Name
+	if platform := first.EffectivePlatform(); platform != "" {
+		location += ", platform " + platform
+	}
+	lines = append(lines, "LOCATION:"+icsEscape(location))
+	lines = append(lines, "DESCRIPTION:"+icsEscape(icsRoute(journey)))
+
+	status := "CONFIRMED"
+	if journey.IsCancelled {
+		status = "CANCELLED"
+	}
+	return append(lines, "STATUS:"+status, "END:VEVENT")
+}
+
+// icsRoute describes the stops of a journey, one per line
+func icsRoute(journey *models.Journey) string {
+	var b strings.Builder
+	if journey.Operator != "" {
+		b.WriteString("Operator: " + journey.Operator + "\n")
+	}
+	for i, stop := range journey.Stops {
+		t := firstTime(stop.Dep, stop.SchedDep)
+		if i == len(journey.Stops)-1 || t == nil {
+			t = firstTime(stop.Arr, stop.SchedArr, t)
+		}
+		clock := "     "
+		if t != nil {
+			clock = icsBerlin(*t).Format("15:04")
+		}
+		line := clock + " " + stop.Name
+		if platform := stop.EffectivePlatform(); platform != "" {
+			line += " (platform " + platform + ")"
+		}
+		if stop.IsCancelled {
+			line += " (cancelled)"
+		}
+		b.WriteString(line)
+		if i < len(journey.Stops)-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// firstTime returns the first non-nil time
+func firstTime(times ...*time.Time) *time.Time {
+	for _, t := range times {
+		if t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// icsBerlin converts t to Europe/Berlin, keeping its zone when the zone
+// database is unavailable
+func icsBerlin(t time.Time) time.Time {
+	if loc, err := time.LoadLocation(icsTZID); err == nil {
+		return t.In(loc)
+	}
+	return t
+}
+
+// icsLocalTime formats t as a local date-time in Europe/Berlin
+func icsLocalTime(t time.Time) string {
+	return icsBerlin(t).Format("20060102T150405")
+}
+
+// icsEscape escapes a TEXT property value
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icsFold splits a content line into lines of at most icsMaxLine octets,
+// continuing each with a leading space, without splitting UTF-8 sequences
+func icsFold(line string) string {
+	if len(line) <= icsMaxLine {
+		return line
+	}
+	var b strings.Builder
+	limit := icsMaxLine
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icsMaxLine - 1 // the leading space counts
+	}
+	b.WriteString(line)
+	return b.String()
+}
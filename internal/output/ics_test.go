@@ -0,0 +1,89 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestRenderJourneyICS(t *testing.T) {
+	orig := icsNow
+	icsNow = func() time.Time { return time.Date(2024, 1, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600)) }
+	t.Cleanup(func() { icsNow = orig })
+
+	journey := newShareJourney()
+	journey.ID = "2|#VN#1#ST#1704063600#PI#0#ZI#123#TA#0#DA#10124#"
+	journey.Operator = "DB Fernverkehr AG"
+	journey.Stops[0].Platform = "4"
+	journey.Stops[0].RTPlatform = "5"
+
+	out := RenderJourneyICSString(journey)
+
+	// Every line ends in CRLF and stays within 75 octets
+	testutil.AssertTrue(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+	for _, line := range lines {
+		testutil.AssertFalse(t, strings.Contains(line, "\n"))
+		testutil.AssertTrue(t, len(line) <= icsMaxLine)
+	}
+
+	// Unfold continuation lines to check the properties
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//mobil-koeln//moko//EN\r\n",
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Berlin\r\n",
+		"BEGIN:VEVENT\r\nUID:",
+		"@moko\r\nDTSTAMP:20240101T083000Z\r\n",
+		// 12:00 UTC is 13:00 in Berlin
+		"DTSTART;TZID=Europe/Berlin:20240101T130000\r\n",
+		"DTEND;TZID=Europe/Berlin:20240101T163000\r\n",
+		"SUMMARY:ICE 623: Köln Hbf → München Hbf\r\n",
+		"LOCATION:Köln Hbf\\, platform 5\r\n",
+		"DESCRIPTION:Operator: DB Fernverkehr AG\\n13:00 Köln Hbf (platform 5)\\n14:40 Mannheim Hbf\\n",
+		"\\n16:30 München Hbf\r\n",
+		"STATUS:CONFIRMED\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		testutil.AssertContains(t, unfolded, want)
+	}
+
+	// The description is long enough to be folded
+	testutil.AssertTrue(t, strings.Contains(out, "\r\n "))
+}
+
+func TestRenderJourneyICS_Cancelled(t *testing.T) {
+	journey := newShareJourney()
+	journey.IsCancelled = true
+	journey.Stops[2].IsCancelled = true
+
+	out := strings.ReplaceAll(RenderJourneyICSString(journey), "\r\n ", "")
+	testutil.AssertContains(t, out, "STATUS:CANCELLED\r\n")
+	testutil.AssertContains(t, out, "Vaihingen (Enz) (cancelled)")
+}
+
+func TestRenderJourneyICS_NoTimes(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	journey := &models.Journey{Name: "RE 5", Day: &day, Stops: []models.Stop{{Name: "Köln Hbf"}, {Name: "Bonn Hbf"}}}
+
+	out := RenderJourneyICSString(journey)
+	testutil.AssertContains(t, out, "DTSTART;VALUE=DATE:20240101\r\n")
+	testutil.AssertFalse(t, strings.Contains(out, "DTEND"))
+
+	// Without stops the calendar is empty but valid
+	testutil.AssertEqual(t, RenderJourneyICSString(nil),
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//mobil-koeln//moko//EN\r\nCALSCALE:GREGORIAN\r\nMETHOD:PUBLISH\r\nEND:VCALENDAR\r\n")
+}
+
+func TestICSFold(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("ä", 60)
+	folded := icsFold(line)
+	parts := strings.Split(folded, "\r\n ")
+	testutil.AssertTrue(t, len(parts) > 1)
+	for _, part := range parts {
+		testutil.AssertTrue(t, len(part) <= icsMaxLine-1 || part == parts[0] && len(part) <= icsMaxLine)
+		testutil.AssertTrue(t, strings.ToValidUTF8(part, "?") == part)
+	}
+	testutil.AssertEqual(t, strings.Join(parts, ""), line)
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"fmt"
+	"io"
+)
+
+// Page writes header plus pageSize rows at a time to w and waits for a key
+// read from in: space, enter, n or j show the next page, b, p or k the
+// previous one, q or Ctrl+C quit. Paging past the last page ends, as does
+// end of input. Lines end in \r\n so the output is correct on a terminal in
+// raw mode.
+func Page(in io.Reader, w io.Writer, header, rows []string, pageSize int) error {
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	pages := (len(rows) + pageSize - 1) / pageSize
+	if pages == 0 {
+		pages = 1
+	}
+
+	page := 0
+	for {
+		start := page * pageSize
+		end := min(start+pageSize, len(rows))
+
+		ClearScreen(w)
+		for _, line := range header {
+			_, _ = fmt.Fprint(w, line, "\r\n")
+		}
+		for _, line := range rows[start:end] {
+			_, _ = fmt.Fprint(w, line, "\r\n")
+		}
+		_, _ = fmt.Fprintf(w, "\r\n-- %d-%d of %d -- space: next  b: back  q: quit", start+1, end, len(rows))
+
+		next, err := readPageKey(in)
+		if err != nil {
+			return err
+		}
+		switch {
+		case next == 0 || (next > 0 && page == pages-1):
+			_, _ = fmt.Fprint(w, "\r\n")
+			return nil
+		case next > 0:
+			page++
+		case page > 0:
+			page--
+		}
+	}
+}
+
+// readPageKey reads keys until one is recognized and returns 1 for the next
+// page, -1 for the previous page and 0 to quit
+func readPageKey(in io.Reader) (int, error) {
+	buf := make([]byte, 1)
+	for {
+		n, err := in.Read(buf)
+		if err == io.EOF {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			continue
+		}
+		switch buf[0] {
+		case ' ', '\r', '\n', 'n', 'j':
+			return 1, nil
+		case 'b', 'p', 'k':
+			return -1, nil
+		case 'q', 3, 4: // q, Ctrl+C, Ctrl+D
+			return 0, nil
+		}
+	}
+}
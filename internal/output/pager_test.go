@@ -0,0 +1,38 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestPage(t *testing.T) {
+	header := []string{"Journey: RE 5"}
+	rows := []string{"stop 1", "stop 2", "stop 3", "stop 4", "stop 5"}
+
+	page := func(keys string) []string {
+		var buf bytes.Buffer
+		testutil.AssertNil(t, Page(strings.NewReader(keys), &buf, header, rows, 2))
+		// One entry per screen drawn
+		return strings.Split(buf.String(), "\033[2J\033[H")[1:]
+	}
+
+	// Next, back, unknown keys are ignored, then quit
+	screens := page("nxbq")
+	testutil.AssertLen(t, screens, 3)
+	testutil.AssertContains(t, screens[0], "Journey: RE 5\r\nstop 1\r\nstop 2\r\n")
+	testutil.AssertContains(t, screens[0], "-- 1-2 of 5 --")
+	testutil.AssertContains(t, screens[1], "stop 3\r\nstop 4\r\n")
+	testutil.AssertContains(t, screens[1], "Journey: RE 5")
+	testutil.AssertContains(t, screens[2], "stop 1")
+
+	// Paging past the last page ends
+	screens = page("   ")
+	testutil.AssertLen(t, screens, 3)
+	testutil.AssertContains(t, screens[2], "-- 5-5 of 5 --")
+
+	// End of input quits
+	testutil.AssertLen(t, page(""), 1)
+}
@@ -0,0 +1,112 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+// RenderJourneyShare writes a one-paragraph plain-text summary of a journey's
+// current state, meant to be pasted into a message: train, position, delay,
+// next major stop and arrival at the terminus. It never emits ANSI codes.
+func RenderJourneyShare(w io.Writer, journey *models.Journey, now time.Time) {
+	if journey == nil || len(journey.Stops) == 0 {
+		_, _ = fmt.Fprintln(w, "No journey data found.")
+		return
+	}
+
+	stops := journey.Stops
+	last := stops[len(stops)-1]
+	train := journey.Name
+	if train == "" {
+		train = "The train"
+	}
+
+	if journey.IsCancelled {
+		_, _ = fmt.Fprintf(w, "%s to %s is cancelled.\n", train, last.Name)
+		return
+	}
+
+	idx := FindCurrentStopIndex(stops, now)
+	current := stops[idx]
+
+	if idx == len(stops)-1 {
+		sentence := fmt.Sprintf("%s has arrived at %s", train, last.Name)
+		if last.Arr != nil {
+			sentence += " at " + shareTime(last.Arr, last.SchedArr)
+		}
+		_, _ = fmt.Fprintln(w, sentence+".")
+		return
+	}
+
+	var sentences []string
+	switch {
+	case idx == 0 && current.Dep != nil && now.Before(*current.Dep):
+		sentences = append(sentences, fmt.Sprintf("%s to %s leaves %s at %s%s.",
+			train, last.Name, current.Name, current.Dep.Format("15:04"), shareDelay(current.Delay)))
+	case current.Dep != nil && now.Before(*current.Dep):
+		sentences = append(sentences, fmt.Sprintf("%s to %s is currently at %s%s.",
+			train, last.Name, current.Name, shareDelay(current.Delay)))
+	default:
+		sentences = append(sentences, fmt.Sprintf("%s to %s has just left %s%s.",
+			train, last.Name, current.Name, shareDelay(current.Delay)))
+	}
+
+	if next := nextMajorStop(stops, idx); next >= 0 {
+		stop := stops[next]
+		sentence := "Next major stop: " + stop.Name
+		if stop.Arr != nil {
+			sentence += " at " + stop.Arr.Format("15:04")
+		}
+		sentences = append(sentences, sentence+".")
+	}
+
+	if last.Arr != nil {
+		sentences = append(sentences, fmt.Sprintf("Arrival in %s at %s.",
+			last.Name, shareTime(last.Arr, last.SchedArr)))
+	}
+
+	_, _ = fmt.Fprintln(w, strings.Join(sentences, " "))
+}
+
+// shareDelay phrases a delay in minutes for the share summary
+func shareDelay(delay int) string {
+	switch {
+	case delay > 0:
+		return fmt.Sprintf(", running %d min late", delay)
+	case delay < 0:
+		return fmt.Sprintf(", running %d min early", -delay)
+	}
+	return ", on time"
+}
+
+// shareTime formats an effective time, adding the scheduled time when it differs
+func shareTime(effective, sched *time.Time) string {
+	s := effective.Format("15:04")
+	if sched != nil && !sched.Equal(*effective) {
+		s += " (scheduled " + sched.Format("15:04") + ")"
+	}
+	return s
+}
+
+// nextMajorStop returns the index of the next served main station
+// ("Hbf") after idx, excluding the terminus. Without one it falls back to the
+// next served stop before the terminus, or -1.
+func nextMajorStop(stops []models.Stop, idx int) int {
+	fallback := -1
+	for i := idx + 1; i < len(stops)-1; i++ {
+		if stops[i].IsCancelled {
+			continue
+		}
+		if strings.Contains(stops[i].Name, "Hbf") {
+			return i
+		}
+		if fallback < 0 {
+			fallback = i
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,73 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+// newShareJourney returns an ICE from Köln to München with a 5 minute delay
+func newShareJourney() *models.Journey {
+	at := func(h, m int) *time.Time {
+		t := time.Date(2024, 1, 1, h, m, 0, 0, time.UTC)
+		return &t
+	}
+	late := func(h, m int) *time.Time {
+		t := at(h, m).Add(5 * time.Minute)
+		return &t
+	}
+	return &models.Journey{
+		Name: "ICE 623",
+		Stops: []models.Stop{
+			{Name: "Köln Hbf", SchedDep: at(12, 0), Dep: at(12, 0)},
+			{Name: "Mannheim Hbf", SchedArr: at(13, 30), Arr: late(13, 30), SchedDep: at(13, 35), Dep: late(13, 35), Delay: 5},
+			{Name: "Vaihingen (Enz)", SchedArr: at(14, 0), Arr: late(14, 0), SchedDep: at(14, 1), Dep: late(14, 1), Delay: 5},
+			{Name: "Stuttgart Hbf", SchedArr: at(14, 10), Arr: late(14, 10), SchedDep: at(14, 15), Dep: late(14, 15), Delay: 5},
+			{Name: "München Hbf", SchedArr: at(15, 25), Arr: late(15, 25), Delay: 5},
+		},
+	}
+}
+
+func TestRenderJourneyShare(t *testing.T) {
+	var buf bytes.Buffer
+	RenderJourneyShare(&buf, newShareJourney(), time.Date(2024, 1, 1, 13, 36, 0, 0, time.UTC))
+
+	testutil.AssertEqual(t, buf.String(),
+		"ICE 623 to München Hbf is currently at Mannheim Hbf, running 5 min late. "+
+			"Next major stop: Stuttgart Hbf at 14:15. "+
+			"Arrival in München Hbf at 15:30 (scheduled 15:25).\n")
+	testutil.AssertFalse(t, strings.Contains(buf.String(), "\x1b"))
+}
+
+func TestRenderJourneyShare_States(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"before departure", time.Date(2024, 1, 1, 11, 50, 0, 0, time.UTC), "ICE 623 to München Hbf leaves Köln Hbf at 12:00, on time."},
+		{"between stops", time.Date(2024, 1, 1, 13, 50, 0, 0, time.UTC), "has just left Mannheim Hbf, running 5 min late."},
+		{"arrived", time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC), "ICE 623 has arrived at München Hbf at 15:30 (scheduled 15:25).\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			RenderJourneyShare(&buf, newShareJourney(), tt.now)
+			testutil.AssertContains(t, buf.String(), tt.want)
+		})
+	}
+}
+
+func TestRenderJourneyShare_Cancelled(t *testing.T) {
+	j := newShareJourney()
+	j.IsCancelled = true
+
+	var buf bytes.Buffer
+	RenderJourneyShare(&buf, j, time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC))
+	testutil.AssertEqual(t, buf.String(), "ICE 623 to München Hbf is cancelled.\n")
+}
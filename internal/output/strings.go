@@ -0,0 +1,96 @@
+package output
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+// The *String variants return what the writer-based renderers would write,
+// for callers that need the text rather than a stream (TUI panes, tests).
+
+// RenderDeparturesString returns the output of RenderDepartures
+func RenderDeparturesString(departures []models.Departure, opts TableOptions) string {
+	return renderString(func(w io.Writer) { RenderDepartures(w, departures, opts) })
+}
+
+// RenderLocationsString returns the output of RenderLocations
+func RenderLocationsString(locations []models.Location, opts TableOptions) string {
+	return renderString(func(w io.Writer) { RenderLocations(w, locations, opts) })
+}
+
+// RenderStationString returns the output of RenderStation
+func RenderStationString(loc *models.Location, opts TableOptions) string {
+	return renderString(func(w io.Writer) { RenderStation(w, loc, opts) })
+}
+
+// RenderJourneyString returns the output of RenderJourney
+func RenderJourneyString(journey *models.Journey, opts TableOptions) string {
+	return renderString(func(w io.Writer) { RenderJourney(w, journey, opts) })
+}
+
+// RenderJourneyLines returns the lines of RenderJourney split into the
+// header and the rows below it: stops, leg sub-headers and hidden stop notes.
+// Pagers keep the header on every page.
+func RenderJourneyLines(journey *models.Journey, opts TableOptions) (header, rows []string) {
+	if journey == nil {
+		return []string{"No journey data found."}, nil
+	}
+	c := opts.Colors
+	if c == nil {
+		c = NewColors(ColorNever)
+	}
+
+	now := opts.now()
+	header = renderLines(func(w io.Writer) { renderJourneyHeader(w, c, journey, opts, now) })
+	rows = renderLines(func(w io.Writer) { renderJourneyStops(w, c, journey, opts, now) })
+	return header, rows
+}
+
+// RenderJourneyShareString returns the output of RenderJourneyShare
+func RenderJourneyShareString(journey *models.Journey, now time.Time) string {
+	return renderString(func(w io.Writer) { RenderJourneyShare(w, journey, now) })
+}
+
+// RenderJourneyTimetableString returns the output of RenderJourneyTimetable
+func RenderJourneyTimetableString(journey *models.Journey) string {
+	return renderString(func(w io.Writer) { RenderJourneyTimetable(w, journey) })
+}
+
+// RenderFormationString returns the output of RenderFormation
+func RenderFormationString(formation *models.Formation, opts TableOptions) string {
+	return renderString(func(w io.Writer) { RenderFormation(w, formation, opts) })
+}
+
+// RenderFormationSVGString returns the output of RenderFormationSVG
+func RenderFormationSVGString(formation *models.Formation) string {
+	return renderString(func(w io.Writer) { RenderFormationSVG(w, formation) })
+}
+
+// RenderConnectionsString returns the output of RenderConnections
+func RenderConnectionsString(connections []models.Connection, opts TableOptions) string {
+	return renderString(func(w io.Writer) { RenderConnections(w, connections, opts) })
+}
+
+// renderString collects everything render writes
+func renderString(render func(w io.Writer)) string {
+	var b strings.Builder
+	render(&b)
+	return b.String()
+}
+
+// renderLines returns what render writes as lines, without the final newline
+func renderLines(render func(w io.Writer)) []string {
+	out := strings.TrimSuffix(renderString(render), "\n")
+	if out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// RenderJourneyICSString returns the output of RenderJourneyICS
+func RenderJourneyICSString(journey *models.Journey) string {
+	return renderString(func(w io.Writer) { RenderJourneyICS(w, journey) })
+}
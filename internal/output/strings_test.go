@@ -0,0 +1,64 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestRenderStrings_MatchWriters(t *testing.T) {
+	dep := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	departures := []models.Departure{{Line: "RE 5", Destination: "Koblenz Hbf", Dep: &dep, Platform: "4"}}
+	journey := newShareJourney()
+	opts := TableOptions{Colors: NewColors(ColorNever), Now: func() time.Time { return dep }}
+
+	var buf bytes.Buffer
+	RenderDepartures(&buf, departures, opts)
+	testutil.AssertEqual(t, RenderDeparturesString(departures, opts), buf.String())
+
+	buf.Reset()
+	RenderJourney(&buf, journey, opts)
+	testutil.AssertEqual(t, RenderJourneyString(journey, opts), buf.String())
+
+	buf.Reset()
+	RenderJourneyShare(&buf, journey, dep)
+	testutil.AssertEqual(t, RenderJourneyShareString(journey, dep), buf.String())
+
+	testutil.AssertEqual(t, RenderLocationsString(nil, opts), "No stations found.\n")
+}
+
+func TestRenderJourneyLines(t *testing.T) {
+	dep := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	journey := newShareJourney()
+	journey.Stops[0].Name = "Route: Köln Hbf"
+
+	tests := []struct {
+		name      string
+		opts      TableOptions
+		headerLen int
+	}{
+		{"decorated", TableOptions{Colors: NewColors(ColorNever), Now: func() time.Time { return dep }}, 4},
+		{"bare", TableOptions{Colors: NewColors(ColorNever), Now: func() time.Time { return dep }, NoDecoration: true, GroupLegs: true}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, rows := RenderJourneyLines(journey, tt.opts)
+			testutil.AssertLen(t, header, tt.headerLen)
+			testutil.AssertLen(t, rows, len(journey.Stops))
+			// A station named like the header does not move the split
+			testutil.AssertContains(t, rows[0], "Route: Köln Hbf")
+
+			// Together they are exactly what RenderJourney writes
+			joined := strings.Join(append(header, rows...), "\n") + "\n"
+			testutil.AssertEqual(t, joined, RenderJourneyString(journey, tt.opts))
+		})
+	}
+
+	header, rows := RenderJourneyLines(nil, TableOptions{})
+	testutil.AssertLen(t, header, 1)
+	testutil.AssertLen(t, rows, 0)
+}
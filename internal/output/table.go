@@ -3,6 +3,8 @@ package output
 import (
 	"fmt"
 	"io"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,12 +16,241 @@ type TableOptions struct {
 	Colors    *Colors
 	ShowVia   bool
 	ShowRoute bool
+	// ShowMessages prints each departure's disruption messages below it
+	ShowMessages bool
+	GroupBy      string // "" for a flat list, or GroupByMode
+	// Compact uses single spaces between board columns
+	Compact bool
+	// Separators draws a │ between board columns
+	Separators bool
+	// PreferScheduled shows timetable times instead of real-time estimates
+	// and hides delays
+	PreferScheduled bool
+	// Summary prints a one-line "now / next stop / ETA" glance above journeys
+	Summary bool
+	// GroupLegs prints a sub-header wherever the train or operator changes
+	// along a journey
+	GroupLegs bool
+	// EstimateDelays shows the last known delay, marked as an estimate, at
+	// later journey stops without real-time data
+	EstimateDelays bool
+	// HideCancelledStops collapses each run of cancelled journey stops into
+	// a line saying how many were hidden
+	HideCancelledStops bool
+	// Width is the terminal width to fit board rows into by dropping
+	// columns; 0 shows all columns
+	Width int
+	// DualZone additionally shows each time in this zone ("15:30 / 14:30")
+	DualZone *time.Location
+	// Now returns the current time for positioning journeys; nil uses the
+	// real clock
+	Now func() time.Time
+	// DelayStyle selects how board delays are shown: DelayNumeric (default)
+	// or DelayCompact
+	DelayStyle string
+	// NoEmoji uses ASCII instead of Unicode glyphs
+	NoEmoji bool
+	// Arrivals marks the board as an arrival board: the last column shows
+	// where each train comes from ("from Köln Hbf") instead of its terminus
+	Arrivals bool
+	// NoDecoration drops headers, section titles and blank lines so that
+	// every printed line carries data
+	NoDecoration bool
 }
 
+// blankLine prints an empty separator line unless decoration is off
+func (o TableOptions) blankLine(w io.Writer) {
+	if !o.NoDecoration {
+		_, _ = fmt.Fprintln(w)
+	}
+}
+
+// header prints a title line unless decoration is off
+func (o TableOptions) header(w io.Writer, line string) {
+	if !o.NoDecoration {
+		_, _ = fmt.Fprintln(w, line)
+	}
+}
+
+// Board delay styles
+const (
+	// DelayNumeric shows delays as signed minutes ("+5")
+	DelayNumeric = "numeric"
+	// DelayCompact shows delays as a single glyph (·/↑/↑↑/✕)
+	DelayCompact = "compact"
+)
+
+// delayWidth returns the width of the board delay column
+func (o TableOptions) delayWidth() int {
+	if o.DelayStyle == DelayCompact {
+		return 2
+	}
+	return 4
+}
+
+// formatDelay renders the board delay column for a departure
+func (o TableOptions) formatDelay(c *Colors, dep models.Departure) string {
+	if o.DelayStyle == DelayCompact {
+		return c.FormatDelayCompact(o.shownDelay(dep.Delay), dep.IsCancelled, o.NoEmoji)
+	}
+	return c.FormatDelay(o.shownDelay(dep.Delay))
+}
+
+// now returns the current time from the configured clock
+func (o TableOptions) now() time.Time {
+	if o.Now != nil {
+		return o.Now()
+	}
+	return time.Now()
+}
+
+// timeWidth returns the width of a formatted time column
+func (o TableOptions) timeWidth() int {
+	if o.DualZone != nil {
+		return len("15:04 / 15:04")
+	}
+	return len("15:04")
+}
+
+// formatTime formats a time as HH:MM, followed by the DualZone time when one
+// is set and its wall clock differs. The result is padded to timeWidth.
+func (o TableOptions) formatTime(t time.Time) string {
+	s := t.Format("15:04")
+	if o.DualZone != nil {
+		if alt := t.In(o.DualZone).Format("15:04"); alt != s {
+			s += " / " + alt
+		}
+	}
+	return fmt.Sprintf("%-*s", o.timeWidth(), s)
+}
+
+// pickTime returns the scheduled time when preferred and known, otherwise the
+// effective (real-time if available) time
+func (o TableOptions) pickTime(sched, effective *time.Time) *time.Time {
+	if o.PreferScheduled && sched != nil {
+		return sched
+	}
+	return effective
+}
+
+// shownDelay returns the delay to display, which is none for scheduled times
+func (o TableOptions) shownDelay(delay int) int {
+	if o.PreferScheduled {
+		return 0
+	}
+	return delay
+}
+
+// rowLayout describes the column spacing of a board row
+type rowLayout struct {
+	gaps          [4]string // time|delay|line|platform|destination
+	gapWidths     [4]int    // visible widths of gaps
+	platformWidth int
+	indent        string // prefix of via/journey continuation lines
+
+	// Columns dropped by Width to fit a narrow terminal
+	hidePlatform, hideDelay, hideVia bool
+}
+
+// minDestWidth is the destination room kept before Width drops columns
+const minDestWidth = 20
+
+// newRowLayout returns the row layout for the given options. The default
+// layout keeps the original double-spaced columns.
+func newRowLayout(opts TableOptions, c *Colors) rowLayout {
+	// wider time column with DualZone, narrower delay column when compact
+	extra := opts.timeWidth() - 5 + opts.delayWidth() - 4
+	if !opts.Compact && !opts.Separators {
+		layout := rowLayout{
+			gaps:          [4]string{" ", "  ", "  ", " "},
+			gapWidths:     [4]int{1, 2, 2, 1},
+			platformWidth: 7,
+			indent:        strings.Repeat(" ", 30+extra),
+		}
+		layout.fit(opts)
+		return layout
+	}
+
+	gap, gapWidth := " ", 1
+	if opts.Separators {
+		gap, gapWidth = " "+c.Muted("│")+" ", 3
+		if opts.Compact {
+			gap, gapWidth = c.Muted("│"), 1
+		}
+	}
+	// time(5) + delay(4) + line(10) + platform(6) + four gaps
+	destColumn := 5 + 4 + extra + 10 + 6 + 4*gapWidth
+	layout := rowLayout{
+		gaps:          [4]string{gap, gap, gap, gap},
+		gapWidths:     [4]int{gapWidth, gapWidth, gapWidth, gapWidth},
+		platformWidth: 6,
+		indent:        strings.Repeat(" ", destColumn),
+	}
+	layout.fit(opts)
+	return layout
+}
+
+// fit drops the platform, then the delay, then the via line until the
+// destination gets minDestWidth columns of opts.Width. A zero Width (unknown,
+// e.g. piped output) keeps everything.
+func (l *rowLayout) fit(opts TableOptions) {
+	if opts.Width <= 0 {
+		return
+	}
+	used := opts.timeWidth() + l.gapWidths[0] + opts.delayWidth() + l.gapWidths[1] + 10 + l.gapWidths[2] +
+		l.platformWidth + l.gapWidths[3]
+	removed := 0
+	drop := func(width int) bool {
+		if used+minDestWidth <= opts.Width {
+			return false
+		}
+		used -= width
+		removed += width
+		return true
+	}
+	l.hidePlatform = drop(l.platformWidth + l.gapWidths[3])
+	l.hideDelay = l.hidePlatform && drop(opts.delayWidth()+l.gapWidths[1])
+	l.hideVia = l.hideDelay && used+minDestWidth > opts.Width
+
+	if n := len(l.indent) - removed; n > 0 {
+		l.indent = l.indent[:n]
+	} else {
+		l.indent = ""
+	}
+}
+
+// GroupByMode groups board rows into rail and local transit sections
+const GroupByMode = "mode"
+
+// transitGroupTitles are the section headers used with GroupByMode, in output order
+var transitGroupTitles = []struct {
+	group string
+	title string
+}{
+	{models.TransitRail, "Rail"},
+	{models.TransitLocal, "Local transit"},
+}
+
+// Badges appended to the destination of board rows
+const (
+	// OnDemandBadge marks call-ahead services that must be reserved before travel
+	OnDemandBadge = "[call required]"
+	// StartsHereBadge marks arrivals of trains that originate at the station
+	StartsHereBadge = "[starts here]"
+	// EndsHereBadge marks departures of trains that terminate at the station
+	EndsHereBadge = "[terminates here]"
+	// HomeBadge marks trains that also call at the configured home station
+	HomeBadge = "[home]"
+)
+
 // RenderDepartures renders departures as a formatted table
 func RenderDepartures(w io.Writer, departures []models.Departure, opts TableOptions) {
 	if len(departures) == 0 {
-		_, _ = fmt.Fprintln(w, "No departures found.")
+		if opts.Arrivals {
+			_, _ = fmt.Fprintln(w, "No arrivals found.")
+		} else {
+			_, _ = fmt.Fprintln(w, "No departures found.")
+		}
 		return
 	}
 
@@ -28,64 +259,253 @@ func RenderDepartures(w io.Writer, departures []models.Departure, opts TableOpti
 		c = NewColors(ColorNever)
 	}
 
-	for _, dep := range departures {
-		// Time
-		timeStr := "??:??"
-		if dep.Dep != nil {
-			timeStr = dep.Dep.Format("15:04")
+	if opts.GroupBy == GroupByMode {
+		first := true
+		for _, g := range transitGroupTitles {
+			var rows []models.Departure
+			for _, dep := range departures {
+				if dep.TransitGroup() == g.group {
+					rows = append(rows, dep)
+				}
+			}
+			if len(rows) == 0 {
+				continue
+			}
+			if !first {
+				opts.blankLine(w)
+			}
+			first = false
+			opts.header(w, c.Header(g.title))
+			renderDepartureRows(w, c, rows, opts)
 		}
+		return
+	}
 
-		// Delay (fixed 4-char width)
-		delayStr := c.FormatDelay(dep.Delay)
+	renderDepartureRows(w, c, departures, opts)
+}
 
-		// Line/Train (truncate/pad to 10 chars)
-		line := dep.Line
-		if line == "" {
-			line = dep.TrainShort
+// renderDepartureRows renders board rows, with a day marker row wherever
+// consecutive rows fall on different dates, e.g. on overnight boards
+func renderDepartureRows(w io.Writer, c *Colors, departures []models.Departure, opts TableOptions) {
+	var lastDay time.Time
+	for _, dep := range departures {
+		if t := opts.pickTime(dep.SchedDep, dep.Dep); t != nil {
+			day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+			if !lastDay.IsZero() && !day.Equal(lastDay) {
+				opts.header(w, c.Muted(dayMarker(day, opts.NoEmoji)))
+			}
+			lastDay = day
 		}
-		if len(line) > 10 {
-			line = line[:10]
+		renderDepartureRow(w, c, dep, opts)
+	}
+}
+
+// dayMarker labels the start of a new day on a board: "— Sat 12 Apr —"
+func dayMarker(day time.Time, ascii bool) string {
+	dash := "—"
+	if ascii {
+		dash = "--"
+	}
+	return fmt.Sprintf("%s %s %s", dash, day.Format("Mon 2 Jan"), dash)
+}
+
+// renderDepartureRow renders a single board row plus optional via/journey lines
+func renderDepartureRow(w io.Writer, c *Colors, dep models.Departure, opts TableOptions) {
+	layout := newRowLayout(opts, c)
+
+	// Time
+	timeStr := fmt.Sprintf("%-*s", opts.timeWidth(), "??:??")
+	if t := opts.pickTime(dep.SchedDep, dep.Dep); t != nil {
+		timeStr = opts.formatTime(*t)
+	}
+
+	// Delay (fixed width: 4 chars numeric, 2 compact)
+	delayStr := opts.formatDelay(c, dep)
+
+	// Line/Train (category + number, padded to 10 chars)
+	lineStr := formatLineLabel(c, dep, 10)
+
+	// Platform ("Pl.XXX" padded to the layout's platform width)
+	platform := dep.EffectivePlatform()
+	platformStr := ""
+	if platform != "" {
+		if len(platform) > 3 {
+			platform = platform[:3]
 		}
-		lineStr := fmt.Sprintf("%-10s", line)
+		platformStr = fmt.Sprintf("Pl.%-3s", platform)
+	}
+	platformStr = fmt.Sprintf("%-*s", layout.platformWidth, platformStr)
+	platformStyle := c.Platform
+	if dep.PlatformChanged() && !dep.IsCancelled {
+		platformStyle = c.PlatformChange
+	}
 
-		// Platform (fixed 7-char width: "Pl.XXX" or spaces)
-		platform := dep.EffectivePlatform()
-		platformStr := "       " // 7 spaces
-		if platform != "" {
-			if len(platform) > 3 {
-				platform = platform[:3]
+	// Destination, or origin on arrival boards
+	dest := dep.Destination
+	if opts.Arrivals {
+		dest = "from " + dep.ArrivalOrigin()
+	}
+	if dep.IsCancelled {
+		dest = c.Canceled("%s [CANCELED]", dest)
+	} else if dep.OnDemand {
+		dest += " " + c.Badge(OnDemandBadge)
+	}
+	if dep.PlatformChanged() && !dep.IsCancelled {
+		dest += " " + c.PlatformChange("%s", platformChangeNote(dep.Platform))
+	}
+	if badge := EndpointBadge(dep); badge != "" {
+		dest += " " + c.Muted(badge)
+	}
+	if dep.CallsAtHome && !dep.IsCancelled {
+		dest += " " + c.Badge(HomeBadge)
+	}
+	if occ := OccupancyIndicator(c, dep.OccupancyFirst, dep.OccupancySecond, opts.NoEmoji); occ != "" && !dep.IsCancelled {
+		dest += " " + occ
+	}
+	if dep.Accessible == models.AccessibilityYes && !dep.IsCancelled {
+		dest += " " + c.Badge(AccessibleGlyph(opts.NoEmoji))
+	}
+
+	// Format the line: TIME DELAY LINE     PLATFORM DEST
+	var row strings.Builder
+	row.WriteString(c.Time(timeStr) + layout.gaps[0])
+	if !layout.hideDelay {
+		row.WriteString(delayStr + layout.gaps[1])
+	}
+	row.WriteString(lineStr + layout.gaps[2])
+	if !layout.hidePlatform {
+		row.WriteString(platformStyle(platformStr) + layout.gaps[3])
+	}
+	row.WriteString(dest)
+	_, _ = fmt.Fprintln(w, row.String())
+
+	// Show via stations if requested
+	if opts.ShowVia && len(dep.Via) > 0 && !layout.hideVia {
+		viaStr := strings.Join(dep.Via, " - ")
+		_, _ = fmt.Fprintf(w, "%s%s\n", layout.indent, c.Via("via %s", viaStr))
+	}
+
+	// Show disruption messages if requested
+	if opts.ShowMessages {
+		for _, msg := range dep.Messages {
+			if msg.Text == "" {
+				continue
 			}
-			platformStr = fmt.Sprintf("Pl.%-3s ", platform)
+			_, _ = fmt.Fprintf(w, "%s%s\n", layout.indent, c.Via("! %s", msg.Text))
 		}
+	}
 
-		// Destination
-		dest := dep.Destination
-		if dep.IsCancelled {
-			dest = c.Canceled("%s [CANCELED]", dest)
-		}
+	// Show journey ID if requested
+	if opts.ShowRoute && dep.JourneyID != "" {
+		_, _ = fmt.Fprintf(w, "%s%s %s\n",
+			layout.indent,
+			c.Muted("Journey:"),
+			c.Via(dep.JourneyID))
+	}
+}
 
-		// Format the line: TIME DELAY LINE     PLATFORM DEST
-		_, _ = fmt.Fprintf(w, "%s %s  %s  %s %s\n",
-			c.Time(timeStr),
-			delayStr,
-			c.Line(lineStr),
-			c.Platform(platformStr),
-			dest,
-		)
+// Occupancy glyphs per level, from low to very high
+var (
+	occupancyGlyphs      = [...]string{"▂", "▄", "▆", "█"}
+	occupancyASCIIGlyphs = [...]string{"L", "M", "H", "!"}
+)
+
+// OccupancyGlyph returns the bar for an occupancy level, taller the fuller
+// the train, or "" when there is no forecast
+func OccupancyGlyph(level models.Occupancy, noEmoji bool) string {
+	if level < models.OccupancyLow || level > models.OccupancyVeryHigh {
+		return ""
+	}
+	if noEmoji {
+		return occupancyASCIIGlyphs[level-models.OccupancyLow]
+	}
+	return occupancyGlyphs[level-models.OccupancyLow]
+}
 
-		// Show via stations if requested
-		if opts.ShowVia && len(dep.Via) > 0 {
-			viaStr := strings.Join(dep.Via, " - ")
-			_, _ = fmt.Fprintf(w, "                              %s\n", c.Via("via %s", viaStr))
+// OccupancyIndicator renders the first and second class occupancy forecast
+// as "1▂ 2▆", colored green to red by level. Classes without a forecast are
+// left out; "" when neither has one.
+func OccupancyIndicator(c *Colors, first, second models.Occupancy, noEmoji bool) string {
+	var parts []string
+	for _, class := range []struct {
+		label string
+		level models.Occupancy
+	}{{"1", first}, {"2", second}} {
+		glyph := OccupancyGlyph(class.level, noEmoji)
+		if glyph == "" {
+			continue
+		}
+		switch class.level {
+		case models.OccupancyLow:
+			glyph = c.OnTime(glyph)
+		case models.OccupancyMedium:
+			glyph = c.Delay(glyph)
+		default:
+			glyph = c.DelayHigh(glyph)
 		}
+		parts = append(parts, c.Muted(class.label)+glyph)
+	}
+	return strings.Join(parts, " ")
+}
+
+// AccessibleGlyph returns the wheelchair symbol marking trains with
+// step-free boarding
+func AccessibleGlyph(noEmoji bool) string {
+	if noEmoji {
+		return "[wc]"
+	}
+	return "♿"
+}
+
+// EndpointBadge returns the badge explaining a board row of a train that
+// starts or ends at the station, or "" for through services
+func EndpointBadge(dep models.Departure) string {
+	switch {
+	case dep.StartsHere:
+		return StartsHereBadge
+	case dep.EndsHere:
+		return EndsHereBadge
+	}
+	return ""
+}
+
+// formatLineLabel renders the product category and line number of a departure
+// as separately styled parts, truncated and padded to width characters.
+func formatLineLabel(c *Colors, dep models.Departure, width int) string {
+	category, number, padding := LineLabel(dep, width)
+	styled := c.Line("%s", number)
+	if category != "" {
+		styled = c.Category("%s", category) + " " + styled
+	}
+	return styled + padding
+}
+
+// LineLabel splits the line of a departure into the product category and
+// number shown in the line column, truncated together to width characters,
+// and returns the padding that fills the rest of the column. The category
+// is "" when the line has none; a lone category is shown as the number.
+func LineLabel(dep models.Departure, width int) (category, number, padding string) {
+	category, number = dep.LineParts()
+	if category != "" && number == "" {
+		number, category = category, ""
+	}
 
-		// Show journey ID if requested
-		if opts.ShowRoute && dep.JourneyID != "" {
-			_, _ = fmt.Fprintf(w, "                              %s %s\n",
-				c.Muted("Journey:"),
-				c.Via(dep.JourneyID))
+	plain := number
+	if category != "" {
+		plain = category + " " + number
+	}
+	if len(plain) > width {
+		plain = plain[:width]
+		if len(category) >= width {
+			category = plain
+			number = ""
+		} else if category != "" {
+			number = plain[len(category)+1:]
+		} else {
+			number = plain
 		}
 	}
+	return category, number, strings.Repeat(" ", width-len(plain))
 }
 
 // RenderLocations renders locations as a formatted list
@@ -100,12 +520,15 @@ func RenderLocations(w io.Writer, locations []models.Location, opts TableOptions
 		c = NewColors(ColorNever)
 	}
 
-	_, _ = fmt.Fprintln(w, c.Header("Found stations:"))
-	_, _ = fmt.Fprintln(w)
+	opts.header(w, c.Header("Found stations:"))
+	opts.blankLine(w)
 
 	for _, loc := range locations {
 		_, _ = fmt.Fprintf(w, "  %s\n", c.Line(loc.Name))
 		_, _ = fmt.Fprintf(w, "    %s %d\n", c.Muted("EVA:"), loc.EVA)
+		if loc.Distance > 0 {
+			_, _ = fmt.Fprintf(w, "    %s %s\n", c.Muted("Distance:"), FormatDistance(loc.Distance))
+		}
 		if loc.EVA != 0 {
 			_, _ = fmt.Fprintf(w, "    %s moko departures %d:%s\n",
 				c.Muted("Use:"),
@@ -113,7 +536,55 @@ func RenderLocations(w io.Writer, locations []models.Location, opts TableOptions
 				loc.ID,
 			)
 		}
-		_, _ = fmt.Fprintln(w)
+		opts.blankLine(w)
+	}
+}
+
+// FormatDistance formats a distance in meters: whole meters below 1 km,
+// otherwise kilometers with one decimal ("350 m", "1.2 km")
+func FormatDistance(meters float64) string {
+	if meters < 999.5 {
+		return fmt.Sprintf("%.0f m", meters)
+	}
+	return fmt.Sprintf("%.1f km", meters/1000)
+}
+
+// RenderStation renders the details of a single station: its IDs,
+// coordinates in the LAT:LON form nearby takes, and the products it serves
+func RenderStation(w io.Writer, loc *models.Location, opts TableOptions) {
+	if loc == nil {
+		_, _ = fmt.Fprintln(w, "No station found.")
+		return
+	}
+
+	c := opts.Colors
+	if c == nil {
+		c = NewColors(ColorNever)
+	}
+
+	opts.header(w, c.Header("Station:")+" "+c.Line(loc.Name))
+	opts.blankLine(w)
+
+	field := func(label, value string) {
+		_, _ = fmt.Fprintf(w, "  %s %s\n", c.Muted("%-12s", label), value)
+	}
+	field("EVA:", strconv.FormatInt(loc.EVA, 10))
+	if loc.ID != "" {
+		field("ID:", loc.ID)
+	}
+	if loc.Lat != 0 || loc.Lon != 0 {
+		field("Coordinates:", fmt.Sprintf("%.6f:%.6f", loc.Lat, loc.Lon))
+	}
+	if loc.Type != "" {
+		field("Type:", loc.Type)
+	}
+	products := "unknown"
+	if len(loc.Products) > 0 {
+		products = strings.Join(loc.Products, ", ")
+	}
+	field("Products:", products)
+	if loc.EVA != 0 && loc.ID != "" {
+		field("Use:", fmt.Sprintf("moko departures %d:%s", loc.EVA, loc.ID))
 	}
 }
 
@@ -166,6 +637,115 @@ func FindCurrentStopIndex(stops []models.Stop, now time.Time) int {
 	return 0
 }
 
+// JourneySummary returns a one-line glance at a journey: the current stop, the
+// next stop and the ETA there including delay. Before departure the next stop
+// is the origin; once the terminus is reached there is no next stop.
+func JourneySummary(journey *models.Journey, now time.Time) string {
+	if journey == nil || len(journey.Stops) == 0 {
+		return ""
+	}
+	stops := journey.Stops
+	first, last := stops[0], stops[len(stops)-1]
+
+	if journey.IsCancelled {
+		return "Cancelled"
+	}
+	if first.Dep != nil && now.Before(*first.Dep) {
+		return "Not yet departed · Next: " + summaryETA(first.Name, first.Dep, first.Delay, now)
+	}
+
+	idx := FindCurrentStopIndex(stops, now)
+	next := -1
+	for i := idx + 1; i < len(stops); i++ {
+		if !stops[i].IsCancelled {
+			next = i
+			break
+		}
+	}
+	if next < 0 {
+		if last.Arr != nil {
+			return fmt.Sprintf("Arrived at %s %s%s", last.Name, last.Arr.Format("15:04"), summaryDelay(last.Delay))
+		}
+		return "Arrived at " + last.Name
+	}
+
+	stop := stops[next]
+	eta := stop.Arr
+	if eta == nil {
+		eta = stop.Dep
+	}
+	return fmt.Sprintf("Now: %s · Next: %s", stops[idx].Name, summaryETA(stop.Name, eta, stop.Delay, now))
+}
+
+// summaryETA formats "Name 14:35 (+3), in 4 min" for the journey summary
+func summaryETA(name string, eta *time.Time, delay int, now time.Time) string {
+	if eta == nil {
+		return name
+	}
+	s := fmt.Sprintf("%s %s%s", name, eta.Format("15:04"), summaryDelay(delay))
+	if mins := int(math.Ceil(eta.Sub(now).Minutes())); mins > 0 {
+		return fmt.Sprintf("%s, in %d min", s, mins)
+	}
+	return s + ", due"
+}
+
+// summaryDelay formats a delay as " (+3)", or "" when on time
+func summaryDelay(delay int) string {
+	if delay == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%+d)", delay)
+}
+
+// legHeader labels a journey leg, e.g. "RE 10523 · DB Regio NRW"
+func legHeader(leg models.Leg) string {
+	parts := make([]string, 0, 2)
+	for _, p := range []string{leg.Train, leg.Operator} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return "Leg:"
+	}
+	return "Leg: " + strings.Join(parts, " · ")
+}
+
+// AdditionalStopNote marks unscheduled stops a train makes, e.g. during disruptions
+const AdditionalStopNote = "(extra stop)"
+
+// EstimatedDelayNote marks delays carried forward by EstimateDelays
+const EstimatedDelayNote = "(est.)"
+
+// EstimateDelays carries the last known delay forward to later stops without
+// real-time data of their own, which often happens towards the end of long
+// journeys. The result holds one estimate per stop: 0 where the stop has
+// real-time data, is cancelled, or no delay is known before it. Only delays
+// are carried forward; a train running early is expected to wait.
+func EstimateDelays(stops []models.Stop) []int {
+	estimates := make([]int, len(stops))
+	known := 0
+	for i, stop := range stops {
+		if stop.RTArr != nil || stop.RTDep != nil {
+			known = max(stop.Delay, 0)
+			continue
+		}
+		if !stop.IsCancelled {
+			estimates[i] = known
+		}
+	}
+	return estimates
+}
+
+// FormatEstimatedDelay formats an estimated delay like FormatDelay, marked
+// with "~" and muted so it can't be mistaken for real-time data
+func (c *Colors) FormatEstimatedDelay(delay int) string {
+	if delay == 0 {
+		return "    "
+	}
+	return c.Muted("%4s", fmt.Sprintf("~%+d", delay))
+}
+
 // RenderJourney renders a journey with all stops
 func RenderJourney(w io.Writer, journey *models.Journey, opts TableOptions) {
 	if journey == nil {
@@ -178,55 +758,110 @@ func RenderJourney(w io.Writer, journey *models.Journey, opts TableOptions) {
 		c = NewColors(ColorNever)
 	}
 
-	// Header
-	_, _ = fmt.Fprintf(w, "%s %s\n",
-		c.Header("Journey:"),
-		c.Line(journey.Name),
-	)
+	now := opts.now()
+	renderJourneyHeader(w, c, journey, opts, now)
+	renderJourneyStops(w, c, journey, opts, now)
+}
+
+// renderJourneyHeader writes the lines of RenderJourney above the stops
+func renderJourneyHeader(w io.Writer, c *Colors, journey *models.Journey, opts TableOptions, now time.Time) {
+
+	opts.header(w, c.Header("Journey:")+" "+c.Line(journey.Name))
 
 	if journey.Operator != "" {
-		_, _ = fmt.Fprintf(w, "%s %s\n", c.Muted("Operator:"), journey.Operator)
+		opts.header(w, c.Muted("Operator:")+" "+journey.Operator)
+	}
+
+	if opts.Summary {
+		if summary := JourneySummary(journey, now); summary != "" {
+			_, _ = fmt.Fprintln(w, summary)
+		}
 	}
 
-	_, _ = fmt.Fprintln(w)
-	_, _ = fmt.Fprintln(w, c.Header("Route:"))
-	_, _ = fmt.Fprintln(w)
+	opts.blankLine(w)
+	opts.header(w, c.Header("Route:"))
+	opts.blankLine(w)
+}
 
-	// Find current position
-	now := time.Now()
+// renderJourneyStops writes the stop rows of RenderJourney, with the leg
+// sub-headers and notes on hidden stops between them
+func renderJourneyStops(w io.Writer, c *Colors, journey *models.Journey, opts TableOptions, now time.Time) {
 	currentIdx := FindCurrentStopIndex(journey.Stops, now)
 
+	estimates := make([]int, len(journey.Stops))
+	if opts.EstimateDelays {
+		estimates = EstimateDelays(journey.Stops)
+	}
+
+	// Leg sub-headers, only when the journey changes trains
+	var legs []models.Leg
+	if opts.GroupLegs {
+		if l := journey.Legs(); len(l) > 1 {
+			legs = l
+		}
+	}
+
+	// Cancelled stops not yet summarized with --hide-cancelled-stops
+	hidden := 0
+	flushHidden := func() {
+		if hidden == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(w, "  %s %s\n", c.Muted("┆"), c.Muted(hiddenStopsNote(hidden)))
+		hidden = 0
+	}
+
 	// Stops
 	for i, stop := range journey.Stops {
+		if len(legs) > 0 && i == legs[0].From {
+			flushHidden()
+			if i > 0 {
+				opts.blankLine(w)
+			}
+			opts.header(w, c.Header(legHeader(legs[0])))
+			legs = legs[1:]
+		}
+
+		if opts.HideCancelledStops && stop.IsCancelled {
+			hidden++
+			continue
+		}
+		flushHidden()
+
 		// Determine if this is first, last, or intermediate stop
 		isFirst := i == 0
 		isLast := i == len(journey.Stops)-1
 		isCurrent := i == currentIdx
 
 		// Arrival time
-		arrStr := "     "
-		if stop.Arr != nil && !isFirst {
-			arrStr = stop.Arr.Format("15:04")
+		arrStr := strings.Repeat(" ", opts.timeWidth())
+		if arr := opts.pickTime(stop.SchedArr, stop.Arr); arr != nil && !isFirst {
+			arrStr = opts.formatTime(*arr)
 		}
 
 		// Departure time
-		depStr := "     "
-		if stop.Dep != nil && !isLast {
-			depStr = stop.Dep.Format("15:04")
+		depStr := strings.Repeat(" ", opts.timeWidth())
+		if dep := opts.pickTime(stop.SchedDep, stop.Dep); dep != nil && !isLast {
+			depStr = opts.formatTime(*dep)
 		}
 
 		// Delay
 		delayStr := "    "
-		if stop.Delay != 0 {
-			delayStr = c.FormatDelay(stop.Delay)
+		if delay := opts.shownDelay(stop.Delay); delay != 0 {
+			delayStr = c.FormatDelay(delay)
+		} else if estimate := opts.shownDelay(estimates[i]); estimate != 0 {
+			delayStr = c.FormatEstimatedDelay(estimate)
 		}
 
-		// Platform
-		platform := stop.Platform
+		// Platform, in the attention color when it changed
+		platform := stop.EffectivePlatform()
+		changed := stop.PlatformChanged() && !stop.IsCancelled
 		platformStr := "        "
 		if platform != "" {
 			platformStr = fmt.Sprintf("Pl.%-4s", platform)
-			if !isCurrent {
+			if changed && !isCurrent {
+				platformStr = c.PlatformChange("%s", platformStr)
+			} else if !isCurrent {
 				platformStr = c.Platform("%s", platformStr)
 			}
 		}
@@ -235,6 +870,18 @@ func RenderJourney(w io.Writer, journey *models.Journey, opts TableOptions) {
 		name := stop.Name
 		if stop.IsCancelled {
 			name = c.Canceled("%s [CANCELED]", name)
+		} else if stop.IsAdditional {
+			name += " " + AdditionalStopNote
+		}
+		if stop.Delay == 0 && opts.shownDelay(estimates[i]) != 0 {
+			name += " " + c.Muted(EstimatedDelayNote)
+		}
+		if changed {
+			note := platformChangeNote(stop.Platform)
+			if !isCurrent {
+				note = c.PlatformChange("%s", note)
+			}
+			name += " " + note
 		}
 
 		// Connection symbol
@@ -274,4 +921,19 @@ func RenderJourney(w io.Writer, journey *models.Journey, opts TableOptions) {
 			)
 		}
 	}
+	flushHidden()
+}
+
+// platformChangeNote names the scheduled platform of a train that moved to
+// another one: "(was Pl.7)"
+func platformChangeNote(scheduled string) string {
+	return "(was Pl." + scheduled + ")"
+}
+
+// hiddenStopsNote describes a run of n cancelled stops left out of a journey
+func hiddenStopsNote(n int) string {
+	if n == 1 {
+		return "1 cancelled stop hidden"
+	}
+	return fmt.Sprintf("%d cancelled stops hidden", n)
 }
@@ -86,6 +86,74 @@ func TestRenderDepartures_Canceled(t *testing.T) {
 	testutil.AssertContains(t, output, "München Hbf")
 }
 
+func TestRenderDepartures_OnDemand(t *testing.T) {
+	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	deps := []models.Departure{
+		{Dep: &depTime, Type: "Bus", Line: "Bus 271", Destination: "Much", OnDemand: true},
+		{Dep: &depTime, Type: "Bus", Line: "Bus 260", Destination: "Hennef"},
+	}
+
+	var buf bytes.Buffer
+	RenderDepartures(&buf, deps, TableOptions{Colors: NewColors(ColorNever)})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.AssertLen(t, lines, 2)
+	testutil.AssertContains(t, lines[0], "Much "+OnDemandBadge)
+	testutil.AssertFalse(t, strings.Contains(lines[1], OnDemandBadge))
+}
+
+func TestRenderDepartures_EndpointBadges(t *testing.T) {
+	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	deps := []models.Departure{
+		{Dep: &depTime, Type: "RE", Line: "RE 1", Destination: "Aachen Hbf", StartsHere: true},
+		{Dep: &depTime, Type: "RE", Line: "RE 5", Destination: "Köln Hbf", EndsHere: true},
+		{Dep: &depTime, Type: "RE", Line: "RE 9", Destination: "Siegen"},
+	}
+
+	var buf bytes.Buffer
+	RenderDepartures(&buf, deps, TableOptions{Colors: NewColors(ColorNever)})
+
+	lines := strings.Split(strings.TrimSpace(stripANSI(buf.String())), "\n")
+	testutil.AssertLen(t, lines, 3)
+	testutil.AssertContains(t, lines[0], "Aachen Hbf "+StartsHereBadge)
+	testutil.AssertContains(t, lines[1], "Köln Hbf "+EndsHereBadge)
+	testutil.AssertFalse(t, strings.Contains(lines[2], "["))
+}
+
+func TestRenderDepartures_HomeBadge(t *testing.T) {
+	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	deps := []models.Departure{
+		{Dep: &depTime, Type: "RE", Line: "RE 5", Destination: "Koblenz Hbf", CallsAtHome: true},
+		{Dep: &depTime, Type: "RE", Line: "RE 9", Destination: "Siegen"},
+		{Dep: &depTime, Type: "RE", Line: "RE 8", Destination: "Mönchengladbach Hbf", CallsAtHome: true, IsCancelled: true},
+	}
+
+	var buf bytes.Buffer
+	RenderDepartures(&buf, deps, TableOptions{Colors: NewColors(ColorNever)})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.AssertLen(t, lines, 3)
+	testutil.AssertContains(t, lines[0], "Koblenz Hbf "+HomeBadge)
+	testutil.AssertFalse(t, strings.Contains(lines[1], HomeBadge))
+	testutil.AssertFalse(t, strings.Contains(lines[2], HomeBadge))
+}
+
+func TestRenderDepartures_Arrivals(t *testing.T) {
+	arrTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	arrs := []models.Departure{
+		{Dep: &arrTime, Type: "RE", Line: "RE 1", Destination: "Aachen Hbf", Origin: "Aachen Hbf"},
+		{Dep: &arrTime, Type: "RE", Line: "RE 5", Destination: "Wesel"},
+	}
+
+	out := stripANSI(RenderDeparturesString(arrs, TableOptions{Arrivals: true}))
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	testutil.AssertLen(t, lines, 2)
+	testutil.AssertContains(t, lines[0], "from Aachen Hbf")
+	testutil.AssertContains(t, lines[1], "from Wesel")
+
+	testutil.AssertEqual(t, RenderDeparturesString(nil, TableOptions{Arrivals: true}), "No arrivals found.\n")
+}
+
 func TestRenderDepartures_WithVia(t *testing.T) {
 	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
 	dep := models.Departure{
@@ -109,6 +177,84 @@ func TestRenderDepartures_WithVia(t *testing.T) {
 	testutil.AssertContains(t, output, "via Mannheim - Stuttgart")
 }
 
+func TestRenderDepartures_Messages(t *testing.T) {
+	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	dep := models.Departure{
+		Dep:         &depTime,
+		Line:        "ICE 123",
+		Platform:    "7",
+		Destination: "München Hbf",
+		Messages: []models.Message{
+			{Type: "QUALITAET", Text: "Reparatur an einem Signal"},
+			{Type: "QUALITAET", Text: "Verspätung eines vorausfahrenden Zuges"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		showMessages bool
+		wantLines    int
+	}{
+		{"hidden by default", false, 1},
+		{"shown with two messages", true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			RenderDepartures(&buf, []models.Departure{dep}, TableOptions{
+				Colors:       NewColors(ColorNever),
+				ShowMessages: tt.showMessages,
+			})
+
+			lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+			testutil.AssertLen(t, lines, tt.wantLines)
+			if tt.showMessages {
+				testutil.AssertContains(t, lines[1], "! Reparatur an einem Signal")
+				testutil.AssertContains(t, lines[2], "! Verspätung eines vorausfahrenden Zuges")
+				testutil.AssertTrue(t, strings.HasPrefix(lines[1], " "))
+			}
+		})
+	}
+}
+
+func TestRenderDepartures_DayMarker(t *testing.T) {
+	at := func(day, hour, min int) *time.Time {
+		ts := time.Date(2025, 4, day, hour, min, 0, 0, time.UTC)
+		return &ts
+	}
+
+	tests := []struct {
+		name       string
+		times      []*time.Time
+		wantMarker bool
+	}{
+		{"same day", []*time.Time{at(11, 22, 50), at(11, 23, 40)}, false},
+		{"crosses midnight", []*time.Time{at(11, 23, 40), at(12, 0, 15), at(12, 5, 0)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deps []models.Departure
+			for _, ts := range tt.times {
+				deps = append(deps, models.Departure{Dep: ts, Line: "S 11", Destination: "Düsseldorf Flughafen"})
+			}
+
+			out := RenderDeparturesString(deps, TableOptions{Colors: NewColors(ColorNever)})
+			testutil.AssertEqual(t, strings.Contains(out, "— Sat 12 Apr —"), tt.wantMarker)
+			if tt.wantMarker {
+				lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+				testutil.AssertLen(t, lines, 4)
+				testutil.AssertEqual(t, lines[1], "— Sat 12 Apr —")
+				testutil.AssertTrue(t, strings.HasPrefix(lines[2], "00:15"))
+			}
+		})
+	}
+
+	// ASCII glyphs, and no marker on bare output
+	deps := []models.Departure{{Dep: at(11, 23, 40)}, {Dep: at(12, 0, 15)}}
+	testutil.AssertContains(t, RenderDeparturesString(deps, TableOptions{NoEmoji: true}), "-- Sat 12 Apr --")
+	testutil.AssertFalse(t, strings.Contains(RenderDeparturesString(deps, TableOptions{NoDecoration: true}), "Apr"))
+}
+
 func TestRenderDepartures_WithRoute(t *testing.T) {
 	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
 	dep := models.Departure{
@@ -171,6 +317,71 @@ func TestRenderDepartures_PlatformChange(t *testing.T) {
 	testutil.AssertContains(t, output, "Pl.8")
 }
 
+func TestRenderPlatformChangeNote(t *testing.T) {
+	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		platform   string
+		rtPlatform string
+		cancelled  bool
+		want       string // "" when no note is expected
+	}{
+		{"changed", "7", "8", false, "(was Pl.7)"},
+		{"confirmed", "7", "7", false, ""},
+		{"no real-time platform", "7", "", false, ""},
+		{"no scheduled platform", "", "8", false, ""},
+		{"cancelled", "7", "8", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dep := models.Departure{
+				Dep:         &depTime,
+				Line:        "ICE 123",
+				Platform:    tt.platform,
+				RTPlatform:  tt.rtPlatform,
+				Destination: "München Hbf",
+				IsCancelled: tt.cancelled,
+			}
+			journey := &models.Journey{
+				Name: "ICE 123",
+				Stops: []models.Stop{
+					{Name: "Frankfurt Hbf", Dep: &depTime, Platform: tt.platform, RTPlatform: tt.rtPlatform, IsCancelled: tt.cancelled},
+					{Name: "München Hbf", Arr: &depTime},
+				},
+			}
+			opts := TableOptions{Colors: NewColors(ColorNever)}
+
+			var board, route bytes.Buffer
+			RenderDepartures(&board, []models.Departure{dep}, opts)
+			RenderJourney(&route, journey, opts)
+			for _, out := range []string{board.String(), route.String()} {
+				if tt.want == "" {
+					testutil.AssertNotContains(t, out, "(was")
+				} else {
+					testutil.AssertContains(t, out, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderPlatformChange_Color(t *testing.T) {
+	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	c := NewColors(ColorAlways)
+	changed := c.PlatformChange("%s", "Pl.8   ")
+
+	var buf bytes.Buffer
+	deps := []models.Departure{{Dep: &depTime, Line: "ICE 123", Platform: "7", RTPlatform: "8", Destination: "München Hbf"}}
+	RenderDepartures(&buf, deps, TableOptions{Colors: c})
+	testutil.AssertContains(t, buf.String(), changed)
+
+	buf.Reset()
+	deps[0].RTPlatform = "7"
+	RenderDepartures(&buf, deps, TableOptions{Colors: c})
+	testutil.AssertNotContains(t, buf.String(), c.PlatformChange("%s", "Pl.7   "))
+}
+
 func TestRenderDepartures_LongLineName(t *testing.T) {
 	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
 	dep := models.Departure{
@@ -225,6 +436,249 @@ func TestRenderDepartures_MultipleDepartures(t *testing.T) {
 	testutil.AssertTrue(t, len(lines) >= 2)
 }
 
+func TestRenderDepartures_GroupByMode(t *testing.T) {
+	t1 := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	t2 := t1.Add(5 * time.Minute)
+	t3 := t1.Add(10 * time.Minute)
+
+	departures := []models.Departure{
+		{Dep: &t1, Type: "Bus", Line: "Bus 136", Destination: "Chorweiler"},
+		{Dep: &t2, Type: "S", Line: "S 11", Destination: "Bergisch Gladbach"},
+		{Dep: &t3, Type: "STR", Line: "STR 5", Destination: "Sparkasse Am Butzweilerhof"},
+	}
+
+	tests := []struct {
+		name    string
+		groupBy string
+		bare    bool
+		want    []string
+	}{
+		{"flat", "", false, []string{"Chorweiler", "Bergisch Gladbach", "Sparkasse Am Butzweilerhof"}},
+		{"by mode", GroupByMode, false, []string{"Rail", "Bergisch Gladbach", "", "Local transit", "Chorweiler", "Sparkasse Am Butzweilerhof"}},
+		{"by mode bare", GroupByMode, true, []string{"Bergisch Gladbach", "Chorweiler", "Sparkasse Am Butzweilerhof"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			RenderDepartures(&buf, departures, TableOptions{Colors: NewColors(ColorNever), GroupBy: tt.groupBy, NoDecoration: tt.bare})
+
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			testutil.AssertLen(t, lines, len(tt.want))
+			for i, want := range tt.want {
+				testutil.AssertContains(t, lines[i], want)
+			}
+		})
+	}
+}
+
+func TestRenderDepartures_Layout(t *testing.T) {
+	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	deps := []models.Departure{
+		{Dep: &depTime, Type: "ICE", Line: "ICE 123", Platform: "7", Destination: "München Hbf", Delay: 2},
+		{Dep: &depTime, Type: "Bus", Line: "Bus 1", Destination: "Köln-Porz", Delay: 12},
+		{Dep: &depTime, Type: "STR", Line: "STR 18", Platform: "10a", Destination: "Thielenbruch"},
+	}
+
+	render := func(opts TableOptions) []string {
+		var buf bytes.Buffer
+		opts.Colors = NewColors(ColorNever)
+		RenderDepartures(&buf, deps, opts)
+		return strings.Split(strings.TrimSuffix(stripANSI(buf.String()), "\n"), "\n")
+	}
+
+	// Default spacing is unchanged
+	lines := render(TableOptions{})
+	testutil.AssertEqual(t, lines[0], "14:30   +2  ICE 123     Pl.7    München Hbf")
+
+	// Compact rows use single spaces
+	lines = render(TableOptions{Compact: true})
+	testutil.AssertEqual(t, lines[0], "14:30   +2 ICE 123    Pl.7   München Hbf")
+
+	// Separators line up in every row, measured in display columns
+	for _, opts := range []TableOptions{{Separators: true}, {Separators: true, Compact: true}} {
+		lines = render(opts)
+		var want []int
+		for i, line := range lines {
+			var cols []int
+			col := 0
+			for _, r := range line {
+				if r == '│' {
+					cols = append(cols, col)
+				}
+				col++
+			}
+			testutil.AssertLen(t, cols, 4)
+			if i == 0 {
+				want = cols
+				continue
+			}
+			for j := range cols {
+				testutil.AssertEqual(t, cols[j], want[j])
+			}
+		}
+	}
+}
+
+func TestRenderDepartures_Width(t *testing.T) {
+	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	deps := []models.Departure{
+		{Dep: &depTime, Type: "ICE", Line: "ICE 123", Platform: "7", Destination: "München Hbf", Delay: 2,
+			Via: []string{"Frankfurt Hbf"}},
+	}
+
+	render := func(width int) []string {
+		var buf bytes.Buffer
+		RenderDepartures(&buf, deps, TableOptions{Colors: NewColors(ColorNever), ShowVia: true, Width: width})
+		return strings.Split(strings.TrimSuffix(stripANSI(buf.String()), "\n"), "\n")
+	}
+
+	// Unknown or wide enough: everything is shown
+	for _, width := range []int{0, 80} {
+		lines := render(width)
+		testutil.AssertEqual(t, lines[0], "14:30   +2  ICE 123     Pl.7    München Hbf")
+		testutil.AssertLen(t, lines, 2)
+	}
+
+	// Platform goes first, then the delay, then the via line
+	testutil.AssertEqual(t, render(50)[0], "14:30   +2  ICE 123     München Hbf")
+	lines := render(40)
+	testutil.AssertEqual(t, lines[0], "14:30 ICE 123     München Hbf")
+	testutil.AssertLen(t, lines, 2)
+	testutil.AssertEqual(t, strings.TrimSpace(lines[1]), "via Frankfurt Hbf")
+	testutil.AssertLen(t, render(30), 1)
+}
+
+func TestRenderDepartures_PreferScheduled(t *testing.T) {
+	sched := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	rt := sched.Add(7 * time.Minute)
+	deps := []models.Departure{
+		{SchedDep: &sched, RTDep: &rt, Dep: &rt, Delay: 7, Type: "RE", Line: "RE 5", Destination: "Koblenz Hbf"},
+		{Dep: &sched, Type: "RB", Line: "RB 25", Destination: "Lüdenscheid"}, // no separate scheduled time
+	}
+
+	render := func(opts TableOptions) string {
+		var buf bytes.Buffer
+		opts.Colors = NewColors(ColorNever)
+		RenderDepartures(&buf, deps, opts)
+		return stripANSI(buf.String())
+	}
+
+	live := render(TableOptions{})
+	testutil.AssertContains(t, live, "14:37   +7")
+
+	timetable := render(TableOptions{PreferScheduled: true})
+	testutil.AssertContains(t, timetable, "14:30       RE 5")
+	testutil.AssertFalse(t, strings.Contains(timetable, "+7"))
+	testutil.AssertFalse(t, strings.Contains(timetable, "14:37"))
+	testutil.AssertContains(t, timetable, "14:30       RB 25")
+}
+
+func TestRenderDepartures_DualZone(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	london := time.FixedZone("GMT", 0)
+	dep := time.Date(2024, 1, 1, 15, 30, 0, 0, berlin)
+	deps := []models.Departure{
+		{Dep: &dep, Type: "ICE", Line: "ICE 10", Destination: "London St Pancras", Via: []string{"Brussels"}},
+	}
+
+	render := func(zone *time.Location) string {
+		var buf bytes.Buffer
+		RenderDepartures(&buf, deps, TableOptions{Colors: NewColors(ColorNever), ShowVia: true, DualZone: zone})
+		return stripANSI(buf.String())
+	}
+
+	single := render(nil)
+	dual := render(london)
+	testutil.AssertContains(t, dual, "15:30 / 14:30")
+	// Continuation lines shift along with the destination column
+	indent := func(out string) int {
+		lines := strings.Split(out, "\n")
+		return strings.Index(lines[0], "London") - strings.Index(lines[1], "via")
+	}
+	testutil.AssertEqual(t, indent(dual), indent(single))
+
+	// Same wall clock in both zones collapses to one time, padded to the column
+	same := render(berlin)
+	testutil.AssertFalse(t, strings.Contains(same, " / "))
+	testutil.AssertContains(t, same, "15:30        ")
+	testutil.AssertEqual(t, strings.Index(same, "ICE 10"), strings.Index(dual, "ICE 10"))
+}
+
+func TestRenderJourney_DualZone(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	dep := time.Date(2024, 1, 1, 8, 0, 0, 0, berlin)
+	arr := time.Date(2024, 1, 1, 12, 5, 0, 0, berlin)
+	journey := &models.Journey{
+		Name: "ICE 10",
+		Stops: []models.Stop{
+			{Name: "Köln Hbf", Dep: &dep},
+			{Name: "London St Pancras", Arr: &arr},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderJourney(&buf, journey, TableOptions{Colors: NewColors(ColorNever), DualZone: time.FixedZone("GMT", 0)})
+	out := stripANSI(buf.String())
+
+	testutil.AssertContains(t, out, "07:00")
+	testutil.AssertContains(t, out, "12:05 / 11:05")
+}
+
+func TestEstimateDelays(t *testing.T) {
+	at := func(hour, min int) *time.Time {
+		ts := time.Date(2024, 1, 1, hour, min, 0, 0, time.UTC)
+		return &ts
+	}
+	stops := []models.Stop{
+		{Name: "Köln Hbf", RTDep: at(8, 0)},
+		{Name: "Bonn Hbf", RTArr: at(8, 27), Delay: 7},
+		{Name: "Koblenz Hbf"},
+		{Name: "Bingen", IsCancelled: true},
+		{Name: "Mainz Hbf", RTArr: at(9, 40), Delay: -1},
+		{Name: "Frankfurt Hbf"},
+	}
+
+	got := EstimateDelays(stops)
+	want := []int{0, 0, 7, 0, 0, 0}
+	testutil.AssertLen(t, got, len(want))
+	for i := range want {
+		testutil.AssertEqual(t, got[i], want[i])
+	}
+	testutil.AssertLen(t, EstimateDelays(nil), 0)
+}
+
+func TestRenderJourney_EstimateDelays(t *testing.T) {
+	dep := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	rtArr := time.Date(2024, 1, 1, 8, 27, 0, 0, time.UTC)
+	arr := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	journey := &models.Journey{
+		Name: "RE 5",
+		Stops: []models.Stop{
+			{Name: "Köln Hbf", Dep: &dep},
+			{Name: "Bonn Hbf", Arr: &rtArr, RTArr: &rtArr, Delay: 7},
+			{Name: "Koblenz Hbf", Arr: &arr},
+		},
+	}
+	now := func() time.Time { return dep.Add(-time.Hour) }
+
+	var buf bytes.Buffer
+	RenderJourney(&buf, journey, TableOptions{Colors: NewColors(ColorNever), Now: now})
+	testutil.AssertFalse(t, strings.Contains(buf.String(), EstimatedDelayNote))
+
+	buf.Reset()
+	RenderJourney(&buf, journey, TableOptions{Colors: NewColors(ColorNever), Now: now, EstimateDelays: true})
+	out := stripANSI(buf.String())
+	testutil.AssertContains(t, out, "~+7")
+	testutil.AssertContains(t, out, "Koblenz Hbf "+EstimatedDelayNote)
+	testutil.AssertFalse(t, strings.Contains(out, "Bonn Hbf "+EstimatedDelayNote))
+
+	// Scheduled times hide estimates along with delays
+	buf.Reset()
+	RenderJourney(&buf, journey, TableOptions{Colors: NewColors(ColorNever), Now: now, EstimateDelays: true, PreferScheduled: true})
+	testutil.AssertFalse(t, strings.Contains(buf.String(), EstimatedDelayNote))
+}
+
 func TestRenderLocations_Empty(t *testing.T) {
 	var buf bytes.Buffer
 	opts := TableOptions{Colors: NewColors(ColorNever)}
@@ -283,6 +737,73 @@ func TestRenderLocations_Multiple(t *testing.T) {
 	testutil.AssertContains(t, output, "8002041")
 }
 
+func TestRenderLocations_NoDecoration(t *testing.T) {
+	locations := []models.Location{
+		{Name: "Frankfurt(Main)Hbf", EVA: 8000105, ID: "A=1@O=Frankfurt(Main)Hbf@"},
+		{Name: "Frankfurt(Main) Süd", EVA: 8002041, ID: "A=1@O=Frankfurt(Main) Süd@"},
+	}
+
+	var buf bytes.Buffer
+	RenderLocations(&buf, locations, TableOptions{Colors: NewColors(ColorNever), NoDecoration: true})
+
+	out := buf.String()
+	testutil.AssertFalse(t, strings.Contains(out, "Found stations:"))
+	testutil.AssertFalse(t, strings.Contains(out, "\n\n"))
+	testutil.AssertLen(t, strings.Split(strings.TrimSuffix(out, "\n"), "\n"), 6)
+}
+
+func TestFormatDistance(t *testing.T) {
+	tests := []struct {
+		meters float64
+		want   string
+	}{
+		{0, "0 m"},
+		{349.6, "350 m"},
+		{999.4, "999 m"},
+		{999.6, "1.0 km"},
+		{1234, "1.2 km"},
+		{12500, "12.5 km"},
+	}
+	for _, tt := range tests {
+		testutil.AssertEqual(t, FormatDistance(tt.meters), tt.want)
+	}
+}
+
+func TestRenderLocations_Distance(t *testing.T) {
+	locations := []models.Location{
+		{Name: "Köln Hbf", EVA: 8000207, Distance: 120},
+		{Name: "Köln Messe/Deutz", EVA: 8003368, Distance: 1140},
+		{Name: "Köln-Mülheim", EVA: 8000208},
+	}
+
+	out := RenderLocationsString(locations, TableOptions{Colors: NewColors(ColorNever)})
+	testutil.AssertContains(t, out, "Distance: 120 m")
+	testutil.AssertContains(t, out, "Distance: 1.1 km")
+	testutil.AssertEqual(t, strings.Count(out, "Distance:"), 2)
+}
+
+func TestRenderStation(t *testing.T) {
+	loc := &models.Location{
+		Name:     "Köln Hbf",
+		EVA:      8000207,
+		ID:       "A=1@O=Köln Hbf@L=8000207@",
+		Lat:      50.943029,
+		Lon:      6.958729,
+		Products: []string{"ICE", "REGIONAL", "SBAHN"},
+	}
+
+	out := RenderStationString(loc, TableOptions{Colors: NewColors(ColorNever)})
+	testutil.AssertContains(t, out, "Station: Köln Hbf")
+	testutil.AssertContains(t, out, "8000207")
+	testutil.AssertContains(t, out, "50.943029:6.958729")
+	testutil.AssertContains(t, out, "ICE, REGIONAL, SBAHN")
+	testutil.AssertContains(t, out, "moko departures 8000207:A=1@O=Köln Hbf@L=8000207@")
+
+	loc.Products = nil
+	testutil.AssertContains(t, RenderStationString(loc, TableOptions{}), "unknown")
+	testutil.AssertContains(t, RenderStationString(nil, TableOptions{}), "No station found")
+}
+
 func TestFindCurrentStopIndex_EmptyStops(t *testing.T) {
 	now := time.Now()
 	idx := FindCurrentStopIndex([]models.Stop{}, now)
@@ -395,6 +916,75 @@ func TestRenderJourney_Basic(t *testing.T) {
 	testutil.AssertContains(t, output, "Pl.18")
 }
 
+func TestRenderJourney_NoDecoration(t *testing.T) {
+	dep := time.Date(2024, 1, 1, 14, 32, 0, 0, time.UTC)
+	arr := time.Date(2024, 1, 1, 15, 15, 0, 0, time.UTC)
+	journey := &models.Journey{
+		Name:     "ICE 123",
+		Operator: "DB Fernverkehr AG",
+		Stops: []models.Stop{
+			{Name: "Frankfurt Hbf", Dep: &dep, Train: "ICE 123"},
+			{Name: "München Hbf", Arr: &arr, Train: "ICE 1123"},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderJourney(&buf, journey, TableOptions{Colors: NewColors(ColorNever), GroupLegs: true, NoDecoration: true})
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	testutil.AssertLen(t, lines, 2)
+	testutil.AssertContains(t, lines[0], "Frankfurt Hbf")
+	testutil.AssertContains(t, lines[1], "München Hbf")
+}
+
+func TestJourneySummary(t *testing.T) {
+	at := func(h, m int) *time.Time {
+		t := time.Date(2024, 1, 1, h, m, 0, 0, time.UTC)
+		return &t
+	}
+	journey := &models.Journey{
+		Name: "RE 5",
+		Stops: []models.Stop{
+			{Name: "Köln Hbf", Dep: at(14, 0)},
+			{Name: "Köln Messe/Deutz", SchedArr: at(14, 3), Arr: at(14, 6), Dep: at(14, 7), Delay: 3},
+			{Name: "Leverkusen Mitte", Arr: at(14, 15), IsCancelled: true},
+			{Name: "Düsseldorf Hbf", SchedArr: at(14, 30), Arr: at(14, 33), Delay: 3},
+		},
+	}
+	now := func(h, m int) time.Time { return *at(h, m) }
+
+	testutil.AssertEqual(t, JourneySummary(journey, now(13, 50)),
+		"Not yet departed · Next: Köln Hbf 14:00, in 10 min")
+	testutil.AssertEqual(t, JourneySummary(journey, now(14, 1)),
+		"Now: Köln Hbf · Next: Köln Messe/Deutz 14:06 (+3), in 5 min")
+	// Cancelled stops are skipped
+	testutil.AssertEqual(t, JourneySummary(journey, now(14, 10)),
+		"Now: Köln Messe/Deutz · Next: Düsseldorf Hbf 14:33 (+3), in 23 min")
+	testutil.AssertEqual(t, JourneySummary(journey, now(14, 40)),
+		"Arrived at Düsseldorf Hbf 14:33 (+3)")
+	testutil.AssertEqual(t, JourneySummary(&models.Journey{}, now(14, 0)), "")
+}
+
+func TestRenderJourney_Summary(t *testing.T) {
+	dep := time.Now().Add(10 * time.Minute)
+	arr := dep.Add(30 * time.Minute)
+	journey := &models.Journey{
+		Name:  "RE 5",
+		Stops: []models.Stop{{Name: "Köln Hbf", Dep: &dep}, {Name: "Düsseldorf Hbf", Arr: &arr}},
+	}
+
+	render := func(summary bool) string {
+		var buf bytes.Buffer
+		RenderJourney(&buf, journey, TableOptions{Colors: NewColors(ColorNever), Summary: summary})
+		return stripANSI(buf.String())
+	}
+
+	out := render(true)
+	testutil.AssertContains(t, out, "Not yet departed · Next: Köln Hbf")
+	testutil.AssertTrue(t, strings.Index(out, "Not yet departed") < strings.Index(out, "Route:"))
+	testutil.AssertFalse(t, strings.Contains(render(false), "Not yet departed"))
+}
+
 func TestRenderJourney_CanceledStop(t *testing.T) {
 	arr1 := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
 
@@ -419,3 +1009,218 @@ func TestRenderJourney_CanceledStop(t *testing.T) {
 	testutil.AssertContains(t, output, "CANCELED")
 	testutil.AssertContains(t, output, "Frankfurt Hbf")
 }
+
+func TestRenderJourney_HideCancelledStops(t *testing.T) {
+	journey := &models.Journey{
+		Name: "RE 5",
+		Stops: []models.Stop{
+			{Name: "Köln Hbf"},
+			{Name: "Köln Süd", IsCancelled: true},
+			{Name: "Brühl", IsCancelled: true},
+			{Name: "Bonn Hbf"},
+			{Name: "Remagen", IsCancelled: true},
+			{Name: "Koblenz Hbf"},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderJourney(&buf, journey, TableOptions{Colors: NewColors(ColorNever), HideCancelledStops: true})
+	out := stripANSI(buf.String())
+	testutil.AssertFalse(t, strings.Contains(out, "CANCELED"))
+	testutil.AssertFalse(t, strings.Contains(out, "Brühl"))
+	testutil.AssertContains(t, out, "2 cancelled stops hidden")
+	testutil.AssertContains(t, out, "1 cancelled stop hidden")
+	testutil.AssertTrue(t, strings.Index(out, "2 cancelled") < strings.Index(out, "Bonn Hbf"))
+	testutil.AssertContains(t, out, "Koblenz Hbf")
+
+	buf.Reset()
+	RenderJourney(&buf, journey, TableOptions{Colors: NewColors(ColorNever)})
+	testutil.AssertContains(t, buf.String(), "Brühl [CANCELED]")
+}
+
+func TestRenderJourney_AdditionalStop(t *testing.T) {
+	arr := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	journey := &models.Journey{
+		Name: "RE 5",
+		Stops: []models.Stop{
+			{Name: "Köln Hbf", Dep: &arr},
+			{Name: "Köln-Mülheim", Arr: &arr, Dep: &arr, IsAdditional: true},
+			{Name: "Düsseldorf Hbf", Arr: &arr},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderJourney(&buf, journey, TableOptions{Colors: NewColors(ColorNever)})
+	out := stripANSI(buf.String())
+
+	testutil.AssertContains(t, out, "Köln-Mülheim "+AdditionalStopNote)
+	testutil.AssertEqual(t, strings.Count(out, AdditionalStopNote), 1)
+}
+
+func TestRenderJourney_PreferScheduled(t *testing.T) {
+	schedDep := time.Date(2024, 1, 1, 14, 32, 0, 0, time.UTC)
+	rtDep := schedDep.Add(4 * time.Minute)
+	schedArr := time.Date(2024, 1, 1, 15, 15, 0, 0, time.UTC)
+	rtArr := schedArr.Add(6 * time.Minute)
+
+	journey := &models.Journey{
+		Name: "ICE 123",
+		Stops: []models.Stop{
+			{Name: "Frankfurt Hbf", SchedDep: &schedDep, Dep: &rtDep, Delay: 4},
+			{Name: "München Hbf", SchedArr: &schedArr, Arr: &rtArr, Delay: 6},
+		},
+	}
+
+	var buf bytes.Buffer
+	RenderJourney(&buf, journey, TableOptions{Colors: NewColors(ColorNever), PreferScheduled: true})
+
+	out := stripANSI(buf.String())
+	testutil.AssertContains(t, out, "14:32")
+	testutil.AssertContains(t, out, "15:15")
+	testutil.AssertFalse(t, strings.Contains(out, "14:36"))
+	testutil.AssertFalse(t, strings.Contains(out, "15:21"))
+	testutil.AssertFalse(t, strings.Contains(out, "+4"))
+	testutil.AssertFalse(t, strings.Contains(out, "+6"))
+}
+
+func TestRenderJourney_GroupLegs(t *testing.T) {
+	at := func(h, m int) *time.Time {
+		ts := time.Date(2024, 1, 1, h, m, 0, 0, time.UTC)
+		return &ts
+	}
+	journey := &models.Journey{
+		Name: "RJ 62",
+		Stops: []models.Stop{
+			{Name: "Wien Hbf", Dep: at(6, 30), Train: "RJ 62", Operator: "ÖBB"},
+			{Name: "Salzburg Hbf", Arr: at(9, 2), Dep: at(9, 8), Train: "RJ 62", Operator: "ÖBB"},
+			{Name: "München Hbf", Arr: at(10, 30), Train: "RJ 62", Operator: "DB Fernverkehr AG"},
+		},
+	}
+	render := func(j *models.Journey, group bool) string {
+		return stripANSI(RenderJourneyString(j, TableOptions{Colors: NewColors(ColorNever), GroupLegs: group}))
+	}
+
+	out := render(journey, true)
+	testutil.AssertContains(t, out, "Leg: RJ 62 · ÖBB\n")
+	testutil.AssertContains(t, out, "Salzburg Hbf\n\nLeg: RJ 62 · DB Fernverkehr AG\n")
+	testutil.AssertTrue(t, strings.Index(out, "Leg: RJ 62 · ÖBB") < strings.Index(out, "Wien Hbf"))
+
+	// A single train renders exactly as without the option
+	for i := range journey.Stops {
+		journey.Stops[i].Operator = "ÖBB"
+	}
+	testutil.AssertEqual(t, render(journey, true), render(journey, false))
+}
+
+func TestRenderJourney_Now(t *testing.T) {
+	at := func(h, m int) *time.Time {
+		ts := time.Date(2024, 1, 1, h, m, 0, 0, time.UTC)
+		return &ts
+	}
+	journey := &models.Journey{
+		Name: "S 11",
+		Stops: []models.Stop{
+			{Name: "Köln Hbf", Dep: at(14, 0)},
+			{Name: "Köln-Mülheim", Arr: at(14, 6), Dep: at(14, 7)},
+			{Name: "Bergisch Gladbach", Arr: at(14, 25)},
+		},
+	}
+
+	out := RenderJourneyString(journey, TableOptions{
+		Colors: NewColors(ColorNever),
+		Now:    func() time.Time { return *at(14, 10) },
+	})
+	testutil.AssertContains(t, stripANSI(out), "> ├ 14:06  14:07")
+}
+
+func TestRenderDepartures_CompactDelay(t *testing.T) {
+	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	deps := []models.Departure{
+		{Dep: &depTime, Type: "ICE", Line: "ICE 123", Platform: "7", Destination: "München Hbf", Delay: 12,
+			Via: []string{"Frankfurt Hbf"}},
+		{Dep: &depTime, Type: "RE", Line: "RE 5", Platform: "2", Destination: "Koblenz Hbf"},
+	}
+
+	render := func(opts TableOptions) []string {
+		opts.Colors = NewColors(ColorNever)
+		opts.ShowVia = true
+		return strings.Split(stripANSI(RenderDeparturesString(deps, opts)), "\n")
+	}
+
+	lines := render(TableOptions{DelayStyle: DelayCompact})
+	testutil.AssertEqual(t, lines[0], "14:30 ↑↑  ICE 123     Pl.7    München Hbf")
+	testutil.AssertEqual(t, lines[2], "14:30 ·   RE 5        Pl.2    Koblenz Hbf")
+	// The via line moves left with the narrower delay column
+	numeric := render(TableOptions{})
+	testutil.AssertEqual(t, len(numeric[1])-len(strings.TrimLeft(numeric[1], " "))-2,
+		len(lines[1])-len(strings.TrimLeft(lines[1], " ")))
+
+	ascii := render(TableOptions{DelayStyle: DelayCompact, NoEmoji: true})
+	testutil.AssertEqual(t, ascii[0], "14:30 ++  ICE 123     Pl.7    München Hbf")
+}
+
+func TestOccupancyIndicator(t *testing.T) {
+	c := NewColors(ColorNever)
+
+	testutil.AssertEqual(t, OccupancyIndicator(c, models.OccupancyLow, models.OccupancyHigh, false), "1▂ 2▆")
+	testutil.AssertEqual(t, OccupancyIndicator(c, models.OccupancyUnknown, models.OccupancyVeryHigh, false), "2█")
+	testutil.AssertEqual(t, OccupancyIndicator(c, models.OccupancyMedium, models.OccupancyVeryHigh, true), "1M 2!")
+	testutil.AssertEqual(t, OccupancyIndicator(c, models.OccupancyUnknown, models.OccupancyUnknown, false), "")
+
+	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	deps := []models.Departure{
+		{Dep: &depTime, Type: "ICE", Line: "ICE 623", Destination: "München Hbf", OccupancyFirst: models.OccupancyLow, OccupancySecond: models.OccupancyMedium},
+		{Dep: &depTime, Type: "RE", Line: "RE 5", Destination: "Koblenz Hbf"},
+	}
+	var buf bytes.Buffer
+	RenderDepartures(&buf, deps, TableOptions{Colors: c})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.AssertContains(t, lines[0], "München Hbf 1▂ 2▄")
+	testutil.AssertTrue(t, strings.HasSuffix(lines[1], "Koblenz Hbf"))
+}
+
+func TestLineLabel(t *testing.T) {
+	tests := []struct {
+		name                      string
+		dep                       models.Departure
+		width                     int
+		category, number, padding string
+	}{
+		{"category and number", models.Departure{Type: "S", Line: "S1"}, 6, "S", "1", "   "},
+		{"no category", models.Departure{Line: "Bus 150"}, 8, "", "Bus 150", " "},
+		{"lone category", models.Departure{Type: "ICE", TrainShort: "ICE"}, 4, "", "ICE", " "},
+		{"truncated number", models.Departure{Type: "ICE", Line: "ICE 12345"}, 6, "ICE", "12", ""},
+		{"truncated category", models.Departure{Type: "Bus", Line: "Bus 150"}, 2, "Bu", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, number, padding := LineLabel(tt.dep, tt.width)
+			testutil.AssertEqual(t, category, tt.category)
+			testutil.AssertEqual(t, number, tt.number)
+			testutil.AssertEqual(t, padding, tt.padding)
+		})
+	}
+}
+
+func TestRenderAccessibleGlyph(t *testing.T) {
+	c := NewColors(ColorNever)
+	depTime := time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC)
+	deps := []models.Departure{
+		{Dep: &depTime, Type: "RE", Line: "RE 5", Destination: "Koblenz Hbf", Accessible: models.AccessibilityYes},
+		{Dep: &depTime, Type: "RB", Line: "RB 26", Destination: "Mainz Hbf", Accessible: models.AccessibilityNo},
+		{Dep: &depTime, Type: "RB", Line: "RB 48", Destination: "Wuppertal Hbf"},
+		{Dep: &depTime, Type: "RE", Line: "RE 1", Destination: "Aachen Hbf", Accessible: models.AccessibilityYes, IsCancelled: true},
+	}
+
+	var buf bytes.Buffer
+	RenderDepartures(&buf, deps, TableOptions{Colors: c})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.AssertTrue(t, strings.HasSuffix(lines[0], "Koblenz Hbf ♿"))
+	testutil.AssertTrue(t, strings.HasSuffix(lines[1], "Mainz Hbf"))
+	testutil.AssertTrue(t, strings.HasSuffix(lines[2], "Wuppertal Hbf"))
+	testutil.AssertNotContains(t, lines[3], "♿")
+
+	buf.Reset()
+	RenderDepartures(&buf, deps[:1], TableOptions{Colors: c, NoEmoji: true})
+	testutil.AssertContains(t, buf.String(), "Koblenz Hbf [wc]")
+}
@@ -13,6 +13,12 @@ func ClearScreen(w io.Writer) {
 	_, _ = fmt.Fprint(w, "\033[2J\033[H")
 }
 
+// RewriteFirstLine replaces the first line of the screen with text, leaving
+// the cursor where it was
+func RewriteFirstLine(w io.Writer, text string) {
+	_, _ = fmt.Fprintf(w, "\0337\033[H\033[2K%s\0338", text)
+}
+
 // HideCursor hides the terminal cursor
 func HideCursor(w io.Writer) {
 	_, _ = fmt.Fprint(w, "\033[?25l")
@@ -19,6 +19,14 @@ func TestClearScreen(t *testing.T) {
 	testutil.AssertContains(t, output, "\033[H")
 }
 
+func TestRewriteFirstLine(t *testing.T) {
+	var buf bytes.Buffer
+	RewriteFirstLine(&buf, "Next refresh in 12s")
+
+	// Saves the cursor, clears the top line, writes and restores the cursor
+	testutil.AssertEqual(t, buf.String(), "\0337\033[H\033[2KNext refresh in 12s\0338")
+}
+
 func TestHideCursor(t *testing.T) {
 	var buf bytes.Buffer
 	HideCursor(&buf)
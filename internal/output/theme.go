@@ -0,0 +1,285 @@
+package output
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fatih/color"
+)
+
+// Style is how one kind of text is drawn
+type Style struct {
+	// Color is an ANSI color number: 0-15 for the terminal palette, 16-255
+	// for the extended one. Empty keeps the terminal's default color.
+	Color     string
+	Bold      bool
+	Underline bool
+	// Reverse swaps foreground and background, to highlight without colors
+	Reverse bool
+}
+
+// Theme is a palette for the command output and the TUI
+type Theme struct {
+	Name string
+
+	Time      Style
+	Delay     Style
+	DelayHigh Style
+	OnTime    Style
+	Line      Style
+	Category  Style // rail product categories
+	Local     Style // local transit categories (TUI)
+	Platform  Style
+	// PlatformChange marks a platform that differs from the schedule
+	PlatformChange Style
+	Dest           Style
+	Canceled       Style
+	Via            Style
+	Header         Style
+	Muted          Style
+	Badge          Style
+
+	// Accent marks focus in the TUI: panel borders, selections, chip cursor
+	Accent Style
+
+	// Backgrounds of highlighted TUI rows. Themes without colors leave them
+	// empty and highlight in reverse video instead.
+	CurrentBg  string // current stop of a journey
+	BoardBg    string // board station within a journey
+	BookmarkBg string // bookmarked stop
+	AlertBg    string // platform change banner
+
+	// Markers in the indicator column of the TUI journey view, one cell
+	// wide. Empty markers fall back to those of DefaultTheme.
+	CurrentMarker  string // current stop by time
+	ScrollMarker   string // stop scrolled to
+	BookmarkMarker string // bookmarked stop
+}
+
+// MarkerOverrides customizes the journey markers and highlight backgrounds
+// of a theme. Empty fields keep the theme's choice.
+type MarkerOverrides struct {
+	Current       string
+	Scroll        string
+	Bookmark      string
+	CurrentColor  string
+	BoardColor    string
+	BookmarkColor string
+}
+
+// DefaultTheme is the palette used unless another theme is chosen
+var DefaultTheme = Theme{
+	Name:           "default",
+	Time:           Style{Color: "15", Bold: true},
+	Delay:          Style{Color: "3"},
+	DelayHigh:      Style{Color: "1", Bold: true},
+	OnTime:         Style{Color: "2"},
+	Line:           Style{Color: "6", Bold: true},
+	Category:       Style{Color: "4", Bold: true},
+	Local:          Style{Color: "13", Bold: true},
+	Platform:       Style{Color: "5"},
+	PlatformChange: Style{Color: "11", Bold: true},
+	Dest:           Style{Color: "7"},
+	Canceled:       Style{Color: "1", Bold: true},
+	Via:            Style{Color: "8"},
+	Header:         Style{Color: "15", Bold: true},
+	Muted:          Style{Color: "8"},
+	Badge:          Style{Color: "3", Bold: true},
+	Accent:         Style{Color: "6", Bold: true},
+	CurrentBg:      "1",
+	BoardBg:        "2",
+	BookmarkBg:     "3",
+	AlertBg:        "1",
+
+	CurrentMarker:  "●",
+	ScrollMarker:   "►",
+	BookmarkMarker: "★",
+}
+
+// themes lists the selectable themes by name
+var themes = map[string]Theme{
+	"default": DefaultTheme,
+
+	// mono tells states apart by weight and underline only, for color
+	// blindness and monochrome terminals
+	"mono": {
+		Name:           "mono",
+		Time:           Style{Bold: true},
+		Delay:          Style{Underline: true},
+		DelayHigh:      Style{Bold: true, Underline: true},
+		Line:           Style{Bold: true},
+		Category:       Style{Bold: true},
+		Local:          Style{Bold: true},
+		Canceled:       Style{Bold: true, Underline: true},
+		PlatformChange: Style{Bold: true, Underline: true},
+		Header:         Style{Bold: true},
+		Badge:          Style{Bold: true},
+		Accent:         Style{Bold: true},
+	},
+
+	"highcontrast": {
+		Name:           "highcontrast",
+		Time:           Style{Color: "15", Bold: true},
+		Delay:          Style{Color: "11", Bold: true},
+		DelayHigh:      Style{Color: "9", Bold: true, Underline: true},
+		OnTime:         Style{Color: "10", Bold: true},
+		Line:           Style{Color: "14", Bold: true},
+		Category:       Style{Color: "12", Bold: true},
+		Local:          Style{Color: "13", Bold: true},
+		Platform:       Style{Color: "13", Bold: true},
+		PlatformChange: Style{Color: "11", Bold: true, Underline: true},
+		Dest:           Style{Color: "15"},
+		Canceled:       Style{Color: "9", Bold: true, Underline: true},
+		Via:            Style{Color: "7"},
+		Header:         Style{Color: "15", Bold: true, Underline: true},
+		Muted:          Style{Color: "7"},
+		Badge:          Style{Color: "11", Bold: true},
+		Accent:         Style{Color: "14", Bold: true},
+		CurrentBg:      "9",
+		BoardBg:        "10",
+		BookmarkBg:     "11",
+		AlertBg:        "9",
+	},
+
+	// solarized uses the 256-color approximations of the Solarized accents
+	"solarized": {
+		Name:           "solarized",
+		Time:           Style{Color: "254", Bold: true},
+		Delay:          Style{Color: "136"},
+		DelayHigh:      Style{Color: "160", Bold: true},
+		OnTime:         Style{Color: "64"},
+		Line:           Style{Color: "37", Bold: true},
+		Category:       Style{Color: "33", Bold: true},
+		Local:          Style{Color: "125", Bold: true},
+		Platform:       Style{Color: "61"},
+		PlatformChange: Style{Color: "166", Bold: true},
+		Dest:           Style{Color: "254"},
+		Canceled:       Style{Color: "160", Bold: true},
+		Via:            Style{Color: "240"},
+		Header:         Style{Color: "254", Bold: true},
+		Muted:          Style{Color: "240"},
+		Badge:          Style{Color: "166", Bold: true},
+		Accent:         Style{Color: "37", Bold: true},
+		CurrentBg:      "160",
+		BoardBg:        "64",
+		BookmarkBg:     "136",
+		AlertBg:        "166",
+	},
+}
+
+// ThemeNames returns the names of the selectable themes, sorted
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// LookupTheme returns the theme with the given name, ignoring case. An empty
+// name yields the default theme.
+func LookupTheme(name string) (Theme, error) {
+	if name == "" {
+		return DefaultTheme, nil
+	}
+	t, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	return t, nil
+}
+
+// WithMarkers returns t with the markers and highlight colors of m applied.
+// Markers must be a single character one cell wide so that the journey
+// columns stay aligned; colors are ANSI color numbers.
+func (t Theme) WithMarkers(m MarkerOverrides) (Theme, error) {
+	for _, marker := range []struct {
+		name  string
+		glyph string
+		dst   *string
+	}{
+		{"current", m.Current, &t.CurrentMarker},
+		{"scroll", m.Scroll, &t.ScrollMarker},
+		{"bookmark", m.Bookmark, &t.BookmarkMarker},
+	} {
+		if marker.glyph == "" {
+			continue
+		}
+		if err := ValidateMarker(marker.glyph); err != nil {
+			return Theme{}, fmt.Errorf("%s marker: %w", marker.name, err)
+		}
+		*marker.dst = marker.glyph
+	}
+
+	for _, bg := range []struct {
+		name  string
+		color string
+		dst   *string
+	}{
+		{"current", m.CurrentColor, &t.CurrentBg},
+		{"board", m.BoardColor, &t.BoardBg},
+		{"bookmark", m.BookmarkColor, &t.BookmarkBg},
+	} {
+		if bg.color == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(bg.color); err != nil || n < 0 || n > 255 {
+			return Theme{}, fmt.Errorf("%s color %q: use an ANSI color number from 0 to 255", bg.name, bg.color)
+		}
+		*bg.dst = bg.color
+	}
+	return t, nil
+}
+
+// ValidateMarker checks that glyph is a single character one cell wide
+func ValidateMarker(glyph string) error {
+	if utf8.RuneCountInString(glyph) != 1 || lipgloss.Width(glyph) != 1 {
+		return fmt.Errorf("%q must be a single character one cell wide", glyph)
+	}
+	return nil
+}
+
+// attributes returns the SGR attributes that draw s
+func (s Style) attributes() []color.Attribute {
+	var attrs []color.Attribute
+	if n, err := strconv.Atoi(s.Color); err == nil {
+		switch {
+		case n >= 0 && n < 8:
+			attrs = append(attrs, color.FgBlack+color.Attribute(n))
+		case n >= 8 && n < 16:
+			attrs = append(attrs, color.FgHiBlack+color.Attribute(n-8))
+		case n >= 16 && n < 256:
+			attrs = append(attrs, 38, 5, color.Attribute(n))
+		}
+	}
+	if s.Bold {
+		attrs = append(attrs, color.Bold)
+	}
+	if s.Underline {
+		attrs = append(attrs, color.Underline)
+	}
+	if s.Reverse {
+		attrs = append(attrs, color.ReverseVideo)
+	}
+	return attrs
+}
+
+// sprintf returns a formatting function drawing its output in s
+func (s Style) sprintf() func(format string, a ...interface{}) string {
+	attrs := s.attributes()
+	if len(attrs) == 0 {
+		// color.New() without attributes would still emit an empty escape
+		return func(format string, a ...interface{}) string {
+			if len(a) == 0 {
+				return format
+			}
+			return fmt.Sprintf(format, a...)
+		}
+	}
+	return color.New(attrs...).SprintfFunc()
+}
@@ -0,0 +1,132 @@
+package output
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestLookupTheme(t *testing.T) {
+	for _, name := range []string{"default", "mono", "highcontrast", "solarized"} {
+		t.Run(name, func(t *testing.T) {
+			theme, err := LookupTheme(name)
+			testutil.AssertNil(t, err)
+			testutil.AssertEqual(t, theme.Name, name)
+		})
+	}
+
+	theme, err := LookupTheme("")
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, theme.Name, "default")
+
+	theme, err = LookupTheme("Mono")
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, theme.Name, "mono")
+
+	_, err = LookupTheme("neon")
+	testutil.AssertError(t, err)
+	testutil.AssertContains(t, err.Error(), "highcontrast, mono, solarized")
+
+	testutil.AssertLen(t, ThemeNames(), 4)
+}
+
+// sgrParams matches the parameters of an SGR escape sequence
+var sgrParams = regexp.MustCompile(`\x1b\[([0-9;]*)m`)
+
+// colorParams returns the SGR parameters in s that select a color
+func colorParams(s string) []string {
+	var found []string
+	for _, m := range sgrParams.FindAllStringSubmatch(s, -1) {
+		for _, p := range strings.Split(m[1], ";") {
+			n, _ := strconv.Atoi(p)
+			if (n >= 30 && n <= 38) || (n >= 40 && n <= 48) || (n >= 90 && n <= 107) {
+				found = append(found, p)
+			}
+		}
+	}
+	return found
+}
+
+func TestNewColorsWithTheme_Mono(t *testing.T) {
+	oldNoColor := color.NoColor
+	defer func() { color.NoColor = oldNoColor }()
+
+	theme, err := LookupTheme("mono")
+	testutil.AssertNil(t, err)
+	c := NewColorsWithTheme(ColorAlways, theme)
+
+	for _, s := range []string{
+		c.FormatDelay(5),
+		c.FormatDelay(DelayHighThreshold + 2),
+		c.FormatDelay(-2),
+		c.FormatDelayCompact(12, false, false),
+		c.Canceled("[CANCELED]"),
+	} {
+		if params := colorParams(s); len(params) > 0 {
+			t.Errorf("%q selects colors %v", s, params)
+		}
+	}
+
+	// Minor and major delays still differ
+	testutil.AssertContains(t, c.FormatDelay(5), "\033[4m")
+	testutil.AssertContains(t, c.FormatDelay(DelayHighThreshold+2), "\033[1;4m")
+
+	// The default theme does use colors
+	c = NewColorsWithTheme(ColorAlways, DefaultTheme)
+	testutil.AssertTrue(t, len(colorParams(c.FormatDelay(5))) > 0)
+}
+
+func TestStyleAttributes(t *testing.T) {
+	tests := []struct {
+		style Style
+		want  []color.Attribute
+	}{
+		{Style{}, nil},
+		{Style{Color: "3"}, []color.Attribute{color.FgYellow}},
+		{Style{Color: "8", Bold: true}, []color.Attribute{color.FgHiBlack, color.Bold}},
+		{Style{Color: "160", Underline: true}, []color.Attribute{38, 5, 160, color.Underline}},
+		{Style{Reverse: true}, []color.Attribute{color.ReverseVideo}},
+	}
+	for _, tt := range tests {
+		got := tt.style.attributes()
+		testutil.AssertLen(t, got, len(tt.want))
+		for i := range tt.want {
+			testutil.AssertEqual(t, got[i], tt.want[i])
+		}
+	}
+}
+
+func TestThemeWithMarkers(t *testing.T) {
+	theme, err := DefaultTheme.WithMarkers(MarkerOverrides{Current: "◆", Bookmark: "*", BoardColor: "4"})
+	testutil.AssertNil(t, err)
+	testutil.AssertEqual(t, theme.CurrentMarker, "◆")
+	testutil.AssertEqual(t, theme.ScrollMarker, "►")
+	testutil.AssertEqual(t, theme.BookmarkMarker, "*")
+	testutil.AssertEqual(t, theme.BoardBg, "4")
+	testutil.AssertEqual(t, theme.CurrentBg, DefaultTheme.CurrentBg)
+
+	// The original theme is unchanged
+	testutil.AssertEqual(t, DefaultTheme.CurrentMarker, "●")
+
+	for _, tt := range []struct {
+		name string
+		m    MarkerOverrides
+		want string
+	}{
+		{"two characters", MarkerOverrides{Current: "->"}, "current marker"},
+		{"wide glyph", MarkerOverrides{Scroll: "🚆"}, "scroll marker"},
+		{"combining mark", MarkerOverrides{Bookmark: "e\u0301"}, "bookmark marker"},
+		{"color name", MarkerOverrides{CurrentColor: "red"}, "current color"},
+		{"color out of range", MarkerOverrides{BookmarkColor: "256"}, "bookmark color"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DefaultTheme.WithMarkers(tt.m)
+			testutil.AssertError(t, err)
+			testutil.AssertContains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+// RenderJourneyTimetable writes a printable timetable of a journey: a header
+// with train, date and operator, then one aligned row per stop with the
+// scheduled arrival, departure and platform. Realtime data (delays, platform
+// changes, cancellations and extra stops) is left out and no ANSI codes are
+// emitted, so the output can be printed or pasted into a document.
+func RenderJourneyTimetable(w io.Writer, journey *models.Journey) {
+	if journey == nil || len(journey.Stops) == 0 {
+		_, _ = fmt.Fprintln(w, "No journey data found.")
+		return
+	}
+
+	stops := make([]models.Stop, 0, len(journey.Stops))
+	for _, stop := range journey.Stops {
+		if !stop.IsAdditional {
+			stops = append(stops, stop)
+		}
+	}
+
+	// Header
+	title := journey.Name
+	if len(stops) > 0 {
+		title += ": " + stops[0].Name + " – " + stops[len(stops)-1].Name
+	}
+	_, _ = fmt.Fprintln(w, strings.TrimPrefix(title, ": "))
+	if day := timetableDay(journey, stops); day != nil {
+		_, _ = fmt.Fprintf(w, "Date:     %s\n", day.Format("Mon, 02.01.2006"))
+	}
+	if journey.Operator != "" {
+		_, _ = fmt.Fprintf(w, "Operator: %s\n", journey.Operator)
+	}
+	_, _ = fmt.Fprintln(w)
+
+	// Size the station column by display width so umlauts and wide
+	// characters don't shift the time columns
+	nameWidth := lipgloss.Width("Station")
+	for _, stop := range stops {
+		nameWidth = max(nameWidth, lipgloss.Width(stop.Name))
+	}
+
+	row := func(name, arr, dep, platform string) {
+		line := fmt.Sprintf("%s  %-5s  %-5s  %s", padDisplay(name, nameWidth), arr, dep, platform)
+		_, _ = fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+
+	row("Station", "Arr", "Dep", "Platform")
+	row(strings.Repeat("-", nameWidth), "-----", "-----", "--------")
+	for i, stop := range stops {
+		arr, dep := "", ""
+		if stop.SchedArr != nil && i > 0 {
+			arr = stop.SchedArr.Format("15:04")
+		}
+		if stop.SchedDep != nil && i < len(stops)-1 {
+			dep = stop.SchedDep.Format("15:04")
+		}
+		row(stop.Name, arr, dep, stop.Platform)
+	}
+}
+
+// timetableDay returns the operating day of a journey, falling back to the
+// first scheduled time when the API didn't send one
+func timetableDay(journey *models.Journey, stops []models.Stop) *time.Time {
+	if journey.Day != nil {
+		return journey.Day
+	}
+	for _, stop := range stops {
+		if stop.SchedDep != nil {
+			return stop.SchedDep
+		}
+		if stop.SchedArr != nil {
+			return stop.SchedArr
+		}
+	}
+	return nil
+}
+
+// padDisplay pads s with spaces to width terminal cells
+func padDisplay(s string, width int) string {
+	if pad := width - lipgloss.Width(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestRenderJourneyTimetable(t *testing.T) {
+	journey := newShareJourney()
+	journey.Operator = "DB Fernverkehr AG"
+	journey.Stops[1].Platform = "3"
+	journey.Stops = append(journey.Stops[:2], append([]models.Stop{{Name: "Umleitung", IsAdditional: true}}, journey.Stops[2:]...)...)
+
+	out := RenderJourneyTimetableString(journey)
+
+	testutil.AssertEqual(t, out, strings.Join([]string{
+		"ICE 623: Köln Hbf – München Hbf",
+		"Date:     Mon, 01.01.2024",
+		"Operator: DB Fernverkehr AG",
+		"",
+		"Station          Arr    Dep    Platform",
+		"---------------  -----  -----  --------",
+		"Köln Hbf                12:00",
+		"Mannheim Hbf     13:30  13:35  3",
+		"Vaihingen (Enz)  14:00  14:01",
+		"Stuttgart Hbf    14:10  14:15",
+		"München Hbf      15:25",
+		"",
+	}, "\n"))
+
+	// Scheduled times only, no realtime or color noise
+	testutil.AssertFalse(t, strings.Contains(out, "13:40"))
+	testutil.AssertFalse(t, strings.Contains(out, "\033["))
+}
+
+func TestRenderJourneyTimetable_Empty(t *testing.T) {
+	testutil.AssertEqual(t, RenderJourneyTimetableString(nil), "No journey data found.\n")
+}
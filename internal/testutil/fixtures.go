@@ -148,6 +148,50 @@ const SampleFormationResponse = `{
 	}
 }`
 
+// SampleConnectionResponse is a minimal trip search response with one
+// connection: an ICE, a walk between stations and an S-Bahn
+const SampleConnectionResponse = `{
+	"verbindungen": [
+		{
+			"umstiegsAnzahl": 1,
+			"verbindungsDauerInSeconds": 4680,
+			"ezVerbindungsDauerInSeconds": 4860,
+			"verbindungsAbschnitte": [
+				{
+					"journeyId": "2|#VN#1#ST#ICE",
+					"abfahrtsZeitpunkt": "2025-12-28T12:04:00",
+					"ezAbfahrtsZeitpunkt": "2025-12-28T12:07:00",
+					"ankunftsZeitpunkt": "2025-12-28T13:10:00",
+					"ezAnkunftsZeitpunkt": "2025-12-28T13:14:00",
+					"abfahrtsOrt": "Köln Hbf",
+					"abfahrtsOrtExtId": "8000207",
+					"ankunftsOrt": "Frankfurt(Main)Hbf",
+					"ankunftsOrtExtId": "8000105",
+					"verkehrsmittel": {"produktGattung": "ICE", "name": "ICE 123", "mittelText": "ICE 123", "richtung": "München Hbf", "typ": "PUBLICTRANSPORT"},
+					"halte": [{"gleis": "4"}, {"gleis": "7", "ezGleis": "9"}]
+				},
+				{
+					"abfahrtsZeitpunkt": "2025-12-28T13:14:00",
+					"ankunftsZeitpunkt": "2025-12-28T13:20:00",
+					"abfahrtsOrt": "Frankfurt(Main)Hbf",
+					"ankunftsOrt": "Frankfurt(Main)Hbf (tief)",
+					"verkehrsmittel": {"name": "Fußweg", "typ": "WALK"}
+				},
+				{
+					"journeyId": "2|#VN#1#ST#S8",
+					"abfahrtsZeitpunkt": "2025-12-28T13:24:00",
+					"ankunftsZeitpunkt": "2025-12-28T13:42:00",
+					"abfahrtsOrt": "Frankfurt(Main)Hbf (tief)",
+					"ankunftsOrt": "Frankfurt Flughafen Regionalbf.",
+					"ankunftsOrtExtId": 8070004,
+					"verkehrsmittel": {"produktGattung": "SBAHN", "name": "S 8", "mittelText": "S 8", "richtung": "Wiesbaden Hbf", "typ": "PUBLICTRANSPORT"},
+					"halte": [{"gleis": "101"}, {"gleis": "1"}]
+				}
+			]
+		}
+	]
+}`
+
 // SampleEmptyResponse is an empty JSON response
 const SampleEmptyResponse = `{}`
 
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mobil-koeln/moko-cli/internal/api"
+)
+
+// maxRefreshBackoff caps the auto-refresh interval while the API is failing
+const maxRefreshBackoff = 5 * time.Minute
+
+// isOutage reports whether a refresh error means the API is unreachable or
+// refusing requests, as opposed to rejecting this particular query
+func isOutage(err error) bool {
+	if errors.Is(err, api.ErrBlocked) || errors.Is(err, api.ErrTimeout) ||
+		errors.Is(err, api.ErrServerError) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// recordRefreshResult updates the count of consecutive failed refreshes.
+// Only outages count; any answer from the API resets it.
+func (m Model) recordRefreshResult(err error) Model {
+	if err == nil || !isOutage(err) {
+		m.refreshFailures = 0
+		m.refreshBlocked = false
+		return m
+	}
+	m.refreshFailures++
+	m.refreshBlocked = errors.Is(err, api.ErrBlocked)
+	return m
+}
+
+// refreshDelay returns the wait until the next auto-refresh: the configured
+// interval, doubled for each consecutive failure up to maxRefreshBackoff.
+// Being blocked by bahn.de backs off one step further, since retrying soon
+// only prolongs the block.
+func (m Model) refreshDelay() time.Duration {
+	if m.refreshFailures == 0 {
+		return m.refreshInterval
+	}
+	steps := m.refreshFailures
+	if m.refreshBlocked {
+		steps++
+	}
+	limit := max(maxRefreshBackoff, m.refreshInterval)
+	delay := m.refreshInterval << min(steps, 10)
+	if delay <= 0 || delay > limit {
+		return limit
+	}
+	return delay
+}
+
+// scheduleRefresh starts the wait for the next auto-refresh tick
+func (m Model) scheduleRefresh() (Model, tea.Cmd) {
+	delay := m.refreshDelay()
+	m.nextRefresh = m.now().Add(delay)
+	return m, autoRefreshTick(delay)
+}
+
+// reconnectStatus describes the backoff state for the update line, or ""
+// while refreshes succeed
+func (m Model) reconnectStatus() string {
+	if !m.autoRefresh || m.refreshFailures == 0 {
+		return ""
+	}
+	if m.refreshBlocked {
+		return fmt.Sprintf("blocked by bahn.de, reconnecting (attempt %d)", m.refreshFailures)
+	}
+	return fmt.Sprintf("reconnecting (attempt %d)", m.refreshFailures)
+}
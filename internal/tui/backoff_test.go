@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mobil-koeln/moko-cli/internal/api"
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestRefreshBackoff(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client).WithRefreshInterval(30 * time.Second)
+	m.autoRefresh = true
+
+	testutil.AssertEqual(t, m.refreshDelay(), 30*time.Second)
+	testutil.AssertEqual(t, m.reconnectStatus(), "")
+
+	timeout := fmt.Errorf("%w: %w", api.ErrTimeout, context.DeadlineExceeded)
+	m = m.recordRefreshResult(timeout)
+	testutil.AssertEqual(t, m.refreshDelay(), time.Minute)
+	m = m.recordRefreshResult(timeout)
+	testutil.AssertEqual(t, m.refreshDelay(), 2*time.Minute)
+	testutil.AssertEqual(t, m.reconnectStatus(), "reconnecting (attempt 2)")
+
+	// Capped however long the outage lasts
+	for i := 0; i < 20; i++ {
+		m = m.recordRefreshResult(timeout)
+	}
+	testutil.AssertEqual(t, m.refreshDelay(), maxRefreshBackoff)
+
+	// Success resets
+	m = m.recordRefreshResult(nil)
+	testutil.AssertEqual(t, m.refreshDelay(), 30*time.Second)
+	testutil.AssertEqual(t, m.reconnectStatus(), "")
+
+	// Being blocked backs off one step further
+	m = m.recordRefreshResult(api.NewAPIError(429, "Too Many Requests", "/board"))
+	testutil.AssertEqual(t, m.refreshDelay(), 2*time.Minute)
+	testutil.AssertContains(t, m.reconnectStatus(), "blocked")
+
+	// A rejected query is not an outage
+	m = m.recordRefreshResult(api.NewAPIError(400, "Bad Request", "/board"))
+	testutil.AssertEqual(t, m.refreshFailures, 0)
+	m = m.recordRefreshResult(errors.New("failed to parse departures response"))
+	testutil.AssertEqual(t, m.refreshFailures, 0)
+}
+
+func TestRefreshBackoff_Indicator(t *testing.T) {
+	client, _ := api.NewClient()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	m := New(client).WithClock(func() time.Time { return now }).WithRefreshInterval(15 * time.Second)
+	m.width = 160
+	m.autoRefresh = true
+	m.selectedStation = &models.Location{Name: "Köln Hbf", EVA: 8000207}
+	m.lastUpdate = now.Add(-time.Minute)
+
+	result, _ := m.Update(departuresResultMsg{stationEVA: 8000207, err: api.NewAPIError(503, "Service Unavailable", "/board")})
+	m = result.(Model)
+	result, _ = m.Update(autoRefreshTickMsg(now))
+	m = result.(Model)
+
+	// The next tick waits twice the interval and the countdown follows it
+	out := m.renderFilterBar()
+	testutil.AssertContains(t, out, "reconnecting (attempt 1)")
+	testutil.AssertContains(t, out, "(refresh in 30s)")
+
+	result, _ = m.Update(departuresResultMsg{stationEVA: 8000207})
+	m = result.(Model)
+	testutil.AssertFalse(t, strings.Contains(m.renderFilterBar(), "reconnecting"))
+}
@@ -2,6 +2,8 @@ package tui
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,13 +12,16 @@ import (
 )
 
 const (
-	apiTimeout          = 5 * time.Second
-	autoRefreshInterval = 30 * time.Second
+	apiTimeout = 5 * time.Second
+
+	// DefaultRefreshInterval is the auto-refresh interval unless changed
+	// with WithRefreshInterval
+	DefaultRefreshInterval = 30 * time.Second
 )
 
 // autoRefreshTick returns a tea.Cmd that sends a tick after the refresh interval.
-func autoRefreshTick() tea.Cmd {
-	return tea.Tick(autoRefreshInterval, func(t time.Time) tea.Msg {
+func autoRefreshTick(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
 		return autoRefreshTickMsg(t)
 	})
 }
@@ -28,6 +33,15 @@ func countdownTick() tea.Cmd {
 	})
 }
 
+// ringBell returns a tea.Cmd that rings the terminal bell. It writes to
+// stderr so it doesn't interleave with the renderer's frames on stdout.
+func ringBell() tea.Cmd {
+	return func() tea.Msg {
+		_, _ = fmt.Fprint(os.Stderr, "\a")
+		return nil
+	}
+}
+
 // searchStations returns a tea.Cmd that searches for stations.
 func searchStations(client *api.Client, query string, seq int) tea.Cmd {
 	return func() tea.Msg {
@@ -43,8 +57,17 @@ func searchStations(client *api.Client, query string, seq int) tea.Cmd {
 	}
 }
 
+// boardQuery holds the board request parameters that can change while the
+// TUI is running.
+type boardQuery struct {
+	modes []string
+	mode  boardMode
+	at    time.Time // query time; zero means now
+	vias  int
+}
+
 // fetchBoard returns a tea.Cmd that fetches departures or arrivals for a station.
-func fetchBoard(client *api.Client, station models.Location, modes []string, mode boardMode) tea.Cmd {
+func fetchBoard(client *api.Client, station models.Location, q boardQuery) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
 		defer cancel()
@@ -52,12 +75,13 @@ func fetchBoard(client *api.Client, station models.Location, modes []string, mod
 		req := api.StationBoardRequest{
 			EVA:            station.EVA,
 			StationID:      station.ID,
-			NumVias:        5,
-			ModesOfTransit: modes,
+			DateTime:       q.at,
+			NumVias:        q.vias,
+			ModesOfTransit: q.modes,
 		}
 		var departures []models.Departure
 		var err error
-		if mode == boardArrival {
+		if q.mode == boardArrival {
 			departures, err = client.GetArrivals(ctx, req)
 		} else {
 			departures, err = client.GetDepartures(ctx, req)
@@ -70,13 +94,14 @@ func fetchBoard(client *api.Client, station models.Location, modes []string, mod
 	}
 }
 
-// fetchJourney returns a tea.Cmd that fetches journey details.
-func fetchJourney(client *api.Client, journeyID string) tea.Cmd {
+// fetchJourney returns a tea.Cmd that fetches journey details, including the
+// route polyline when withPolyline is set.
+func fetchJourney(client *api.Client, journeyID string, withPolyline bool) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
 		defer cancel()
 
-		journey, err := client.GetJourney(ctx, journeyID, false)
+		journey, err := client.GetJourney(ctx, journeyID, withPolyline)
 		return journeyResultMsg{
 			journeyID: journeyID,
 			journey:   journey,
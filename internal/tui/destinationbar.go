@@ -159,7 +159,7 @@ func (m Model) handleDestinationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "q":
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	return m, nil
@@ -47,16 +47,23 @@ func (m Model) renderFilterBar() string {
 	boardBox := boardBorder.Render(board.String())
 
 	// --- Auto-refresh box ---
+	var refresh strings.Builder
+
 	refreshFocused := m.focus == focusAutoRefresh
-	refreshChip := m.renderChip("Auto-refresh 30s", m.autoRefresh, refreshFocused)
+	refresh.WriteString(m.renderChip("Auto-refresh "+formatInterval(m.refreshInterval), m.autoRefresh, refreshFocused && m.refreshCursor == 0))
+	refresh.WriteString(" ")
+	refresh.WriteString(m.renderChip("Journey only", m.journeyOnlyRefresh, refreshFocused && m.refreshCursor == 1))
 
 	refreshBorder := stylePanelNormal
 	if refreshFocused {
 		refreshBorder = stylePanelFocused
 	}
-	refreshBox := refreshBorder.Render(refreshChip)
+	refreshBox := refreshBorder.Render(refresh.String())
+
+	// --- Query time and vias box ---
+	queryBox := stylePanelNormal.Render(m.renderBoardQuery())
 
-	boxes := lipgloss.JoinHorizontal(lipgloss.Top, modesBox, boardBox, refreshBox)
+	boxes := lipgloss.JoinHorizontal(lipgloss.Top, modesBox, boardBox, refreshBox, queryBox)
 
 	// Last update line above the boxes
 	if !m.lastUpdate.IsZero() {
@@ -64,16 +71,24 @@ func (m Model) renderFilterBar() string {
 
 		// Add countdown if auto-refresh is enabled
 		if m.autoRefresh {
-			elapsed := time.Since(m.lastUpdate)
-			remaining := autoRefreshInterval - elapsed
+			remaining := m.refreshInterval - m.now().Sub(m.lastUpdate)
+			if !m.nextRefresh.IsZero() {
+				remaining = m.nextRefresh.Sub(m.now())
+			}
 			if remaining < 0 {
 				remaining = 0
 			}
 			seconds := int(remaining.Seconds())
 			updateText += fmt.Sprintf("\t(refresh in %ds)", seconds)
+			if m.journeyOnlyActive() {
+				updateText += " journey only"
+			}
 		}
 
 		updateLine := styleMuted.Render(updateText)
+		if status := m.reconnectStatus(); status != "" {
+			updateLine += "  " + styleDelay.Render(status)
+		}
 		return updateLine + "\n" + boxes
 	}
 
@@ -131,7 +146,7 @@ func (m Model) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "q":
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	return m, nil
@@ -174,7 +189,7 @@ func (m Model) handleBoardKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "q":
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	return m, nil
@@ -206,7 +221,85 @@ func (m Model) refetchBoard() (tea.Model, tea.Cmd) {
 		m.destinationCursor = 0
 		m.showJourney = false
 		m.journey = nil
-		return m, fetchBoard(m.client, *m.selectedStation, m.selectedModes(), m.boardMode)
+		return m, fetchBoard(m.client, *m.selectedStation, m.boardQuery())
 	}
 	return m, nil
 }
+
+const (
+	defaultBoardVias = 5
+	maxBoardVias     = 15
+	boardOffsetStep  = 30 * time.Minute
+	maxBoardOffset   = 24 * time.Hour
+)
+
+// boardQuery returns the parameters for the next board request.
+func (m Model) boardQuery() boardQuery {
+	q := boardQuery{
+		modes: m.selectedModes(),
+		mode:  m.boardMode,
+		vias:  m.boardVias,
+	}
+	if m.boardOffset != 0 {
+		q.at = m.now().Add(m.boardOffset)
+	}
+	return q
+}
+
+// shiftBoardTime moves the board query time by steps offset steps and
+// refetches the board. The offset is kept within a day of now.
+func (m Model) shiftBoardTime(steps int) (tea.Model, tea.Cmd) {
+	offset := m.boardOffset + time.Duration(steps)*boardOffsetStep
+	m.boardOffset = min(max(offset, -maxBoardOffset), maxBoardOffset)
+	return m.refetchBoard()
+}
+
+// adjustBoardVias changes the number of via stops requested per entry by
+// delta and refetches the board.
+func (m Model) adjustBoardVias(delta int) (tea.Model, tea.Cmd) {
+	vias := min(max(m.boardVias+delta, 0), maxBoardVias)
+	if vias == m.boardVias {
+		return m, nil
+	}
+	m.boardVias = vias
+	return m.refetchBoard()
+}
+
+// renderBoardQuery describes the board query time and via count, e.g.
+// "Now  Vias 5" or "+1h30m (16:45)  Vias 8".
+func (m Model) renderBoardQuery() string {
+	at := "Now"
+	if m.boardOffset != 0 {
+		at = styleDelay.Render(formatOffset(m.boardOffset) + " (" + m.now().Add(m.boardOffset).Format("15:04") + ")")
+	}
+	return at + styleMuted.Render(fmt.Sprintf("  Vias %d", m.boardVias))
+}
+
+// formatOffset formats a signed whole-minute offset as "+30m", "-1h" or "+2h30m".
+func formatOffset(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	h, mins := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%s%dm", sign, mins)
+	case mins == 0:
+		return fmt.Sprintf("%s%dh", sign, h)
+	}
+	return fmt.Sprintf("%s%dh%02dm", sign, h, mins)
+}
+
+// formatInterval formats a refresh interval for the auto-refresh chip, e.g.
+// "15s", "2m" or "1m30s"
+func formatInterval(d time.Duration) string {
+	switch {
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm%ds", int(d.Minutes()), int(d.Seconds())%60)
+}
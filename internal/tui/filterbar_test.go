@@ -2,8 +2,11 @@ package tui
 
 import (
 	"testing"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mobil-koeln/moko-cli/internal/api"
+	"github.com/mobil-koeln/moko-cli/internal/models"
 	"github.com/mobil-koeln/moko-cli/internal/testutil"
 )
 
@@ -105,3 +108,75 @@ func TestRenderFilterBar_AutoRefreshOn(t *testing.T) {
 	// Should show refresh is on
 	testutil.AssertTrue(t, len(output) > 0)
 }
+
+func TestBoardQuery_TimeAndVias(t *testing.T) {
+	client, _ := api.NewClient()
+	now := time.Date(2025, 12, 28, 15, 15, 0, 0, time.UTC)
+	m := New(client).WithClock(func() time.Time { return now })
+	m.selectedStation = &models.Location{Name: "Köln Hbf", EVA: 8000207}
+	m.focus = focusDepartures
+
+	// Live board by default
+	q := m.boardQuery()
+	testutil.AssertTrue(t, q.at.IsZero())
+	testutil.AssertEqual(t, q.vias, 5)
+	testutil.AssertContains(t, m.renderBoardQuery(), "Now")
+
+	press := func(key rune) {
+		t.Helper()
+		newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		m = newModel.(Model)
+		testutil.AssertTrue(t, cmd != nil)
+	}
+
+	press('+')
+	press('+')
+	press('+')
+	testutil.AssertEqual(t, m.boardQuery().at, now.Add(90*time.Minute))
+	testutil.AssertContains(t, m.renderBoardQuery(), "+1h30m (16:45)")
+	testutil.AssertTrue(t, m.departuresLoading)
+
+	press('-')
+	press('-')
+	press('-')
+	press('-')
+	testutil.AssertContains(t, m.renderBoardQuery(), "-30m (14:45)")
+
+	press('>')
+	testutil.AssertEqual(t, m.boardQuery().vias, 6)
+	press('<')
+	press('<')
+	testutil.AssertEqual(t, m.boardQuery().vias, 4)
+	testutil.AssertContains(t, m.renderFilterBar(), "Vias 4")
+
+	// The keys type into the search box instead
+	m.focus = focusSearch
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.boardOffset, -30*time.Minute)
+}
+
+func TestFormatOffset(t *testing.T) {
+	testutil.AssertEqual(t, formatOffset(30*time.Minute), "+30m")
+	testutil.AssertEqual(t, formatOffset(-2*time.Hour), "-2h")
+	testutil.AssertEqual(t, formatOffset(150*time.Minute), "+2h30m")
+}
+
+func TestRenderFilterBar_RefreshInterval(t *testing.T) {
+	client, _ := api.NewClient()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	m := New(client).WithClock(func() time.Time { return now }).WithRefreshInterval(15 * time.Second)
+	m.width = 160
+	m.autoRefresh = true
+	m.lastUpdate = now.Add(-5 * time.Second)
+
+	output := m.renderFilterBar()
+	testutil.AssertContains(t, output, "Auto-refresh 15s")
+	testutil.AssertContains(t, output, "(refresh in 10s)")
+}
+
+func TestFormatInterval(t *testing.T) {
+	testutil.AssertEqual(t, formatInterval(30*time.Second), "30s")
+	testutil.AssertEqual(t, formatInterval(2*time.Minute), "2m")
+	testutil.AssertEqual(t, formatInterval(90*time.Second), "1m30s")
+}
@@ -2,9 +2,11 @@ package tui
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
-	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mobil-koeln/moko-cli/internal/models"
 	"github.com/mobil-koeln/moko-cli/internal/output"
@@ -39,9 +41,10 @@ func (m Model) renderJourneyDetail(width, height int) string {
 	// Reserve space for scrollbar
 	contentWidth := width - 2
 
-	currentIdx := output.FindCurrentStopIndex(stops, time.Now())
+	currentIdx := output.FindCurrentStopIndex(stops, m.now())
 
 	boardStationIdx := findBoardStationIdx(stops, m.selectedStation)
+	bookmarkIdx := m.bookmarkIdx()
 
 	maxVisible := height - 2
 	if maxVisible < 1 {
@@ -49,146 +52,41 @@ func (m Model) renderJourneyDetail(width, height int) string {
 	}
 	start, end := visibleRange(m.journeyScroll, len(stops), maxVisible)
 
-	// Build content lines
+	var estimates []int
+	if m.estimateDelays {
+		estimates = output.EstimateDelays(stops)
+	}
+
+	// Build content lines, reusing lines rendered in earlier frames
+	cache := m.journeyLines
+	if cache != nil {
+		cache.reset(m.journey, contentWidth)
+	}
 	var contentLines []string
 	for i := start; i < end; i++ {
-		stop := stops[i]
-		isFirst := i == 0
-		isLast := i == len(stops)-1
-		isCurrent := i == currentIdx
-		isBoardStation := i == boardStationIdx
-		isScrolledTo := i == m.journeyScroll // User's scroll position
-
-		// Route symbol
-		symbol := "├"
-		if isFirst {
-			symbol = "┌"
-		} else if isLast {
-			symbol = "└"
-		}
-
-		// Indicator: show scroll position when journey is visible, current stop otherwise
-		indicator := " "
-		if isScrolledTo && m.showJourney {
-			indicator = "►" // Show scroll position when journey is visible
-		} else if isCurrent && !isScrolledTo {
-			indicator = "●" // Show current time-based stop with different symbol
-		}
-
-		// Time
-		timeStr := "     "
-		if stop.Arr != nil && !isFirst {
-			timeStr = stop.Arr.Format("15:04")
-		} else if stop.Dep != nil && isFirst {
-			timeStr = stop.Dep.Format("15:04")
-		}
-
-		// Delay - format as plain text for width calculation
-		var delayPlain string
-		if stop.Delay == 0 {
-			delayPlain = "    "
-		} else if stop.Delay > 0 {
-			delayPlain = fmt.Sprintf("%+4d", stop.Delay)
-		} else {
-			delayPlain = fmt.Sprintf("%4d", stop.Delay)
-		}
-
-		// Platform
-		platform := stop.EffectivePlatform()
-		platformStr := "       "
-		if platform != "" {
-			if len(platform) > 3 {
-				platform = platform[:3]
-			}
-			platformStr = fmt.Sprintf("Pl.%-3s ", platform)
+		state := stopLineState{
+			first:       i == 0,
+			last:        i == len(stops)-1,
+			current:     i == currentIdx,
+			board:       i == boardStationIdx,
+			bookmarked:  i == bookmarkIdx,
+			scrolledTo:  i == m.journeyScroll, // User's scroll position
+			showJourney: m.showJourney,
 		}
-
-		// Station name - pad to fill full width for consistent highlighting
-		name := stop.Name
-		fixedWidth := 1 + 1 + 1 + 1 + 5 + 1 + 4 + 2 + 7 // indicator+sp+symbol+sp+time+sp+delay+sp+platform
-		maxName := contentWidth - fixedWidth - 2
-
-		// Reserve space for [X] if cancelled
-		if stop.IsCancelled {
-			maxName -= 4 // Reserve 4 chars for " [X]"
+		if estimates != nil {
+			state.estDelay = estimates[i]
 		}
-
-		if maxName > 0 {
-			if len(name) > maxName {
-				name = name[:maxName]
-			} else {
-				// Pad with spaces to fill the full width
-				name = name + strings.Repeat(" ", maxName-len(name))
-			}
-		}
-
-		// Build the line content with PLAIN TEXT (no ANSI codes) for proper width calculation
-		var lineContent string
-		if stop.IsCancelled {
-			lineContent = fmt.Sprintf("%s %s %s %s  %s %s",
-				indicator,
-				symbol,
-				timeStr,
-				delayPlain, // Use plain text delay
-				platformStr,
-				name+" [X]",
-			)
-		} else {
-			lineContent = fmt.Sprintf("%s %s %s %s  %s %s",
-				indicator,
-				symbol,
-				timeStr,
-				delayPlain, // Use plain text delay
-				platformStr,
-				name,
-			)
+		key := journeyLineKey{index: i, state: state}
+		line, ok := "", false
+		if cache != nil {
+			line, ok = cache.lines[key]
 		}
-
-		// Apply full-width highlight based on state (priority: red > green > cyan > normal)
-		var line string
-		if isCurrent && !stop.IsCancelled {
-			// Red highlight for current stop (full width, highest priority)
-			line = styleCurrentStop.Width(contentWidth).Render(lineContent)
-		} else if isBoardStation && !stop.IsCancelled {
-			// Green highlight for board station (full width)
-			line = styleBoardStation.Width(contentWidth).Render(lineContent)
-		} else if isScrolledTo && m.showJourney && !isCurrent {
-			// Cyan highlight for scroll position (full width)
-			line = styleSelected.Width(contentWidth).Render(lineContent)
-		} else if stop.IsCancelled {
-			// No background, just colored text for cancelled
-			// Get styled delay for non-highlighted rows
-			delayStyled := "    "
-			if stop.Delay != 0 {
-				delayStyled = formatDelay(stop.Delay)
-			}
-			lineContent = fmt.Sprintf("%s %s %s %s  %s %s",
-				indicator,
-				styleMuted.Render(symbol),
-				styleCanceled.Render(timeStr),
-				delayStyled,
-				styleCanceled.Render(platformStr),
-				styleCanceled.Render(name+" [X]"),
-			)
-			line = lineContent
-		} else {
-			// Normal rendering with colored text
-			// Get styled delay for non-highlighted rows
-			delayStyled := "    "
-			if stop.Delay != 0 {
-				delayStyled = formatDelay(stop.Delay)
+		if !ok {
+			line = renderJourneyStopLine(stops[i], state, contentWidth)
+			if cache != nil {
+				cache.lines[key] = line
 			}
-			lineContent = fmt.Sprintf("%s %s %s %s  %s %s",
-				indicator,
-				styleMuted.Render(symbol),
-				styleTime.Render(timeStr),
-				delayStyled,
-				stylePlatform.Render(platformStr),
-				name,
-			)
-			line = lineContent
 		}
-
 		contentLines = append(contentLines, line)
 	}
 
@@ -201,14 +99,12 @@ func (m Model) renderJourneyDetail(width, height int) string {
 	scrollbar := renderScrollbar(m.journeyScroll, len(stops), maxVisible)
 	scrollbarLines := strings.Split(scrollbar, "\n")
 
-	// Combine content and scrollbar
+	// Combine content and scrollbar (stop lines are already padded)
 	var b strings.Builder
 	for i := 0; i < len(contentLines); i++ {
 		line := contentLines[i]
-		// Pad line to contentWidth
-		lineWidth := lipgloss.Width(line)
-		if lineWidth < contentWidth {
-			line += strings.Repeat(" ", contentWidth-lineWidth)
+		if line == "" {
+			line = strings.Repeat(" ", contentWidth)
 		}
 		b.WriteString(line)
 
@@ -225,6 +121,252 @@ func (m Model) renderJourneyDetail(width, height int) string {
 	return titleStr + "\n" + b.String()
 }
 
+// stopLineState captures everything besides the stop itself that affects how
+// a journey stop line is rendered.
+type stopLineState struct {
+	first, last bool
+	current     bool // time-based current stop
+	board       bool // stop of the selected board station
+	bookmarked  bool // stop the user marked as theirs
+	scrolledTo  bool // user's scroll position
+	showJourney bool
+	estDelay    int // delay estimated from an earlier stop, 0 for none
+}
+
+// journeyLineKey identifies a rendered stop line within a journey.
+type journeyLineKey struct {
+	index int
+	state stopLineState
+}
+
+// journeyLineCache memoizes rendered journey stop lines across frames. Lines
+// only depend on the stop, its state and the width, so they stay valid until
+// a new journey arrives (every fetch yields a new pointer) or the panel resizes.
+type journeyLineCache struct {
+	journey *models.Journey
+	width   int
+	lines   map[journeyLineKey]string
+}
+
+// reset clears the cache when the journey or width differs from the cached one.
+func (c *journeyLineCache) reset(journey *models.Journey, width int) {
+	if c.lines != nil && c.journey == journey && c.width == width {
+		return
+	}
+	c.journey = journey
+	c.width = width
+	c.lines = make(map[journeyLineKey]string)
+}
+
+// renderJourneyStopLine renders a single journey stop, padded to contentWidth.
+func renderJourneyStopLine(stop models.Stop, state stopLineState, contentWidth int) string {
+	// Route symbol
+	symbol := "├"
+	if state.first {
+		symbol = "┌"
+	} else if state.last {
+		symbol = "└"
+	}
+
+	// Indicator: show scroll position when journey is visible, current stop otherwise
+	indicator := " "
+	if state.scrolledTo && state.showJourney {
+		indicator = markerScroll // Show scroll position when journey is visible
+	} else if state.current && !state.scrolledTo {
+		indicator = markerCurrent // Show current time-based stop with different symbol
+	} else if state.bookmarked {
+		indicator = markerBookmark
+	}
+
+	// Time
+	timeStr := "     "
+	if stop.Arr != nil && !state.first {
+		timeStr = stop.Arr.Format("15:04")
+	} else if stop.Dep != nil && state.first {
+		timeStr = stop.Dep.Format("15:04")
+	}
+
+	// Delay - format as plain text for width calculation
+	var delayPlain string
+	if stop.Delay == 0 {
+		delayPlain = "    "
+	} else if stop.Delay > 0 {
+		delayPlain = fmt.Sprintf("%+4d", stop.Delay)
+	} else {
+		delayPlain = fmt.Sprintf("%4d", stop.Delay)
+	}
+	estimated := stop.Delay == 0 && state.estDelay != 0
+	if estimated {
+		delayPlain = fmt.Sprintf("%4s", fmt.Sprintf("~%+d", state.estDelay))
+	}
+
+	// Platform
+	platform := stop.EffectivePlatform()
+	platformStr := "       "
+	if platform != "" {
+		if len(platform) > 3 {
+			platform = platform[:3]
+		}
+		platformStr = fmt.Sprintf("Pl.%-3s ", platform)
+	}
+
+	// Station name - pad to fill full width for consistent highlighting
+	name := stop.Name
+	fixedWidth := 1 + 1 + 1 + 1 + 5 + 1 + 4 + 2 + 7 // indicator+sp+symbol+sp+time+sp+delay+sp+platform
+	maxName := contentWidth - fixedWidth - 2
+
+	// Reserve space for [X] if cancelled, or the note on unscheduled stops
+	suffix := ""
+	if stop.IsCancelled {
+		suffix = " [X]"
+	} else if stop.IsAdditional {
+		suffix = " " + output.AdditionalStopNote
+	}
+	if estimated {
+		suffix += " " + output.EstimatedDelayNote
+	}
+	maxName -= len(suffix)
+
+	if maxName > 0 {
+		if len(name) > maxName {
+			name = name[:maxName]
+		} else {
+			// Pad with spaces to fill the full width
+			name = name + strings.Repeat(" ", maxName-len(name))
+		}
+	}
+
+	// Build the line content with PLAIN TEXT (no ANSI codes) for proper width calculation
+	lineContent := fmt.Sprintf("%s %s %s %s  %s %s",
+		indicator,
+		symbol,
+		timeStr,
+		delayPlain, // Use plain text delay
+		platformStr,
+		name+suffix,
+	)
+
+	// Apply full-width highlight based on state (priority: red > yellow > green > cyan > normal)
+	var line string
+	if state.current && !stop.IsCancelled {
+		// Red highlight for current stop (full width, highest priority)
+		line = styleCurrentStop.Width(contentWidth).Render(lineContent)
+	} else if state.bookmarked && !stop.IsCancelled {
+		// Yellow highlight for the bookmarked stop (full width)
+		line = styleBookmark.Width(contentWidth).Render(lineContent)
+	} else if state.board && !stop.IsCancelled {
+		// Green highlight for board station (full width)
+		line = styleBoardStation.Width(contentWidth).Render(lineContent)
+	} else if state.scrolledTo && state.showJourney && !state.current {
+		// Cyan highlight for scroll position (full width)
+		line = styleSelected.Width(contentWidth).Render(lineContent)
+	} else if stop.IsCancelled {
+		// No background, just colored text for cancelled
+		// Get styled delay for non-highlighted rows
+		delayStyled := "    "
+		if stop.Delay != 0 {
+			delayStyled = formatDelay(stop.Delay)
+		}
+		lineContent = fmt.Sprintf("%s %s %s %s  %s %s",
+			indicator,
+			styleMuted.Render(symbol),
+			styleCanceled.Render(timeStr),
+			delayStyled,
+			styleCanceled.Render(platformStr),
+			styleCanceled.Render(name+suffix),
+		)
+		line = lineContent
+	} else {
+		// Normal rendering with colored text
+		// Get styled delay for non-highlighted rows
+		delayStyled := "    "
+		if stop.Delay != 0 {
+			delayStyled = formatDelay(stop.Delay)
+		} else if estimated {
+			delayStyled = styleMuted.Render(delayPlain)
+		}
+		lineContent = fmt.Sprintf("%s %s %s %s  %s %s",
+			indicator,
+			styleMuted.Render(symbol),
+			styleTime.Render(timeStr),
+			delayStyled,
+			stylePlatform.Render(platformStr),
+			name+styleMuted.Render(suffix),
+		)
+		line = lineContent
+	}
+
+	// Pad line to contentWidth
+	if lineWidth := lipgloss.Width(line); lineWidth < contentWidth {
+		line += strings.Repeat(" ", contentWidth-lineWidth)
+	}
+	return line
+}
+
+// stopKey identifies a stop across refreshes of the same journey, where
+// indices can shift when stops are added.
+func stopKey(stop models.Stop) string {
+	if stop.EVA != 0 {
+		return strconv.FormatInt(stop.EVA, 10)
+	}
+	return stop.Name
+}
+
+// bookmarkIdx returns the index of the bookmarked stop in the open journey,
+// or -1 if there is none.
+func (m Model) bookmarkIdx() int {
+	if m.journey == nil || m.bookmarkStop == "" || m.bookmarkJourneyID != m.selectedJourneyID {
+		return -1
+	}
+	for i, stop := range m.journey.Stops {
+		if stopKey(stop) == m.bookmarkStop {
+			return i
+		}
+	}
+	return -1
+}
+
+// toggleBookmark marks the stop under the journey cursor, or removes the
+// mark if that stop is already bookmarked.
+func (m Model) toggleBookmark() Model {
+	if m.journey == nil || m.journeyScroll < 0 || m.journeyScroll >= len(m.journey.Stops) {
+		return m
+	}
+	stop := m.journey.Stops[m.journeyScroll]
+	if m.bookmarkIdx() == m.journeyScroll {
+		m = m.clearBookmark()
+		m.notice = "Bookmark removed"
+		return m
+	}
+	m.bookmarkJourneyID = m.selectedJourneyID
+	m.bookmarkStop = stopKey(stop)
+	m.bookmarkAlerted = false
+	m.notice = "Bookmarked " + stop.Name
+	return m.checkBookmarkApproach()
+}
+
+// clearBookmark removes the journey bookmark.
+func (m Model) clearBookmark() Model {
+	m.bookmarkJourneyID = ""
+	m.bookmarkStop = ""
+	m.bookmarkAlerted = false
+	return m
+}
+
+// checkBookmarkApproach shows a one-time notice once the bookmarked stop is
+// the train's next stop.
+func (m Model) checkBookmarkApproach() Model {
+	idx := m.bookmarkIdx()
+	if idx <= 0 || m.bookmarkAlerted {
+		return m
+	}
+	if output.FindCurrentStopIndex(m.journey.Stops, m.now()) == idx-1 {
+		m.bookmarkAlerted = true
+		m.notice = "Next stop: " + m.journey.Stops[idx].Name + " (bookmarked)"
+	}
+	return m
+}
+
 // findBoardStationIdx returns the index of the stop that matches the board station,
 // or -1 if not found. Uses EVA matching first, then coordinate proximity as fallback.
 func findBoardStationIdx(stops []models.Stop, station *models.Location) int {
@@ -266,3 +408,81 @@ func findBoardStationIdx(stops []models.Stop, station *models.Location) int {
 
 	return -1
 }
+
+// newStopFilterInput creates the text input used to search journey stops.
+func newStopFilterInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "stop name"
+	ti.Prompt = "find: "
+	ti.CharLimit = 50
+	ti.Width = 30
+	return ti
+}
+
+// matchStopIdx returns the index of the first stop whose name contains query,
+// ignoring case, or -1 if none does.
+func matchStopIdx(stops []models.Stop, query string) int {
+	query = strings.ToLower(query)
+	for i, stop := range stops {
+		if strings.Contains(strings.ToLower(stop.Name), query) {
+			return i
+		}
+	}
+	return -1
+}
+
+// openStopFilter starts a stop search in the open journey, remembering the
+// scroll position to restore when the search is cleared.
+func (m Model) openStopFilter() (tea.Model, tea.Cmd) {
+	if m.journey == nil || len(m.journey.Stops) == 0 {
+		return m, nil
+	}
+	m.stopFilterOpen = true
+	m.stopFilterNoMatch = false
+	m.stopFilterScroll = m.journeyScroll
+	m.stopFilterManual = m.journeyManualScroll
+	m.stopFilterInput.SetValue("")
+	return m, m.stopFilterInput.Focus()
+}
+
+// closeStopFilter ends the stop search, keeping the current scroll position.
+func (m Model) closeStopFilter() Model {
+	m.stopFilterOpen = false
+	m.stopFilterNoMatch = false
+	m.stopFilterInput.Blur()
+	return m
+}
+
+// restoreStopFilterScroll returns to the scroll position from before the search.
+func (m Model) restoreStopFilterScroll() Model {
+	m.journeyScroll = m.stopFilterScroll
+	m.journeyManualScroll = m.stopFilterManual
+	return m
+}
+
+// handleStopFilterKeys handles key events while the stop search is open.
+// Enter keeps the matched stop, Esc restores the previous scroll.
+func (m Model) handleStopFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return m.restoreStopFilterScroll().closeStopFilter(), nil
+	case "enter":
+		return m.closeStopFilter(), nil
+	}
+
+	var cmd tea.Cmd
+	m.stopFilterInput, cmd = m.stopFilterInput.Update(msg)
+
+	query := strings.TrimSpace(m.stopFilterInput.Value())
+	m.stopFilterNoMatch = false
+	if query == "" || m.journey == nil {
+		return m.restoreStopFilterScroll(), cmd
+	}
+	if idx := matchStopIdx(m.journey.Stops, query); idx >= 0 {
+		m.journeyScroll = idx
+		m.journeyManualScroll = true
+	} else {
+		m.stopFilterNoMatch = true
+	}
+	return m, cmd
+}
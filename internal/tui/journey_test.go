@@ -0,0 +1,269 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mobil-koeln/moko-cli/internal/api"
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/output"
+)
+
+// newLargeJourneyModel returns a model showing a journey with n stops, the
+// current stop roughly in the middle.
+func newLargeJourneyModel(n int) Model {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.width = 160
+	m.height = 60
+	m.showJourney = true
+	m.focus = focusJourney
+
+	now := time.Now()
+	stops := make([]models.Stop, n)
+	for i := range stops {
+		t := now.Add(time.Duration(i-n/2) * 3 * time.Minute)
+		stops[i] = models.Stop{
+			EVA:      int64(8000000 + i),
+			Name:     fmt.Sprintf("Station %d", i),
+			Platform: fmt.Sprintf("%d", i%12+1),
+			Arr:      &t,
+			Dep:      &t,
+			Delay:    i % 7,
+		}
+	}
+	stops[n/3].IsCancelled = true
+	m.journey = &models.Journey{Name: "RE 1", Stops: stops}
+	m.selectedStation = &models.Location{EVA: int64(8000000 + n/4)}
+	return m
+}
+
+func BenchmarkRenderJourneyDetail(b *testing.B) {
+	for _, n := range []int{20, 80, 200} {
+		b.Run(fmt.Sprintf("stops=%d", n), func(b *testing.B) {
+			m := newLargeJourneyModel(n)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Simulate scrolling one stop per frame
+				m.journeyScroll = i % n
+				_ = m.renderJourneyDetail(100, 50)
+			}
+		})
+	}
+}
+
+func BenchmarkRenderJourneyDetail_Static(b *testing.B) {
+	m := newLargeJourneyModel(200)
+	m.journeyScroll = 100
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = m.renderJourneyDetail(100, 50)
+	}
+}
+
+func TestRenderJourneyDetail_CacheMatchesUncached(t *testing.T) {
+	cached := newLargeJourneyModel(60)
+	uncached := cached
+	uncached.journeyLines = nil
+
+	check := func(label string, width, height int) {
+		t.Helper()
+		if got, want := cached.renderJourneyDetail(width, height), uncached.renderJourneyDetail(width, height); got != want {
+			t.Errorf("%s: cached render differs from uncached render", label)
+		}
+	}
+
+	for _, scroll := range []int{0, 15, 30, 59, 30} {
+		cached.journeyScroll = scroll
+		uncached.journeyScroll = scroll
+		check(fmt.Sprintf("scroll=%d", scroll), 100, 40)
+	}
+
+	check("resized", 80, 30)
+
+	cached.showJourney = false
+	uncached.showJourney = false
+	check("journey hidden", 80, 30)
+
+	// A refetched journey is a new pointer and must not reuse stale lines
+	next := newLargeJourneyModel(60)
+	next.journey.Stops[10].IsCancelled = true
+	cached.journey = next.journey
+	uncached.journey = next.journey
+	check("new journey", 80, 30)
+}
+
+func TestRenderJourneyStopLine_AdditionalStop(t *testing.T) {
+	stop := models.Stop{Name: "Köln-Mülheim", IsAdditional: true}
+
+	line := renderJourneyStopLine(stop, stopLineState{}, 60)
+	if !strings.Contains(line, output.AdditionalStopNote) {
+		t.Errorf("additional stop line %q lacks %q", line, output.AdditionalStopNote)
+	}
+	if w := lipgloss.Width(line); w != 60 {
+		t.Errorf("line width = %d, want 60", w)
+	}
+
+	stop.IsAdditional = false
+	if line := renderJourneyStopLine(stop, stopLineState{}, 60); strings.Contains(line, output.AdditionalStopNote) {
+		t.Errorf("scheduled stop line %q has the extra stop note", line)
+	}
+}
+
+func TestRenderJourneyStopLine_EstimatedDelay(t *testing.T) {
+	stop := models.Stop{Name: "Koblenz Hbf"}
+
+	line := renderJourneyStopLine(stop, stopLineState{estDelay: 7}, 60)
+	if !strings.Contains(line, "~+7") || !strings.Contains(line, output.EstimatedDelayNote) {
+		t.Errorf("estimated stop line %q lacks the estimate", line)
+	}
+	if w := lipgloss.Width(line); w != 60 {
+		t.Errorf("line width = %d, want 60", w)
+	}
+
+	// Real-time delays win over estimates
+	stop.Delay = 3
+	if line := renderJourneyStopLine(stop, stopLineState{estDelay: 7}, 60); strings.Contains(line, output.EstimatedDelayNote) {
+		t.Errorf("stop with real-time delay %q is marked as estimated", line)
+	}
+}
+
+func TestRenderJourneyStopLine_Bookmarked(t *testing.T) {
+	stop := models.Stop{Name: "Mannheim Hbf"}
+
+	line := renderJourneyStopLine(stop, stopLineState{bookmarked: true}, 60)
+	if !strings.Contains(line, "★") {
+		t.Errorf("bookmarked stop line %q lacks the marker", line)
+	}
+	if w := lipgloss.Width(line); w != 60 {
+		t.Errorf("line width = %d, want 60", w)
+	}
+
+	// The current stop keeps its own indicator
+	if line := renderJourneyStopLine(stop, stopLineState{bookmarked: true, current: true}, 60); !strings.Contains(line, "●") {
+		t.Errorf("current bookmarked stop line %q lacks the current marker", line)
+	}
+}
+
+func TestRenderJourneyDetail_Clock(t *testing.T) {
+	at := func(h, m int) *time.Time {
+		ts := time.Date(2024, 1, 1, h, m, 0, 0, time.UTC)
+		return &ts
+	}
+	client, _ := api.NewClient()
+	m := New(client).WithClock(func() time.Time { return *at(14, 10) })
+	m.showJourney = true
+	m.journey = &models.Journey{
+		Name: "S 11",
+		Stops: []models.Stop{
+			{Name: "Köln Hbf", Dep: at(14, 0)},
+			{Name: "Köln-Mülheim", Arr: at(14, 6), Dep: at(14, 7)},
+			{Name: "Bergisch Gladbach", Arr: at(14, 25)},
+		},
+	}
+
+	out := m.renderJourneyDetail(80, 10)
+	if !strings.Contains(out, "● ├ 14:06") {
+		t.Errorf("current stop at the injected time not marked:\n%s", out)
+	}
+
+	// The same clock renders the same frame
+	m.journeyLines = &journeyLineCache{}
+	if again := m.renderJourneyDetail(80, 10); again != out {
+		t.Error("render with a fixed clock is not deterministic")
+	}
+}
+
+func TestStopFilter(t *testing.T) {
+	m := newLargeJourneyModel(40)
+	m.journey.Stops[25].Name = "Köln Messe/Deutz"
+	m.journeyScroll = 3
+
+	press := func(keys ...tea.KeyMsg) {
+		t.Helper()
+		for _, k := range keys {
+			next, _ := m.Update(k)
+			m = next.(Model)
+		}
+	}
+	typeText := func(s string) {
+		t.Helper()
+		for _, r := range s {
+			press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if !m.stopFilterOpen {
+		t.Fatal("f did not open the stop search")
+	}
+
+	typeText("MESSE")
+	if m.journeyScroll != 25 {
+		t.Errorf("journeyScroll = %d, want 25 (case-insensitive match)", m.journeyScroll)
+	}
+
+	typeText("xyz")
+	if !m.stopFilterNoMatch || !strings.Contains(m.renderStatusBar(), "no match") {
+		t.Error("status bar does not report a missing match")
+	}
+	if m.journeyScroll != 25 {
+		t.Errorf("journeyScroll = %d after a miss, want it unchanged at 25", m.journeyScroll)
+	}
+
+	// Esc clears the search and restores the previous position
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.stopFilterOpen || m.journeyScroll != 3 {
+		t.Errorf("after Esc: open=%v scroll=%d, want closed at 3", m.stopFilterOpen, m.journeyScroll)
+	}
+	if m.focus != focusJourney {
+		t.Errorf("focus = %v after Esc, want the journey", m.focus)
+	}
+
+	// Enter keeps the matched stop
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	typeText("station 1")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.stopFilterOpen || m.journeyScroll != 1 {
+		t.Errorf("after Enter: open=%v scroll=%d, want closed at 1", m.stopFilterOpen, m.journeyScroll)
+	}
+
+	// "/" still focuses the station search, as in the other views
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if m.stopFilterOpen || m.focus != focusSearch {
+		t.Errorf("after /: stop search open=%v focus=%v, want station search", m.stopFilterOpen, m.focus)
+	}
+}
+
+func TestRenderJourneyStopLine_CustomMarkers(t *testing.T) {
+	theme, err := output.DefaultTheme.WithMarkers(output.MarkerOverrides{Current: "◆", Scroll: ">", Bookmark: "*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	applyTheme(theme)
+	t.Cleanup(func() { applyTheme(output.DefaultTheme) })
+
+	stop := models.Stop{Name: "Mannheim Hbf"}
+	for _, tt := range []struct {
+		state stopLineState
+		want  string
+	}{
+		{stopLineState{current: true}, "◆"},
+		{stopLineState{scrolledTo: true, showJourney: true}, ">"},
+		{stopLineState{bookmarked: true}, "*"},
+	} {
+		line := renderJourneyStopLine(stop, tt.state, 60)
+		if !strings.Contains(line, tt.want) {
+			t.Errorf("stop line %q lacks marker %q", line, tt.want)
+		}
+		if w := lipgloss.Width(line); w != 60 {
+			t.Errorf("line width = %d, want 60", w)
+		}
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/mobil-koeln/moko-cli/internal/models"
 )
 
-// autoRefreshTickMsg is sent every 30 seconds when auto-refresh is enabled.
+// autoRefreshTickMsg is sent every refresh interval when auto-refresh is enabled.
 type autoRefreshTickMsg time.Time
 
 // countdownTickMsg is sent every second when auto-refresh is enabled to update countdown display.
@@ -33,3 +33,6 @@ type journeyResultMsg struct {
 	journey   *models.Journey
 	err       error
 }
+
+// noticeMsg carries a short feedback message for the status bar.
+type noticeMsg string
@@ -1,12 +1,14 @@
 package tui
 
 import (
+	"context"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mobil-koeln/moko-cli/internal/api"
 	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/output"
 )
 
 type focusPanel int
@@ -48,6 +50,7 @@ var modeLabels = []struct {
 // Model is the root Bubble Tea model for the TUI.
 type Model struct {
 	client *api.Client
+	clock  func() time.Time // source of "now" for positions and timestamps
 	width  int
 	height int
 
@@ -62,9 +65,19 @@ type Model struct {
 	boardMode   boardMode
 	boardCursor int
 
+	// Board query shifted with "+"/"-" (time) and "<"/">" (vias)
+	boardOffset time.Duration // added to now for the query time
+	boardVias   int
+
 	// Auto-refresh
-	autoRefresh bool
-	lastUpdate  time.Time
+	autoRefresh        bool
+	refreshInterval    time.Duration
+	journeyOnlyRefresh bool // refresh only the open journey, not the board
+	refreshCursor      int  // 0 = auto-refresh chip, 1 = journey-only chip
+	lastUpdate         time.Time
+	nextRefresh        time.Time // when the pending auto-refresh tick fires
+	refreshFailures    int       // consecutive refreshes failed by an outage
+	refreshBlocked     bool      // the last failure was bahn.de blocking us
 
 	// Left panel - stations
 	stations        []models.Location
@@ -79,6 +92,9 @@ type Model struct {
 	departureCursor   int
 	departuresLoading bool
 	departuresErr     error
+	departureSort     boardSort
+	groupByMode       bool   // rail departures first, then local transit
+	pinnedLine        string // line kept above the board ("*" toggles)
 
 	// Right panel - destination filter
 	destinationList    []string
@@ -93,6 +109,47 @@ type Model struct {
 	showJourney         bool
 	journeyScroll       int
 	journeyManualScroll bool // true when user has manually scrolled in journey view
+	journeyLines        *journeyLineCache
+	stripMap            bool // schematic strip instead of the geographic map ("v" toggles)
+	estimateDelays      bool // carry the last known delay to stops without real-time data
+
+	// Stop search in the open journey ("f"); Esc restores the scroll position
+	stopFilterOpen    bool
+	stopFilterInput   textinput.Model
+	stopFilterScroll  int  // journeyScroll before the search was opened
+	stopFilterManual  bool // journeyManualScroll before the search was opened
+	stopFilterNoMatch bool
+
+	// Bookmarked stop of the open journey ("m" toggles, "'" jumps to it)
+	bookmarkJourneyID string
+	bookmarkStop      string // stopKey of the bookmarked stop
+	bookmarkAlerted   bool   // approach notice already shown
+
+	// Sticky banner when the platform at the watched stop (bookmark, else
+	// board station) changes on refresh; "x" dismisses it
+	platformAlert     string
+	platformAlertStop string // stopKey of the stop the alert is about
+
+	// Journey prefetch for the highlighted departure (toggled with "p")
+	prefetch       bool
+	prefetchSeq    int
+	prefetchCancel context.CancelFunc
+	journeyCache   map[string]prefetchedJourney
+
+	// Saved stations, offered in the command palette
+	favorites []Favorite
+
+	// Command palette overlay (":" or Ctrl+P)
+	paletteOpen   bool
+	paletteInput  textinput.Model
+	paletteCursor int
+
+	// One-shot feedback shown in the status bar until the next key press
+	notice string
+
+	// Window title showing the station and next departure, when enabled
+	setTitle bool
+	title    string // last title set, "" if none
 }
 
 // New creates a new TUI model.
@@ -109,11 +166,74 @@ func New(client *api.Client) Model {
 	}
 
 	return Model{
-		client:      client,
-		searchInput: ti,
-		focus:       focusSearch,
-		modeFilters: filters,
+		client:          client,
+		clock:           time.Now,
+		searchInput:     ti,
+		focus:           focusSearch,
+		modeFilters:     filters,
+		boardVias:       defaultBoardVias,
+		refreshInterval: DefaultRefreshInterval,
+		journeyCache:    make(map[string]prefetchedJourney),
+		paletteInput:    newPaletteInput(),
+		stopFilterInput: newStopFilterInput(),
+		journeyLines:    &journeyLineCache{},
+	}
+}
+
+// Favorite is a saved station offered in the command palette
+type Favorite struct {
+	Name    string
+	Station models.Location
+}
+
+// WithFavorites returns the model with saved stations to jump to from the
+// command palette.
+func (m Model) WithFavorites(favs []Favorite) Model {
+	m.favorites = favs
+	return m
+}
+
+// WithRefreshInterval returns the model with a different auto-refresh
+// interval. Callers are expected to enforce a sensible minimum.
+func (m Model) WithRefreshInterval(d time.Duration) Model {
+	m.refreshInterval = d
+	return m
+}
+
+// WithEstimatedDelays returns the model showing the last known delay, marked
+// as an estimate, at journey stops without real-time data.
+func (m Model) WithEstimatedDelays(on bool) Model {
+	m.estimateDelays = on
+	return m
+}
+
+// WithTheme returns the model drawing in the palette of theme. The styles
+// are shared by the package, so this affects all models.
+func (m Model) WithTheme(theme output.Theme) Model {
+	applyTheme(theme)
+	return m
+}
+
+// WithTerminalTitle returns the model keeping the terminal window title set
+// to the selected station and its next departure, cleared again on quit.
+func (m Model) WithTerminalTitle(on bool) Model {
+	m.setTitle = on
+	return m
+}
+
+// WithClock returns the model with a different source of the current time,
+// e.g. a fixed time for deterministic rendering.
+func (m Model) WithClock(now func() time.Time) Model {
+	m.clock = now
+	return m
+}
+
+// now returns the current time from the model's clock.
+func (m Model) now() time.Time {
+	if m.clock == nil {
+		return time.Now()
 	}
+	return m.clock()
 }
 
 // selectedModes returns the API mode names for active filters.
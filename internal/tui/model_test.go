@@ -1,12 +1,15 @@
 package tui
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/mobil-koeln/moko-cli/internal/api"
 	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/output"
 	"github.com/mobil-koeln/moko-cli/internal/testutil"
 )
 
@@ -540,6 +543,36 @@ func TestAutoRefreshTickMsg(t *testing.T) {
 	testutil.AssertTrue(t, m.autoRefresh) // Still enabled
 }
 
+func TestAutoRefresh_JourneyOnly(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.autoRefresh = true
+	m.selectedStation = &models.Location{Name: "Frankfurt Hbf", EVA: 8000105, ID: "test-id"}
+	m.focus = focusAutoRefresh
+
+	// Move to the journey-only chip and toggle it
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	m = newModel.(Model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, m.journeyOnlyRefresh)
+	testutil.AssertTrue(t, m.autoRefresh) // auto-refresh chip untouched
+
+	// Without an open journey the board keeps refreshing
+	testutil.AssertFalse(t, m.journeyOnlyActive())
+
+	m.showJourney = true
+	m.selectedJourneyID = "j-1"
+	m.journey = &models.Journey{Name: "ICE 1"}
+	testutil.AssertTrue(t, m.journeyOnlyActive())
+	testutil.AssertContains(t, m.renderFilterBar(), "[Journey only]")
+
+	newModel, cmd := m.Update(autoRefreshTickMsg(time.Now()))
+	m = newModel.(Model)
+	testutil.AssertTrue(t, cmd != nil)
+	testutil.AssertTrue(t, m.autoRefresh)
+}
+
 func TestCountdownTickMsg(t *testing.T) {
 	client, _ := api.NewClient()
 	m := New(client)
@@ -563,3 +596,373 @@ func TestModel_QuitMsg(t *testing.T) {
 	newModel, _ := m.Update(tea.QuitMsg{})
 	testutil.AssertTrue(t, newModel != nil)
 }
+
+func TestDepartureKeys_SortCyclesAndFollowsCursor(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.focus = focusDepartures
+
+	base := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	t1, t2, t3 := base, base.Add(5*time.Minute), base.Add(10*time.Minute)
+	m.departures = []models.Departure{
+		{JourneyID: "a", Line: "S 8", SchedDep: &t1, Dep: &t1, Delay: 0},
+		{JourneyID: "b", Line: "ICE 5", SchedDep: &t2, Dep: &t2, Delay: 12},
+		{JourneyID: "c", Line: "RE 1", SchedDep: &t3, Dep: &t3, Delay: 3},
+	}
+	m.departureCursor = 2 // on journey "c"
+
+	sortKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}}
+
+	// time -> delay
+	newModel, _ := m.Update(sortKey)
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.departureSort, sortByDelay)
+	testutil.AssertEqual(t, m.departures[0].JourneyID, "b")
+	testutil.AssertEqual(t, m.departures[1].JourneyID, "c")
+	testutil.AssertEqual(t, m.departures[m.departureCursor].JourneyID, "c")
+
+	// delay -> line
+	newModel, _ = m.Update(sortKey)
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.departureSort, sortByLine)
+	testutil.AssertEqual(t, m.departures[0].JourneyID, "b")
+	testutil.AssertEqual(t, m.departures[m.departureCursor].JourneyID, "c")
+
+	// line -> time
+	newModel, _ = m.Update(sortKey)
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.departureSort, sortByTime)
+	testutil.AssertEqual(t, m.departures[0].JourneyID, "a")
+	testutil.AssertEqual(t, m.departureCursor, 2)
+}
+
+func TestDepartureKeys_PrefetchDebounceAndCache(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.focus = focusDepartures
+	m.departures = []models.Departure{
+		{JourneyID: "a", Line: "S 8"},
+		{JourneyID: "b", Line: "RE 1"},
+	}
+
+	// Prefetch is off by default: moving the cursor schedules nothing
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, cmd == nil)
+
+	// Enabling prefetch schedules a debounced fetch for the highlighted departure
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, m.prefetch)
+	testutil.AssertTrue(t, cmd != nil)
+	staleSeq := m.prefetchSeq
+
+	// Moving again invalidates the pending tick
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	m = newModel.(Model)
+	newModel, cmd = m.Update(prefetchTickMsg{seq: staleSeq, journeyID: "b"})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, cmd == nil)
+	testutil.AssertTrue(t, m.prefetchCancel == nil)
+
+	// A current tick starts a cancellable fetch
+	newModel, cmd = m.Update(prefetchTickMsg{seq: m.prefetchSeq, journeyID: "a"})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, cmd != nil)
+	testutil.AssertTrue(t, m.prefetchCancel != nil)
+
+	// The result is cached and Enter opens it without a request
+	journey := &models.Journey{ID: "a", Stops: []models.Stop{{Name: "Köln Hbf"}}}
+	newModel, _ = m.Update(prefetchResultMsg{journeyID: "a", journey: journey})
+	m = newModel.(Model)
+
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, cmd == nil)
+	testutil.AssertTrue(t, m.showJourney)
+	testutil.AssertFalse(t, m.journeyLoading)
+	testutil.AssertEqual(t, m.journey, journey)
+	testutil.AssertEqual(t, m.selectedJourneyID, "a")
+}
+
+func TestJourneyCache_ReopenWithoutRequest(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.focus = focusDepartures
+	m.departures = []models.Departure{{JourneyID: "a", Line: "S 8"}}
+
+	// Opening fetches the journey
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, cmd != nil)
+	journey := &models.Journey{ID: "a", Stops: []models.Stop{{Name: "Köln Hbf"}}}
+	newModel, _ = m.Update(journeyResultMsg{journeyID: "a", journey: journey})
+	m = newModel.(Model)
+
+	// Closing and reopening shows it again without a request
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	testutil.AssertFalse(t, m.showJourney)
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, cmd == nil)
+	testutil.AssertEqual(t, m.journey, journey)
+
+	// Stale entries are refetched
+	m.journeyCache["a"] = prefetchedJourney{journey: journey, fetchedAt: time.Now().Add(-2 * prefetchMaxAge)}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, cmd != nil)
+	testutil.AssertTrue(t, m.journeyLoading)
+}
+
+func TestJourneyCache_Bounded(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+
+	for i := 0; i < journeyCacheSize+5; i++ {
+		m = m.storeJourney(fmt.Sprintf("j%d", i), &models.Journey{})
+	}
+	testutil.AssertEqual(t, len(m.journeyCache), journeyCacheSize)
+
+	// The oldest entry is evicted first
+	m.journeyCache["old"] = prefetchedJourney{journey: &models.Journey{}, fetchedAt: time.Now().Add(-time.Second)}
+	delete(m.journeyCache, "j5")
+	m = m.storeJourney("new", &models.Journey{})
+	_, ok := m.journeyCache["old"]
+	testutil.AssertFalse(t, ok)
+	testutil.AssertEqual(t, len(m.journeyCache), journeyCacheSize)
+}
+
+func TestDepartureKeys_GroupByTransit(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.focus = focusDepartures
+
+	base := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	t1, t2, t3 := base, base.Add(5*time.Minute), base.Add(10*time.Minute)
+	m.departures = []models.Departure{
+		{JourneyID: "bus", Type: "Bus", Line: "Bus 136", SchedDep: &t1},
+		{JourneyID: "s", Type: "S", Line: "S 11", SchedDep: &t2},
+		{JourneyID: "tram", Type: "STR", Line: "STR 5", SchedDep: &t3},
+	}
+	m.departureCursor = 0 // on "bus"
+
+	groupKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}}
+
+	newModel, _ := m.Update(groupKey)
+	m = newModel.(Model)
+	testutil.AssertTrue(t, m.groupByMode)
+	testutil.AssertEqual(t, m.departures[0].JourneyID, "s")
+	testutil.AssertEqual(t, m.departures[1].JourneyID, "bus")
+	testutil.AssertEqual(t, m.departures[2].JourneyID, "tram")
+	testutil.AssertEqual(t, m.departures[m.departureCursor].JourneyID, "bus")
+
+	newModel, _ = m.Update(groupKey)
+	m = newModel.(Model)
+	testutil.AssertFalse(t, m.groupByMode)
+	testutil.AssertEqual(t, m.departures[0].JourneyID, "bus")
+	testutil.AssertEqual(t, m.departureCursor, 0)
+}
+
+func TestJourneyKeys_Bookmark(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.focus = focusJourney
+	m.showJourney = true
+
+	past := time.Now().Add(-time.Hour)
+	later := time.Now().Add(time.Hour)
+	journey := func() *models.Journey {
+		return &models.Journey{
+			ID:   "journey-123",
+			Name: "ICE 123",
+			Stops: []models.Stop{
+				{Name: "Frankfurt Hbf", EVA: 8000105, Dep: &past},
+				{Name: "Mannheim Hbf", EVA: 8000244, Arr: &later},
+				{Name: "Stuttgart Hbf", EVA: 8000096, Arr: &later},
+			},
+		}
+	}
+	m.selectedJourneyID = "journey-123"
+	m.journey = journey()
+	m.journeyScroll = 2
+
+	key := func(r rune) {
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(Model)
+	}
+
+	// Mark Stuttgart, scroll away and jump back
+	key('m')
+	testutil.AssertEqual(t, m.bookmarkIdx(), 2)
+	key('k')
+	key('k')
+	key('\'')
+	testutil.AssertEqual(t, m.journeyScroll, 2)
+
+	// A refresh of the same journey keeps the bookmark, even when a stop is added
+	refreshed := journey()
+	refreshed.Stops = append(refreshed.Stops[:1], append([]models.Stop{{Name: "Mainz Hbf", EVA: 8000240}}, refreshed.Stops[1:]...)...)
+	newModel, _ := m.Update(journeyResultMsg{journeyID: "journey-123", journey: refreshed})
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.bookmarkIdx(), 3)
+
+	// Toggling on the bookmarked stop removes it
+	m.journeyScroll = 3
+	key('m')
+	testutil.AssertEqual(t, m.bookmarkIdx(), -1)
+
+	// Opening a different journey clears the bookmark
+	key('m')
+	m.selectedJourneyID = "journey-456"
+	newModel, _ = m.Update(journeyResultMsg{journeyID: "journey-456", journey: journey()})
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.bookmarkStop, "")
+}
+
+func TestJourneyResult_BookmarkApproachNotice(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+
+	past := time.Now().Add(-time.Hour)
+	later := time.Now().Add(time.Hour)
+	m.selectedJourneyID = "journey-123"
+	m.bookmarkJourneyID = "journey-123"
+	m.bookmarkStop = "8000244"
+
+	msg := journeyResultMsg{
+		journeyID: "journey-123",
+		journey: &models.Journey{
+			ID: "journey-123",
+			Stops: []models.Stop{
+				{Name: "Frankfurt Hbf", EVA: 8000105, Dep: &past},
+				{Name: "Mannheim Hbf", EVA: 8000244, Arr: &later},
+			},
+		},
+	}
+	newModel, _ := m.Update(msg)
+	m = newModel.(Model)
+	testutil.AssertContains(t, m.notice, "Next stop: Mannheim Hbf")
+
+	// Only once
+	m.notice = ""
+	newModel, _ = m.Update(msg)
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.notice, "")
+}
+
+func TestJourneyResult_PlatformChangeAlert(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.width, m.height = 120, 40
+	m.selectedStation = &models.Location{Name: "Mannheim Hbf", EVA: 8000244}
+	m.selectedJourneyID = "journey-123"
+
+	past := time.Now().Add(-time.Hour)
+	later := time.Now().Add(time.Hour)
+	journey := func(platform string, mannheimDep *time.Time) *models.Journey {
+		return &models.Journey{
+			ID: "journey-123",
+			Stops: []models.Stop{
+				{Name: "Frankfurt Hbf", EVA: 8000105, Dep: &past},
+				{Name: "Mannheim Hbf", EVA: 8000244, Platform: "3", RTPlatform: platform, Arr: mannheimDep, Dep: mannheimDep},
+				{Name: "Stuttgart Hbf", EVA: 8000096, Arr: &later},
+			},
+		}
+	}
+	result := func(j *models.Journey) tea.Cmd {
+		newModel, cmd := m.Update(journeyResultMsg{journeyID: "journey-123", journey: j})
+		m = newModel.(Model)
+		return cmd
+	}
+
+	// Opening the journey and an unchanged refresh don't alert
+	testutil.AssertTrue(t, result(journey("", &later)) == nil)
+	testutil.AssertTrue(t, result(journey("", &later)) == nil)
+	testutil.AssertEqual(t, m.platformAlert, "")
+
+	// A platform change at the board station raises a sticky banner and rings
+	testutil.AssertTrue(t, result(journey("5", &later)) != nil)
+	testutil.AssertEqual(t, m.platformAlert, "Platform change at Mannheim Hbf: now Pl. 5 (was 3)")
+	testutil.AssertContains(t, m.View(), "now Pl. 5 (was 3)")
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.platformAlert, "Platform change at Mannheim Hbf: now Pl. 5 (was 3)")
+
+	// "x" acknowledges it
+	m.focus = focusJourney
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.platformAlert, "")
+
+	// It also clears once the train has left the stop
+	result(journey("7", &later))
+	testutil.AssertContains(t, m.platformAlert, "now Pl. 7 (was 5)")
+	result(journey("7", &past))
+	testutil.AssertEqual(t, m.platformAlert, "")
+}
+
+func TestDeparturesResult_DuplicateJourneyIDKeepsCursor(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.selectedStation = &models.Location{Name: "Hamm(Westf)Hbf", EVA: 8000149}
+
+	base := time.Now().Add(10 * time.Minute)
+	board := func() []models.Departure {
+		at := func(min int) *time.Time {
+			t := base.Add(time.Duration(min) * time.Minute)
+			return &t
+		}
+		// The split ICE is listed once per portion
+		return []models.Departure{
+			{JourneyID: "journey-1", Line: "RE 6", Destination: "Köln Hbf", Dep: at(0)},
+			{JourneyID: "journey-split", Line: "ICE 940", Destination: "Berlin Hbf", Dep: at(2)},
+			{JourneyID: "journey-2", Line: "RB 69", Destination: "Münster(Westf)Hbf", Dep: at(4)},
+			{JourneyID: "journey-split", Line: "ICE 950", Destination: "Hamburg Hbf", Dep: at(6)},
+		}
+	}
+	m.departures = board()
+	m.departureCursor = 3
+	m.selectedJourneyID = "journey-split"
+
+	newModel, _ := m.Update(departuresResultMsg{stationEVA: 8000149, departures: board()})
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.departureCursor, 3)
+
+	m.departureCursor = 1
+	newModel, _ = m.Update(departuresResultMsg{stationEVA: 8000149, departures: board()})
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.departureCursor, 1)
+
+	// With Köln hidden the cursor indexes the filtered list, where the
+	// Hamburg portion is the third row
+	for i, dest := range m.destinationList {
+		m.destinationFilters[i] = dest != "Köln Hbf"
+	}
+	m.departureCursor = 2
+	newModel, _ = m.Update(departuresResultMsg{stationEVA: 8000149, departures: board()})
+	m = newModel.(Model)
+	testutil.AssertEqual(t, m.departureCursor, 2)
+	testutil.AssertEqual(t, m.filteredDepartures()[m.departureCursor].Destination, "Hamburg Hbf")
+}
+
+func TestWithTheme(t *testing.T) {
+	t.Cleanup(func() { applyTheme(output.DefaultTheme) })
+	client, _ := api.NewClient()
+
+	mono, err := output.LookupTheme("mono")
+	testutil.AssertNil(t, err)
+	New(client).WithTheme(mono)
+	testutil.AssertTrue(t, styleCurrentStop.GetReverse())
+	testutil.AssertTrue(t, styleDelay.GetUnderline())
+	_, noBg := styleBookmark.GetBackground().(lipgloss.NoColor)
+	testutil.AssertTrue(t, noBg)
+
+	New(client).WithTheme(output.DefaultTheme)
+	testutil.AssertFalse(t, styleCurrentStop.GetReverse())
+	testutil.AssertEqual(t, styleBookmark.GetBackground(), lipgloss.TerminalColor(lipgloss.Color("3")))
+}
@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteAction is a named action that can be run from the command palette.
+type paletteAction struct {
+	name string
+	run  func(m Model) (tea.Model, tea.Cmd)
+}
+
+// paletteActions lists all command palette actions in display order.
+var paletteActions = []paletteAction{
+	{"Toggle departures/arrivals", func(m Model) (tea.Model, tea.Cmd) {
+		if m.boardMode == boardDeparture {
+			m.boardMode = boardArrival
+			m.boardCursor = 1
+		} else {
+			m.boardMode = boardDeparture
+			m.boardCursor = 0
+		}
+		return m.refetchBoard()
+	}},
+	{"Clear filters", func(m Model) (tea.Model, tea.Cmd) {
+		for i := range m.modeFilters {
+			m.modeFilters[i] = true
+		}
+		for i := range m.destinationFilters {
+			m.destinationFilters[i] = true
+		}
+		return m.refetchBoard()
+	}},
+	{"Reset board time and vias", func(m Model) (tea.Model, tea.Cmd) {
+		m.boardOffset = 0
+		m.boardVias = defaultBoardVias
+		return m.refetchBoard()
+	}},
+	{"Copy journey ID", func(m Model) (tea.Model, tea.Cmd) {
+		id := m.currentJourneyID()
+		if id == "" {
+			m.notice = "No journey selected"
+			return m, nil
+		}
+		return m, copyToClipboard(id, "Copied journey ID")
+	}},
+	{"Reload", func(m Model) (tea.Model, tea.Cmd) {
+		return m.reload()
+	}},
+	{"Toggle auto-refresh", func(m Model) (tea.Model, tea.Cmd) {
+		return m.toggleAutoRefresh()
+	}},
+	{"Toggle journey-only refresh", func(m Model) (tea.Model, tea.Cmd) {
+		return m.toggleJourneyOnlyRefresh(), nil
+	}},
+	{"Cycle sort order", func(m Model) (tea.Model, tea.Cmd) {
+		return m.cycleSort(), nil
+	}},
+	{"Toggle rail/local grouping", func(m Model) (tea.Model, tea.Cmd) {
+		return m.toggleGrouping(), nil
+	}},
+	{"Toggle strip/geographic map", func(m Model) (tea.Model, tea.Cmd) {
+		return m.toggleStripMap(), nil
+	}},
+	{"Toggle journey prefetch", func(m Model) (tea.Model, tea.Cmd) {
+		m.prefetch = !m.prefetch
+		return m.schedulePrefetch()
+	}},
+	{"Search station", func(m Model) (tea.Model, tea.Cmd) {
+		m.focus = focusSearch
+		m.searchInput.Focus()
+		return m, nil
+	}},
+	{"Quit", func(m Model) (tea.Model, tea.Cmd) {
+		return m, m.quit()
+	}},
+}
+
+// paletteActions returns the fixed actions followed by one action per
+// favorite station.
+func (m Model) paletteActions() []paletteAction {
+	actions := slices.Clip(paletteActions)
+	for _, fav := range m.favorites {
+		station := fav.Station
+		actions = append(actions, paletteAction{
+			name: "Go to @" + fav.Name + " (" + station.Name + ")",
+			run: func(m Model) (tea.Model, tea.Cmd) {
+				m.focus = focusDepartures
+				m.searchInput.Blur()
+				return m.selectStation(station)
+			},
+		})
+	}
+	return actions
+}
+
+// newPaletteInput creates the text input used to filter palette actions.
+func newPaletteInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Type a command..."
+	ti.Prompt = ": "
+	ti.CharLimit = 50
+	ti.Width = 40
+	return ti
+}
+
+// fuzzyScore matches query as a case-insensitive subsequence of target.
+// Lower scores are better; consecutive and early matches score lower.
+func fuzzyScore(query, target string) (int, bool) {
+	query = strings.ToLower(query)
+	target = strings.ToLower(target)
+	if query == "" {
+		return 0, true
+	}
+
+	score := 0
+	last := -1
+	qi := 0
+	q := []rune(query)
+	for ti, r := range []rune(target) {
+		if qi < len(q) && r == q[qi] {
+			if last >= 0 {
+				score += ti - last - 1 // gap since previous match
+			} else {
+				score += ti // distance from start
+			}
+			last = ti
+			qi++
+		}
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
+
+// filteredPaletteActions returns the actions matching the palette query, best match first.
+func (m Model) filteredPaletteActions() []paletteAction {
+	query := strings.TrimSpace(m.paletteInput.Value())
+
+	type scored struct {
+		action paletteAction
+		score  int
+	}
+	var matches []scored
+	for _, a := range m.paletteActions() {
+		if score, ok := fuzzyScore(query, a.name); ok {
+			matches = append(matches, scored{a, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score < matches[j].score
+	})
+
+	result := make([]paletteAction, len(matches))
+	for i, s := range matches {
+		result[i] = s.action
+	}
+	return result
+}
+
+// openPalette shows the command palette with an empty query.
+func (m Model) openPalette() (tea.Model, tea.Cmd) {
+	m.paletteOpen = true
+	m.paletteCursor = 0
+	m.paletteInput.SetValue("")
+	return m, m.paletteInput.Focus()
+}
+
+// closePalette hides the command palette.
+func (m Model) closePalette() Model {
+	m.paletteOpen = false
+	m.paletteInput.Blur()
+	return m
+}
+
+// handlePaletteKeys handles key events while the command palette is open.
+func (m Model) handlePaletteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	actions := m.filteredPaletteActions()
+
+	switch msg.String() {
+	case "esc":
+		return m.closePalette(), nil
+
+	case "up", "ctrl+k":
+		if m.paletteCursor > 0 {
+			m.paletteCursor--
+		}
+		return m, nil
+
+	case "down", "ctrl+j":
+		if m.paletteCursor < len(actions)-1 {
+			m.paletteCursor++
+		}
+		return m, nil
+
+	case "enter":
+		if m.paletteCursor >= len(actions) {
+			return m, nil
+		}
+		action := actions[m.paletteCursor]
+		return action.run(m.closePalette())
+	}
+
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.paletteCursor = 0
+	return m, cmd
+}
+
+// renderPalette renders the command palette box.
+func (m Model) renderPalette(width int) string {
+	var b strings.Builder
+	b.WriteString(styleHeader.Render("COMMANDS"))
+	b.WriteString("\n")
+	b.WriteString(m.paletteInput.View())
+	b.WriteString("\n")
+
+	actions := m.filteredPaletteActions()
+	if len(actions) == 0 {
+		b.WriteString(styleMuted.Render(" No matching commands"))
+	}
+	for i, a := range actions {
+		if i == m.paletteCursor {
+			b.WriteString(styleSelected.Render("▶ " + a.name))
+		} else {
+			b.WriteString("  " + a.name)
+		}
+		if i < len(actions)-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	return stylePanelFocused.Width(width).Render(b.String())
+}
+
+// overlayPalette places the palette box over an area of the given size.
+func (m Model) overlayPalette(width, height int) string {
+	boxWidth := 50
+	if boxWidth > width-4 {
+		boxWidth = width - 4
+	}
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Top, m.renderPalette(boxWidth))
+}
+
+// currentJourneyID returns the open journey, or the highlighted departure's journey.
+func (m Model) currentJourneyID() string {
+	if m.showJourney && m.selectedJourneyID != "" {
+		return m.selectedJourneyID
+	}
+	deps := m.filteredDepartures()
+	if m.departureCursor >= 0 && m.departureCursor < len(deps) {
+		return deps[m.departureCursor].JourneyID
+	}
+	return ""
+}
+
+// copyToClipboard returns a tea.Cmd that writes text to the system clipboard.
+func copyToClipboard(text, success string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(text); err != nil {
+			return noticeMsg("Copy failed: " + err.Error())
+		}
+		return noticeMsg(success)
+	}
+}
@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mobil-koeln/moko-cli/internal/api"
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query  string
+		target string
+		wantOK bool
+	}{
+		{"", "Reload", true},
+		{"rel", "Reload", true},
+		{"RLD", "Reload", true},
+		{"cji", "Copy journey ID", true},
+		{"xyz", "Reload", false},
+		{"dlr", "Reload", false}, // order matters
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query+"/"+tt.target, func(t *testing.T) {
+			_, ok := fuzzyScore(tt.query, tt.target)
+			testutil.AssertEqual(t, ok, tt.wantOK)
+		})
+	}
+
+	// Contiguous matches rank ahead of scattered ones
+	tight, _ := fuzzyScore("sort", "Cycle sort order")
+	loose, _ := fuzzyScore("sort", "Search station or things")
+	testutil.AssertTrue(t, tight < loose)
+}
+
+func TestPalette_OpenFilterRun(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.focus = focusDepartures
+
+	// ":" opens the palette outside the search box
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, m.paletteOpen)
+	testutil.AssertEqual(t, len(m.filteredPaletteActions()), len(paletteActions))
+
+	// Typing filters the action list
+	for _, r := range "arriv" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(Model)
+	}
+	actions := m.filteredPaletteActions()
+	testutil.AssertTrue(t, len(actions) > 0)
+	testutil.AssertEqual(t, actions[0].name, "Toggle departures/arrivals")
+
+	// Enter runs the selected action and closes the palette
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	testutil.AssertFalse(t, m.paletteOpen)
+	testutil.AssertEqual(t, m.boardMode, boardArrival)
+	testutil.AssertEqual(t, m.focus, focusDepartures)
+}
+
+func TestPalette_SearchFocus(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+
+	// ":" is typed into the search box, Ctrl+P opens the palette
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}})
+	m = newModel.(Model)
+	testutil.AssertFalse(t, m.paletteOpen)
+	testutil.AssertEqual(t, m.searchInput.Value(), ":")
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, m.paletteOpen)
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	testutil.AssertFalse(t, m.paletteOpen)
+	testutil.AssertEqual(t, m.searchInput.Value(), ":")
+}
+
+func TestPalette_CopyJourneyIDWithoutSelection(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.focus = focusStations
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = newModel.(Model)
+	for _, r := range "copy" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(Model)
+	}
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, cmd == nil)
+	testutil.AssertEqual(t, m.notice, "No journey selected")
+}
+
+func TestPalette_Favorites(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client).WithFavorites([]Favorite{
+		{Name: "home", Station: models.Location{EVA: 8000207, ID: "A=1@O=Köln Hbf@L=8000207@", Name: "Köln Hbf"}},
+	})
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = newModel.(Model)
+	testutil.AssertEqual(t, len(m.filteredPaletteActions()), len(paletteActions)+1)
+	for _, r := range "@home" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(Model)
+	}
+	testutil.AssertEqual(t, m.filteredPaletteActions()[0].name, "Go to @home (Köln Hbf)")
+
+	// Running it opens the station's board
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	testutil.AssertTrue(t, cmd != nil)
+	testutil.AssertFalse(t, m.paletteOpen)
+	testutil.AssertEqual(t, m.selectedStation.EVA, int64(8000207))
+	testutil.AssertTrue(t, m.departuresLoading)
+	testutil.AssertEqual(t, m.focus, focusDepartures)
+}
+
+func TestRenderPalette(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.width = 100
+	m.height = 40
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = newModel.(Model)
+
+	output := m.View()
+	testutil.AssertContains(t, output, "COMMANDS")
+	testutil.AssertContains(t, output, "Clear filters")
+	testutil.AssertContains(t, output, "Esc:close")
+}
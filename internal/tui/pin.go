@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+// maxPinnedRows is the number of upcoming departures of the pinned line shown
+// above the board
+const maxPinnedRows = 2
+
+// departureLabel returns the line label a departure is pinned by
+func departureLabel(dep models.Departure) string {
+	if dep.Line != "" {
+		return dep.Line
+	}
+	return dep.TrainShort
+}
+
+// togglePin pins the line of the highlighted departure, or clears the pin
+// when one is set
+func (m Model) togglePin() Model {
+	if m.pinnedLine != "" {
+		m.pinnedLine = ""
+		return m
+	}
+	deps := m.filteredDepartures()
+	if m.departureCursor >= 0 && m.departureCursor < len(deps) {
+		m.pinnedLine = departureLabel(deps[m.departureCursor])
+	}
+	return m
+}
+
+// pinnedDepartures returns the next departures of the pinned line in time
+// order, whatever the board is sorted by
+func (m Model) pinnedDepartures() []models.Departure {
+	if m.pinnedLine == "" {
+		return nil
+	}
+	var pinned []models.Departure
+	for _, dep := range m.filteredDepartures() {
+		if departureLabel(dep) == m.pinnedLine {
+			pinned = append(pinned, dep)
+		}
+	}
+	sortDepartures(pinned, sortByTime)
+	if len(pinned) > maxPinnedRows {
+		pinned = pinned[:maxPinnedRows]
+	}
+	return pinned
+}
+
+// renderPinned renders the fixed rows of the pinned line above the board,
+// closed by a rule. It returns no lines when nothing is pinned.
+func (m Model) renderPinned(width int) []string {
+	if m.pinnedLine == "" {
+		return nil
+	}
+	lines := []string{styleMuted.Render(" 📌 " + m.pinnedLine)}
+	pinned := m.pinnedDepartures()
+	for _, dep := range pinned {
+		lines = append(lines, renderDepartureLine(dep, width, false))
+	}
+	if len(pinned) == 0 {
+		lines = append(lines, styleMuted.Render("   No departures of this line on the board"))
+	}
+	return append(lines, styleMuted.Render(strings.Repeat("─", max(width, 0))))
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mobil-koeln/moko-cli/internal/api"
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func newPinModel() Model {
+	client, _ := api.NewClient()
+	m := New(client)
+	m.width = 100
+	m.height = 12
+	m.focus = focusDepartures
+	m.selectedStation = &models.Location{Name: "Köln Hbf", EVA: 8000207}
+
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 20 {
+		dep := base.Add(time.Duration(i) * time.Minute)
+		line := "RE 1"
+		if i == 3 || i == 15 || i == 18 {
+			line = "S 11"
+		}
+		m.departures = append(m.departures, models.Departure{
+			JourneyID: line + dep.Format("1504"), Line: line, Dep: &dep, SchedDep: &dep, Destination: "Düsseldorf",
+		})
+	}
+	return m
+}
+
+func TestTogglePin(t *testing.T) {
+	m := newPinModel()
+	m.departureCursor = 3
+
+	updated, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")})
+	m = updated.(Model)
+	testutil.AssertEqual(t, m.pinnedLine, "S 11")
+
+	// The next two departures of the line, in time order
+	pinned := m.pinnedDepartures()
+	testutil.AssertLen(t, pinned, maxPinnedRows)
+	testutil.AssertEqual(t, pinned[0].Dep.Minute(), 3)
+	testutil.AssertEqual(t, pinned[1].Dep.Minute(), 15)
+
+	// The same key clears the pin
+	updated, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")})
+	m = updated.(Model)
+	testutil.AssertEqual(t, m.pinnedLine, "")
+
+	// Esc clears the pin before leaving the board
+	m.pinnedLine = "S 11"
+	updated, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	testutil.AssertEqual(t, m.pinnedLine, "")
+	testutil.AssertEqual(t, m.focus, focusDepartures)
+}
+
+func TestRenderDepartureList_Pinned(t *testing.T) {
+	m := newPinModel()
+	m.pinnedLine = "S 11"
+	m.departureCursor = 0
+
+	out := m.renderDepartureList(80, 10)
+	lines := strings.Split(out, "\n")
+	testutil.AssertEqual(t, len(lines), 9) // same height as without a pin
+	testutil.AssertContains(t, lines[0], "[pin: S 11]")
+	testutil.AssertContains(t, lines[1], "S 11")
+	testutil.AssertContains(t, lines[2], "12:03")
+	testutil.AssertContains(t, lines[3], "12:15")
+
+	// The pinned rows stay when the list scrolls past them
+	m.departureCursor = 10
+	out = m.renderDepartureList(80, 10)
+	testutil.AssertContains(t, out, "12:03")
+	testutil.AssertContains(t, out, "12:10")
+
+	// A line without departures says so
+	m.pinnedLine = "U 18"
+	testutil.AssertContains(t, m.renderDepartureList(80, 10), "No departures of this line")
+}
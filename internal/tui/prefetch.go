@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mobil-koeln/moko-cli/internal/api"
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+const (
+	// prefetchDelay debounces prefetches while the cursor is moving.
+	prefetchDelay = 300 * time.Millisecond
+	// prefetchMaxAge is how long a prefetched or recently opened journey may be
+	// shown without refetching.
+	prefetchMaxAge = 60 * time.Second
+	// journeyCacheSize bounds the number of journeys kept in memory.
+	journeyCacheSize = 16
+)
+
+// prefetchedJourney is a journey fetched in the background for the highlighted
+// departure, or one that was recently opened.
+type prefetchedJourney struct {
+	journey   *models.Journey
+	fetchedAt time.Time
+}
+
+// prefetchTickMsg fires once the cursor has rested on a departure for prefetchDelay.
+type prefetchTickMsg struct {
+	seq       int
+	journeyID string
+}
+
+// prefetchResultMsg carries a background journey fetch back to the model.
+type prefetchResultMsg struct {
+	journeyID string
+	journey   *models.Journey
+	err       error
+}
+
+// prefetchJourney returns a tea.Cmd that fetches a journey with a cancellable context.
+func prefetchJourney(ctx context.Context, client *api.Client, journeyID string, withPolyline bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, apiTimeout)
+		defer cancel()
+
+		journey, err := client.GetJourney(ctx, journeyID, withPolyline)
+		return prefetchResultMsg{
+			journeyID: journeyID,
+			journey:   journey,
+			err:       err,
+		}
+	}
+}
+
+// cachedJourney returns a fresh prefetched journey for the given ID, if any.
+func (m Model) cachedJourney(journeyID string) (*models.Journey, bool) {
+	entry, ok := m.journeyCache[journeyID]
+	if !ok || time.Since(entry.fetchedAt) > prefetchMaxAge {
+		return nil, false
+	}
+	return entry.journey, true
+}
+
+// cancelPrefetch aborts any in-flight prefetch and invalidates pending ticks.
+func (m Model) cancelPrefetch() Model {
+	m.prefetchSeq++
+	if m.prefetchCancel != nil {
+		m.prefetchCancel()
+		m.prefetchCancel = nil
+	}
+	return m
+}
+
+// schedulePrefetch cancels the previous prefetch and, if enabled, schedules
+// a debounced fetch of the journey under the departure cursor.
+func (m Model) schedulePrefetch() (Model, tea.Cmd) {
+	m = m.cancelPrefetch()
+	if !m.prefetch {
+		return m, nil
+	}
+
+	deps := m.filteredDepartures()
+	if m.departureCursor < 0 || m.departureCursor >= len(deps) {
+		return m, nil
+	}
+	journeyID := deps[m.departureCursor].JourneyID
+	if journeyID == "" {
+		return m, nil
+	}
+	if _, ok := m.cachedJourney(journeyID); ok {
+		return m, nil
+	}
+
+	seq := m.prefetchSeq
+	return m, tea.Tick(prefetchDelay, func(time.Time) tea.Msg {
+		return prefetchTickMsg{seq: seq, journeyID: journeyID}
+	})
+}
+
+func (m Model) handlePrefetchTick(msg prefetchTickMsg) (tea.Model, tea.Cmd) {
+	// Cursor moved (or prefetch was disabled) since this tick was scheduled
+	if !m.prefetch || msg.seq != m.prefetchSeq {
+		return m, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.prefetchCancel = cancel
+	return m, prefetchJourney(ctx, m.client, msg.journeyID, m.routeMapVisible())
+}
+
+func (m Model) handlePrefetchResult(msg prefetchResultMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil || msg.journey == nil {
+		return m, nil
+	}
+	return m.storeJourney(msg.journeyID, msg.journey), nil
+}
+
+// storeJourney caches a freshly fetched journey so reopening it is instant.
+// When the cache is full, expired entries and then the oldest entry are evicted.
+func (m Model) storeJourney(journeyID string, journey *models.Journey) Model {
+	if m.journeyCache == nil {
+		m.journeyCache = make(map[string]prefetchedJourney)
+	}
+	if _, ok := m.journeyCache[journeyID]; !ok && len(m.journeyCache) >= journeyCacheSize {
+		var oldestID string
+		var oldest time.Time
+		for id, entry := range m.journeyCache {
+			if time.Since(entry.fetchedAt) > prefetchMaxAge {
+				delete(m.journeyCache, id)
+				continue
+			}
+			if oldestID == "" || entry.fetchedAt.Before(oldest) {
+				oldestID, oldest = id, entry.fetchedAt
+			}
+		}
+		if len(m.journeyCache) >= journeyCacheSize {
+			delete(m.journeyCache, oldestID)
+		}
+	}
+	m.journeyCache[journeyID] = prefetchedJourney{journey: journey, fetchedAt: time.Now()}
+	return m
+}
@@ -25,12 +25,35 @@ type mapCell struct {
 	ctype mapCellType
 }
 
+// routeMapVisible reports whether the journey view has room for the
+// geographic route map, so journeys are only fetched with their polyline when
+// it is drawn. The strip map does not need one.
+func (m Model) routeMapVisible() bool {
+	return m.width > 0 && m.height > 0 && !m.stripMap
+}
+
+// toggleStripMap switches the map panel between the geographic map and the
+// schematic strip. The choice sticks for later journeys.
+func (m Model) toggleStripMap() Model {
+	m.stripMap = !m.stripMap
+	if m.stripMap {
+		m.notice = "Map: schematic strip"
+	} else {
+		m.notice = "Map: geographic"
+	}
+	return m
+}
+
 // renderRouteMap renders a dots-only geographic map of the journey route.
 // currentIdx is the time-based current stop; selectedIdx is the user's cursor position;
 // boardStationIdx is the station from which the departure board was queried (green).
-func renderRouteMap(stops []models.Stop, currentIdx, selectedIdx, boardStationIdx, width, height int) string {
+// When path holds the journey's polyline the route follows the track geometry;
+// otherwise stops are joined by straight lines.
+// When the panel is too small or there are no coordinates at all, a short
+// placeholder label is rendered instead so the panel does not look broken.
+func renderRouteMap(stops []models.Stop, path []models.Coord, currentIdx, selectedIdx, boardStationIdx, width, height int) string {
 	if len(stops) == 0 || width < 3 || height < 3 {
-		return ""
+		return mapPlaceholder(mapUnavailableText, width, height)
 	}
 
 	// Filter stops with valid coordinates
@@ -44,25 +67,33 @@ func renderRouteMap(stops []models.Stop, currentIdx, selectedIdx, boardStationId
 			valid = append(valid, stopEntry{index: i, stop: s})
 		}
 	}
-	if len(valid) == 0 {
-		return ""
+	if len(path) < 2 {
+		path = nil
+	}
+	if len(valid) == 0 && path == nil {
+		return mapPlaceholder(mapNoGeodataText, width, height)
 	}
 
-	// Compute bounding box
-	minLat, maxLat := valid[0].stop.Lat, valid[0].stop.Lat
-	minLon, maxLon := valid[0].stop.Lon, valid[0].stop.Lon
-	for _, v := range valid[1:] {
-		if v.stop.Lat < minLat {
-			minLat = v.stop.Lat
+	// Compute bounding box over stops and route geometry
+	coords := make([]models.Coord, 0, len(valid)+len(path))
+	for _, v := range valid {
+		coords = append(coords, models.Coord{Lat: v.stop.Lat, Lon: v.stop.Lon})
+	}
+	coords = append(coords, path...)
+	minLat, maxLat := coords[0].Lat, coords[0].Lat
+	minLon, maxLon := coords[0].Lon, coords[0].Lon
+	for _, c := range coords[1:] {
+		if c.Lat < minLat {
+			minLat = c.Lat
 		}
-		if v.stop.Lat > maxLat {
-			maxLat = v.stop.Lat
+		if c.Lat > maxLat {
+			maxLat = c.Lat
 		}
-		if v.stop.Lon < minLon {
-			minLon = v.stop.Lon
+		if c.Lon < minLon {
+			minLon = c.Lon
 		}
-		if v.stop.Lon > maxLon {
-			maxLon = v.stop.Lon
+		if c.Lon > maxLon {
+			maxLon = c.Lon
 		}
 	}
 
@@ -113,10 +144,9 @@ func renderRouteMap(stops []models.Stop, currentIdx, selectedIdx, boardStationId
 		col int
 		row int
 	}
-	points := make([]gridPoint, len(valid))
-	for i, v := range valid {
-		col := int(math.Round((v.stop.Lon-minLon)*scale + xOffset))
-		row := int(math.Round((maxLat-v.stop.Lat)*scale/2.0 + yOffset))
+	project := func(lat, lon float64) gridPoint {
+		col := int(math.Round((lon-minLon)*scale + xOffset))
+		row := int(math.Round((maxLat-lat)*scale/2.0 + yOffset))
 		if col < 0 {
 			col = 0
 		}
@@ -129,7 +159,18 @@ func renderRouteMap(stops []models.Stop, currentIdx, selectedIdx, boardStationId
 		if row >= height {
 			row = height - 1
 		}
-		points[i] = gridPoint{col: col, row: row}
+		return gridPoint{col: col, row: row}
+	}
+	points := make([]gridPoint, len(valid))
+	for i, v := range valid {
+		points[i] = project(v.stop.Lat, v.stop.Lon)
+	}
+	route := points
+	if path != nil {
+		route = make([]gridPoint, len(path))
+		for i, c := range path {
+			route[i] = project(c.Lat, c.Lon)
+		}
 	}
 
 	// Create grid
@@ -141,9 +182,9 @@ func renderRouteMap(stops []models.Stop, currentIdx, selectedIdx, boardStationId
 		}
 	}
 
-	// Draw route lines between consecutive stops
-	for i := 0; i < len(points)-1; i++ {
-		bresenhamLine(grid, points[i].col, points[i].row, points[i+1].col, points[i+1].row)
+	// Draw the route along the polyline, or straight between consecutive stops
+	for i := 0; i < len(route)-1; i++ {
+		bresenhamLine(grid, route[i].col, route[i].row, route[i+1].col, route[i+1].row)
 	}
 
 	// Place stop markers — priority: current (red) > board station (green) > scroll cursor > temporal
@@ -216,6 +257,29 @@ func renderRouteMap(stops []models.Stop, currentIdx, selectedIdx, boardStationId
 	return b.String()
 }
 
+// Placeholder labels for the map panel
+const (
+	mapUnavailableText = "map unavailable"
+	mapNoGeodataText   = "no geodata"
+)
+
+// mapPlaceholder renders text centered in a width x height area, truncated to
+// fit. It returns "" when there is no room at all.
+func mapPlaceholder(text string, width, height int) string {
+	if width < 1 || height < 1 {
+		return ""
+	}
+	if len(text) > width {
+		text = text[:width]
+	}
+
+	pad := (width - len(text)) / 2
+	label := styleMuted.Render(strings.Repeat(" ", pad) + text)
+	lines := make([]string, height)
+	lines[(height-1)/2] = label
+	return strings.Join(lines, "\n")
+}
+
 // bresenhamLine draws a line between two points on the grid using Bresenham's algorithm.
 func bresenhamLine(grid [][]mapCell, x0, y0, x1, y1 int) {
 	dx := x1 - x0
@@ -258,3 +322,91 @@ func bresenhamLine(grid [][]mapCell, x0, y0, x1, y1 int) {
 		}
 	}
 }
+
+// hasGeodata reports whether the geographic map has anything to place: a
+// route polyline or at least one stop with coordinates.
+func hasGeodata(stops []models.Stop, path []models.Coord) bool {
+	if len(path) >= 2 {
+		return true
+	}
+	for _, s := range stops {
+		if s.Lat != 0 || s.Lon != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// renderStripMap renders a schematic "beads on a string" map of the journey:
+// one bead per stop, evenly spaced from top to bottom, using the same markers
+// and colors as renderRouteMap. It needs no coordinates. Stops are joined by
+// a connector line when there is room; otherwise each stop takes one line and
+// the strip scrolls to keep selectedIdx in view.
+func renderStripMap(stops []models.Stop, currentIdx, selectedIdx, boardStationIdx, width, height int) string {
+	if len(stops) == 0 || width < 3 || height < 1 {
+		return mapPlaceholder(mapUnavailableText, width, height)
+	}
+
+	pastStyle := lipgloss.NewStyle().Foreground(colorGray)
+	currentStyle := lipgloss.NewStyle().Foreground(colorRed).Bold(true)
+	futureStyle := lipgloss.NewStyle().Foreground(colorCyan).Bold(true)
+	selectedStyle := lipgloss.NewStyle().Foreground(colorRed).Bold(true)
+	boardStationStyle := lipgloss.NewStyle().Foreground(colorGreen).Bold(true)
+
+	bead := func(i int) string {
+		switch {
+		case i == currentIdx:
+			return currentStyle.Render("◉")
+		case i == boardStationIdx:
+			return boardStationStyle.Render("●")
+		case i == selectedIdx:
+			return selectedStyle.Render("◆")
+		case i < currentIdx:
+			return pastStyle.Render("○")
+		default:
+			return futureStyle.Render("●")
+		}
+	}
+	// The connector above stop i has been travelled once the train reached it
+	connector := func(i int) string {
+		if i <= currentIdx {
+			return pastStyle.Render("┆")
+		}
+		return styleMuted.Render("│")
+	}
+
+	nameWidth := width - 3
+	var lines []string
+	if 2*len(stops)-1 <= height {
+		for i, s := range stops {
+			if i > 0 {
+				lines = append(lines, " "+connector(i))
+			}
+			lines = append(lines, " "+bead(i)+" "+stripStopName(s, i == currentIdx, nameWidth))
+		}
+	} else {
+		start, end := visibleRange(selectedIdx, len(stops), height)
+		for i := start; i < end; i++ {
+			lines = append(lines, " "+bead(i)+" "+stripStopName(stops[i], i == currentIdx, nameWidth))
+		}
+	}
+
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stripStopName renders a stop label for the strip map, bold at the current
+// stop and struck through when cancelled
+func stripStopName(s models.Stop, current bool, width int) string {
+	name := truncate(s.Name, width)
+	switch {
+	case s.IsCancelled:
+		return styleMuted.Strikethrough(true).Render(name)
+	case current:
+		return lipgloss.NewStyle().Bold(true).Render(name)
+	default:
+		return name
+	}
+}
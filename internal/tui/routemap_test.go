@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestRenderRouteMap_NoCoordinates(t *testing.T) {
+	stops := []models.Stop{{Name: "Stop A"}, {Name: "Stop B"}, {Name: "Stop C"}}
+
+	out := renderRouteMap(stops, nil, 1, 1, -1, 30, 7)
+
+	testutil.AssertContains(t, out, mapNoGeodataText)
+	testutil.AssertEqual(t, strings.Count(out, "\n"), 6)
+}
+
+func TestRenderRouteMap_TooSmall(t *testing.T) {
+	stops := []models.Stop{{Name: "Stop A", Lat: 50.9, Lon: 6.9}, {Name: "Stop B", Lat: 51.0, Lon: 7.0}}
+
+	testutil.AssertContains(t, renderRouteMap(stops, nil, 0, 0, -1, 20, 2), mapUnavailableText)
+	// Labels are truncated to the panel width
+	testutil.AssertContains(t, renderRouteMap(stops, nil, 0, 0, -1, 2, 2), "ma")
+	testutil.AssertEqual(t, renderRouteMap(stops, nil, 0, 0, -1, 0, 0), "")
+}
+
+func TestRenderRouteMap_WithCoordinates(t *testing.T) {
+	stops := []models.Stop{{Name: "Stop A", Lat: 50.9, Lon: 6.9}, {Name: "Stop B", Lat: 51.0, Lon: 7.0}}
+
+	out := renderRouteMap(stops, nil, 0, 0, -1, 20, 8)
+
+	testutil.AssertFalse(t, strings.Contains(out, mapNoGeodataText))
+	testutil.AssertContains(t, out, "◉")
+}
+
+// pathRowsAbove counts map rows above the current-stop marker that contain route dots.
+func pathRowsAbove(out string) int {
+	rows := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "◉") {
+			break
+		}
+		if strings.Contains(line, "·") {
+			rows++
+		}
+	}
+	return rows
+}
+
+func TestRenderRouteMap_FollowsPolyline(t *testing.T) {
+	// Two stops on the same latitude; the track bends north between them
+	stops := []models.Stop{{Name: "Stop A", Lat: 50.9, Lon: 6.9}, {Name: "Stop B", Lat: 50.9, Lon: 7.0}}
+	path := []models.Coord{{Lat: 50.9, Lon: 6.9}, {Lat: 51.0, Lon: 6.95}, {Lat: 50.9, Lon: 7.0}}
+
+	straight := renderRouteMap(stops, nil, 0, 0, -1, 30, 10)
+	curved := renderRouteMap(stops, path, 0, 0, -1, 30, 10)
+
+	testutil.AssertEqual(t, pathRowsAbove(straight), 0)
+	testutil.AssertTrue(t, pathRowsAbove(curved) > 0)
+}
+
+func TestRenderRouteMap_PolylineWithoutStopCoordinates(t *testing.T) {
+	stops := []models.Stop{{Name: "Stop A"}, {Name: "Stop B"}}
+	path := []models.Coord{{Lat: 50.9, Lon: 6.9}, {Lat: 51.0, Lon: 7.0}}
+
+	out := renderRouteMap(stops, path, 0, 0, -1, 20, 8)
+
+	testutil.AssertFalse(t, strings.Contains(out, mapNoGeodataText))
+	testutil.AssertContains(t, out, "·")
+}
+
+func TestRenderStripMap(t *testing.T) {
+	stops := []models.Stop{{Name: "Stop A"}, {Name: "Stop B"}, {Name: "Stop C", IsCancelled: true}}
+
+	// Room for connectors between the beads
+	out := renderStripMap(stops, 1, 1, -1, 20, 7)
+	lines := strings.Split(out, "\n")
+	testutil.AssertLen(t, lines, 7)
+	testutil.AssertContains(t, lines[0], "○ Stop A")
+	testutil.AssertContains(t, lines[1], "┆")
+	testutil.AssertContains(t, lines[2], "◉")
+	testutil.AssertContains(t, lines[2], "Stop B")
+	testutil.AssertContains(t, lines[3], "│")
+	testutil.AssertContains(t, lines[4], "Stop C")
+	for _, line := range lines {
+		testutil.AssertTrue(t, lipgloss.Width(line) <= 20)
+	}
+
+	// Too short for connectors: one stop per line, scrolled to the selection
+	out = renderStripMap(stops, 0, 2, -1, 20, 2)
+	lines = strings.Split(out, "\n")
+	testutil.AssertLen(t, lines, 2)
+	testutil.AssertContains(t, lines[0], "Stop B")
+	testutil.AssertContains(t, lines[1], "◆")
+
+	testutil.AssertContains(t, renderStripMap(nil, 0, 0, -1, 20, 5), mapUnavailableText)
+}
+
+func TestToggleStripMap(t *testing.T) {
+	m := Model{width: 120, height: 40}
+	testutil.AssertTrue(t, m.routeMapVisible())
+
+	m = m.toggleStripMap()
+	testutil.AssertTrue(t, m.stripMap)
+	testutil.AssertFalse(t, m.routeMapVisible())
+	testutil.AssertContains(t, m.notice, "strip")
+
+	m = m.toggleStripMap()
+	testutil.AssertFalse(t, m.stripMap)
+}
+
+func TestHasGeodata(t *testing.T) {
+	testutil.AssertFalse(t, hasGeodata([]models.Stop{{Name: "A"}}, nil))
+	testutil.AssertTrue(t, hasGeodata([]models.Stop{{Name: "A", Lat: 50.9, Lon: 6.9}}, nil))
+	testutil.AssertTrue(t, hasGeodata(nil, []models.Coord{{Lat: 50.9, Lon: 6.9}, {Lat: 51, Lon: 7}}))
+}
@@ -430,6 +430,97 @@ func TestJourneyScrolling_AutoScrollsOnRefreshWithoutManualScroll(t *testing.T)
 	testutil.AssertFalse(t, m.journeyManualScroll)
 }
 
+func TestJourneyScrolling_OpensAtBoardStation(t *testing.T) {
+	m := newTestModel()
+	m.showJourney = false
+	m.journey = nil
+
+	now := time.Now()
+	past := now.Add(-30 * time.Minute)
+	future := now.Add(30 * time.Minute)
+	later := now.Add(60 * time.Minute)
+
+	// Current stop is B, but the board was opened for station D
+	m.selectedStation = &models.Location{Name: "Stop D", EVA: 4}
+	msg := journeyResultMsg{
+		journeyID: "j-board",
+		journey: &models.Journey{
+			Name: "RE 5",
+			Stops: []models.Stop{
+				{Name: "Stop A", EVA: 1, Arr: &past, Dep: &past},
+				{Name: "Stop B", EVA: 2, Arr: &now, Dep: &now},
+				{Name: "Stop C", EVA: 3, Arr: &future, Dep: &future},
+				{Name: "Stop D", EVA: 4, Arr: &later, Dep: &later},
+			},
+		},
+	}
+
+	newModel, _ := m.Update(msg)
+	m = newModel.(Model)
+
+	testutil.AssertEqual(t, m.journeyScroll, 3)
+	testutil.AssertFalse(t, m.journeyManualScroll)
+}
+
+func TestJourneyScrolling_RefreshFollowsCurrentStop(t *testing.T) {
+	m := newTestModel()
+
+	now := time.Now()
+	past := now.Add(-30 * time.Minute)
+	future := now.Add(30 * time.Minute)
+	later := now.Add(60 * time.Minute)
+	journey := &models.Journey{
+		Name: "RE 5",
+		Stops: []models.Stop{
+			{Name: "Stop A", EVA: 1, Arr: &past, Dep: &past},
+			{Name: "Stop B", EVA: 2, Arr: &now, Dep: &now},
+			{Name: "Stop C", EVA: 3, Arr: &future, Dep: &future},
+			{Name: "Stop D", EVA: 4, Arr: &later, Dep: &later},
+		},
+	}
+
+	// Opened from the board of station D, now showing it
+	m.selectedStation = &models.Location{Name: "Stop D", EVA: 4}
+	m.showJourney = true
+	m.journey = journey
+	m.journeyScroll = 3
+
+	// An auto-refresh tracks the train instead of jumping back to D
+	newModel, _ := m.Update(journeyResultMsg{journeyID: "j-board", journey: journey})
+	m = newModel.(Model)
+
+	testutil.AssertEqual(t, m.journeyScroll, 1)
+}
+
+func TestJourneyScrolling_FallsBackToCurrentStop(t *testing.T) {
+	m := newTestModel()
+	m.showJourney = false
+	m.journey = nil
+
+	now := time.Now()
+	past := now.Add(-30 * time.Minute)
+	future := now.Add(30 * time.Minute)
+
+	// Board station is not part of this journey
+	m.selectedStation = &models.Location{Name: "Elsewhere", EVA: 99}
+	msg := journeyResultMsg{
+		journeyID: "j-board",
+		journey: &models.Journey{
+			Name: "RE 5",
+			Stops: []models.Stop{
+				{Name: "Stop A", EVA: 1, Arr: &past, Dep: &past},
+				{Name: "Stop B", EVA: 2, Arr: &now, Dep: &now},
+				{Name: "Stop C", EVA: 3, Arr: &future, Dep: &future},
+			},
+		},
+	}
+
+	newModel, _ := m.Update(msg)
+	m = newModel.(Model)
+
+	testutil.AssertEqual(t, m.journeyScroll, 1)
+}
+
 func TestJourneyScrolling_RenderWithScroll(t *testing.T) {
 	m := newTestModel()
 	m.showJourney = true
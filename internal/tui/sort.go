@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+type boardSort int
+
+const (
+	sortByTime boardSort = iota
+	sortByDelay
+	sortByLine
+)
+
+var boardSortLabels = []string{"time", "delay", "line"}
+
+// next returns the sort mode that follows s, wrapping around.
+func (s boardSort) next() boardSort {
+	return (s + 1) % boardSort(len(boardSortLabels))
+}
+
+// label returns the display name of the sort mode.
+func (s boardSort) label() string {
+	return boardSortLabels[s]
+}
+
+// sortDepartures sorts departures in place by the given mode.
+// Sorting is stable so entries with equal keys keep their API order;
+// departures without a time sort last.
+func sortDepartures(deps []models.Departure, mode boardSort) {
+	// Scheduled time keeps the default order identical to the API's
+	schedTime := func(d models.Departure) *time.Time {
+		if d.SchedDep != nil {
+			return d.SchedDep
+		}
+		return d.Dep
+	}
+	byTime := func(a, b models.Departure) bool {
+		ta, tb := schedTime(a), schedTime(b)
+		if ta == nil || tb == nil {
+			return ta != nil && tb == nil
+		}
+		return ta.Before(*tb)
+	}
+
+	switch mode {
+	case sortByDelay:
+		sort.SliceStable(deps, func(i, j int) bool {
+			if deps[i].Delay != deps[j].Delay {
+				return deps[i].Delay > deps[j].Delay
+			}
+			return byTime(deps[i], deps[j])
+		})
+	case sortByLine:
+		sort.SliceStable(deps, func(i, j int) bool {
+			li, lj := deps[i].Line, deps[j].Line
+			if li == "" {
+				li = deps[i].TrainShort
+			}
+			if lj == "" {
+				lj = deps[j].TrainShort
+			}
+			if li != lj {
+				return li < lj
+			}
+			return byTime(deps[i], deps[j])
+		})
+	default:
+		sort.SliceStable(deps, func(i, j int) bool {
+			return byTime(deps[i], deps[j])
+		})
+	}
+}
+
+// groupByTransit stably moves rail departures ahead of local transit,
+// preserving the current order within each group.
+func groupByTransit(deps []models.Departure) {
+	sort.SliceStable(deps, func(i, j int) bool {
+		return deps[i].TransitGroup() == models.TransitRail && deps[j].TransitGroup() == models.TransitLocal
+	})
+}
@@ -1,80 +1,148 @@
 package tui
 
 import (
+	"cmp"
 	"fmt"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mobil-koeln/moko-cli/internal/output"
 )
 
-// Colors matching existing output/colors.go scheme
+// Colors of the active theme, for styles built on the fly (route map)
 var (
-	colorCyan    = lipgloss.Color("6")  // Cyan - lines
-	colorYellow  = lipgloss.Color("3")  // Yellow - minor delays
-	colorRed     = lipgloss.Color("1")  // Red - major delays, canceled
-	colorGreen   = lipgloss.Color("2")  // Green - on time
-	colorMagenta = lipgloss.Color("5")  // Magenta - platforms
-	colorWhite   = lipgloss.Color("15") // White - times, text
-	colorGray    = lipgloss.Color("8")  // Gray - muted text
+	colorCyan  lipgloss.Color // accent - focus, upcoming route
+	colorRed   lipgloss.Color // major delays, canceled, current position
+	colorGreen lipgloss.Color // on time, board station
+	colorGray  lipgloss.Color // muted text
 )
 
 // Text styles
 var (
-	styleTime      = lipgloss.NewStyle().Foreground(colorWhite).Bold(true)
-	styleDelay     = lipgloss.NewStyle().Foreground(colorYellow)
-	styleDelayHigh = lipgloss.NewStyle().Foreground(colorRed).Bold(true)
-	styleOnTime    = lipgloss.NewStyle().Foreground(colorGreen)
-	styleLine      = lipgloss.NewStyle().Foreground(colorCyan).Bold(true)
-	stylePlatform  = lipgloss.NewStyle().Foreground(colorMagenta)
-	styleCanceled  = lipgloss.NewStyle().Foreground(colorRed).Bold(true)
-	styleMuted     = lipgloss.NewStyle().Foreground(colorGray)
-	styleHeader    = lipgloss.NewStyle().Foreground(colorWhite).Bold(true)
+	styleTime      lipgloss.Style
+	styleDelay     lipgloss.Style
+	styleDelayHigh lipgloss.Style
+	styleOnTime    lipgloss.Style
+	styleLine      lipgloss.Style
+	styleCategory  lipgloss.Style
+	styleLocal     lipgloss.Style
+	stylePlatform  lipgloss.Style
+	styleCanceled  lipgloss.Style
+	styleBadge     lipgloss.Style
+	styleMuted     lipgloss.Style
+	styleHeader    lipgloss.Style
 )
 
 // Panel border styles
 var (
-	stylePanelFocused = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(colorCyan)
-
-	stylePanelNormal = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(colorGray)
+	stylePanelFocused lipgloss.Style
+	stylePanelNormal  lipgloss.Style
 )
 
 // Selected item in a list
-var styleSelected = lipgloss.NewStyle().Foreground(colorCyan).Bold(true)
-
-// Current stop highlight (red background)
-var styleCurrentStop = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("0")). // Black text
-	Background(colorRed).            // Red background
-	Bold(true)
-
-// Board station highlight (green background)
-var styleBoardStation = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("0")). // Black text
-	Background(colorGreen).          // Green background
-	Bold(true)
-
-// Focused chip cursor in the filter bar — reverse-video style
-var styleChipCursor = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("0")).
-	Background(colorCyan).
-	Bold(true)
+var styleSelected lipgloss.Style
+
+// Highlighted rows: current stop, board station and bookmarked stop of a
+// journey, the platform change banner and the focused filter bar chip
+var (
+	styleCurrentStop  lipgloss.Style
+	styleBoardStation lipgloss.Style
+	styleBookmark     lipgloss.Style
+	styleAlert        lipgloss.Style
+	styleChipCursor   lipgloss.Style
+)
+
+// Markers of the journey stop list: current stop, scroll position and
+// bookmarked stop
+var (
+	markerCurrent  string
+	markerScroll   string
+	markerBookmark string
+)
 
 // Status bar at the bottom
-var styleStatusBar = lipgloss.NewStyle().
-	Foreground(colorGray).
-	Background(lipgloss.Color("0"))
+var styleStatusBar lipgloss.Style
 
 // Loading indicator
-var styleLoading = lipgloss.NewStyle().Foreground(colorYellow).Italic(true)
+var styleLoading lipgloss.Style
 
 // Error text
-var styleError = lipgloss.NewStyle().Foreground(colorRed)
+var styleError lipgloss.Style
 
 // Logo/brand style
-var styleLogo = lipgloss.NewStyle().Foreground(colorRed).Bold(true)
+var styleLogo lipgloss.Style
+
+func init() {
+	applyTheme(output.DefaultTheme)
+}
+
+// themeStyle returns the lipgloss style drawing text in s
+func themeStyle(s output.Style) lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color(s.Color)).
+		Bold(s.Bold).
+		Underline(s.Underline).
+		Reverse(s.Reverse)
+}
+
+// highlightStyle returns a style for black bold text on bg, or bold reverse
+// video when the theme has no background color
+func highlightStyle(bg string) lipgloss.Style {
+	if bg == "" {
+		return lipgloss.NewStyle().Reverse(true).Bold(true)
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("0")). // Black text
+		Background(lipgloss.Color(bg)).
+		Bold(true)
+}
+
+// applyTheme rebuilds the TUI styles from theme. Styles are shared by the
+// whole package, so the last theme applied wins.
+func applyTheme(theme output.Theme) {
+	colorCyan = lipgloss.Color(theme.Accent.Color)
+	colorRed = lipgloss.Color(theme.Canceled.Color)
+	colorGreen = lipgloss.Color(theme.OnTime.Color)
+	colorGray = lipgloss.Color(theme.Muted.Color)
+
+	styleTime = themeStyle(theme.Time)
+	styleDelay = themeStyle(theme.Delay)
+	styleDelayHigh = themeStyle(theme.DelayHigh)
+	styleOnTime = themeStyle(theme.OnTime)
+	styleLine = themeStyle(theme.Line)
+	styleCategory = themeStyle(theme.Category)
+	styleLocal = themeStyle(theme.Local)
+	stylePlatform = themeStyle(theme.Platform)
+	styleCanceled = themeStyle(theme.Canceled)
+	styleBadge = themeStyle(theme.Badge)
+	styleMuted = themeStyle(theme.Muted)
+	styleHeader = themeStyle(theme.Header)
+
+	stylePanelFocused = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorCyan)
+	stylePanelNormal = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorGray)
+
+	styleSelected = themeStyle(theme.Accent)
+
+	styleCurrentStop = highlightStyle(theme.CurrentBg)
+	styleBoardStation = highlightStyle(theme.BoardBg)
+	styleBookmark = highlightStyle(theme.BookmarkBg)
+	styleAlert = highlightStyle(theme.AlertBg)
+	styleChipCursor = highlightStyle(theme.Accent.Color)
+
+	markerCurrent = cmp.Or(theme.CurrentMarker, output.DefaultTheme.CurrentMarker)
+	markerScroll = cmp.Or(theme.ScrollMarker, output.DefaultTheme.ScrollMarker)
+	markerBookmark = cmp.Or(theme.BookmarkMarker, output.DefaultTheme.BookmarkMarker)
+
+	styleStatusBar = lipgloss.NewStyle().
+		Foreground(colorGray).
+		Background(lipgloss.Color("0"))
+	styleLoading = themeStyle(theme.Delay).Italic(true)
+	styleError = themeStyle(theme.Canceled).Bold(false)
+	styleLogo = themeStyle(theme.Canceled)
+}
 
 // formatDelay returns a styled delay string (4-char width)
 func formatDelay(delay int) string {
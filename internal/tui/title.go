@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mobil-koeln/moko-cli/internal/models"
+)
+
+// defaultTitle is the window title before a station is selected
+const defaultTitle = "moko"
+
+// terminalTitle returns the window title for the current board: the station
+// and its next departure or arrival, e.g. "moko: Köln Hbf – S 11 Düsseldorf
+// Hbf 14:32 +2".
+func (m Model) terminalTitle() string {
+	if m.selectedStation == nil {
+		return defaultTitle
+	}
+	title := defaultTitle + ": " + m.selectedStation.Name
+
+	dep, ok := m.nextDeparture()
+	if !ok {
+		return title
+	}
+	title += " – " + departureLabel(dep)
+	place := dep.Destination
+	if m.boardMode == boardArrival {
+		place = dep.ArrivalOrigin()
+	}
+	if place != "" {
+		title += " " + place
+	}
+	title += " " + dep.Dep.Format("15:04")
+	if dep.Delay > 0 {
+		title += fmt.Sprintf(" +%d", dep.Delay)
+	}
+	return title
+}
+
+// nextDeparture returns the earliest departure on the filtered board that is
+// not cancelled and has not left yet, whatever the board is sorted by.
+func (m Model) nextDeparture() (models.Departure, bool) {
+	now := m.now()
+	var next models.Departure
+	found := false
+	for _, dep := range m.filteredDepartures() {
+		if dep.IsCancelled || dep.Dep == nil || dep.Dep.Before(now) {
+			continue
+		}
+		if !found || dep.Dep.Before(*next.Dep) {
+			next, found = dep, true
+		}
+	}
+	return next, found
+}
+
+// syncTitle appends a command setting the window title to cmd when title
+// updates are on and the title changed.
+func (m Model) syncTitle(cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	if !m.setTitle {
+		return m, cmd
+	}
+	title := m.terminalTitle()
+	if title == m.title {
+		return m, cmd
+	}
+	m.title = title
+	return m, tea.Batch(cmd, tea.SetWindowTitle(title))
+}
+
+// quit ends the program, first clearing the window title if moko set one.
+func (m Model) quit() tea.Cmd {
+	if m.title == "" {
+		return tea.Quit
+	}
+	return tea.Sequence(tea.SetWindowTitle(""), tea.Quit)
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mobil-koeln/moko-cli/internal/api"
+	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/testutil"
+)
+
+func TestTerminalTitle(t *testing.T) {
+	now := time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC)
+	at := func(min int) *time.Time {
+		ts := now.Add(time.Duration(min) * time.Minute)
+		return &ts
+	}
+	client, _ := api.NewClient()
+	m := New(client).WithClock(func() time.Time { return now })
+	testutil.AssertEqual(t, m.terminalTitle(), "moko")
+
+	m.selectedStation = &models.Location{Name: "Köln Hbf", EVA: 8000207}
+	testutil.AssertEqual(t, m.terminalTitle(), "moko: Köln Hbf")
+
+	// Gone and cancelled trains are skipped, whatever the board order
+	m.departures = []models.Departure{
+		{Line: "RE 5", Destination: "Koblenz Hbf", Dep: at(-1)},
+		{Line: "S 12", Destination: "Au(Sieg)", Dep: at(9)},
+		{Line: "RE 1", Destination: "Aachen Hbf", Dep: at(1), IsCancelled: true},
+		{Line: "S 11", Destination: "Düsseldorf Hbf", Origin: "Bergisch Gladbach", Dep: at(2), Delay: 2},
+	}
+	testutil.AssertEqual(t, m.terminalTitle(), "moko: Köln Hbf – S 11 Düsseldorf Hbf 14:32 +2")
+
+	m.boardMode = boardArrival
+	testutil.AssertEqual(t, m.terminalTitle(), "moko: Köln Hbf – S 11 Bergisch Gladbach 14:32 +2")
+}
+
+func TestSyncTitle(t *testing.T) {
+	client, _ := api.NewClient()
+	m := New(client)
+
+	// Off unless enabled
+	next, cmd := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = next.(Model)
+	testutil.AssertTrue(t, cmd == nil)
+	testutil.AssertEqual(t, m.title, "")
+	_, isQuit := m.quit()().(tea.QuitMsg)
+	testutil.AssertTrue(t, isQuit)
+
+	m = m.WithTerminalTitle(true)
+	next, cmd = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = next.(Model)
+	testutil.AssertTrue(t, cmd != nil)
+	testutil.AssertEqual(t, m.title, "moko")
+
+	// Unchanged titles are not sent again
+	_, cmd = m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	testutil.AssertTrue(t, cmd == nil)
+
+	// Selecting a station updates the title
+	next, _ = m.selectStation(models.Location{Name: "Bonn Hbf", EVA: 8000044})
+	next, _ = next.(Model).Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m = next.(Model)
+	testutil.AssertEqual(t, m.title, "moko: Bonn Hbf")
+
+	// Quitting clears the title before exiting
+	_, isQuit = m.quit()().(tea.QuitMsg)
+	testutil.AssertFalse(t, isQuit)
+}
@@ -1,15 +1,22 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
-	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mobil-koeln/moko-cli/internal/models"
 	"github.com/mobil-koeln/moko-cli/internal/output"
 )
 
 // Update handles all messages and key events.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	return next.(Model).syncTitle(cmd)
+}
+
+// update dispatches a message to its handler.
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -23,14 +30,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleDeparturesResult(msg)
 
 	case journeyResultMsg:
+		if msg.err == nil && msg.journey != nil {
+			m = m.storeJourney(msg.journeyID, msg.journey)
+		}
 		return m.handleJourneyResult(msg)
 
+	case prefetchTickMsg:
+		return m.handlePrefetchTick(msg)
+
+	case prefetchResultMsg:
+		return m.handlePrefetchResult(msg)
+
 	case autoRefreshTickMsg:
 		return m.handleAutoRefreshTick()
 
 	case countdownTickMsg:
 		return m.handleCountdownTick()
 
+	case noticeMsg:
+		m.notice = string(msg)
+		return m, nil
+
 	case tea.KeyMsg:
 		return m.handleKey(msg)
 	}
@@ -70,7 +90,7 @@ func (m Model) handleSearchResult(msg searchResultMsg) (tea.Model, tea.Cmd) {
 		m.departures = nil
 		m.departureCursor = 0
 		m.showJourney = false
-		return m, fetchBoard(m.client, station, m.selectedModes(), m.boardMode)
+		return m, fetchBoard(m.client, station, m.boardQuery())
 	}
 
 	return m, nil
@@ -83,20 +103,20 @@ func (m Model) handleDeparturesResult(msg departuresResultMsg) (tea.Model, tea.C
 	}
 	m.departuresLoading = false
 	m.departuresErr = msg.err
+	m = m.recordRefreshResult(msg.err)
 	if msg.err == nil {
 		hadData := len(m.departures) > 0
 		m.departures = msg.departures
+		m.arrangeDepartures()
+		// Rebuild the destination list first: the cursor indexes the
+		// filtered list
+		m = m.rebuildDestinationList()
+		filtered := m.filteredDepartures()
 		if hadData && m.selectedJourneyID != "" {
 			// Re-locate the selected journey in the refreshed list
-			found := false
-			for i, dep := range m.departures {
-				if dep.JourneyID == m.selectedJourneyID {
-					m.departureCursor = i
-					found = true
-					break
-				}
-			}
-			if !found {
+			if i := nearestJourneyIdx(filtered, m.selectedJourneyID, m.departureCursor); i >= 0 {
+				m.departureCursor = i
+			} else {
 				// Journey left the board — close the journey view
 				m.showJourney = false
 				m.journey = nil
@@ -105,15 +125,9 @@ func (m Model) handleDeparturesResult(msg departuresResultMsg) (tea.Model, tea.C
 		} else if !hadData {
 			m.departureCursor = 0
 		}
-		// Clamp cursor if list shrank
-		if m.departureCursor >= len(m.departures) && len(m.departures) > 0 {
-			m.departureCursor = len(m.departures) - 1
-		}
-		m.lastUpdate = time.Now()
+		m.lastUpdate = m.now()
 
-		// Rebuild destination list and clamp departure cursor to filtered list
-		m = m.rebuildDestinationList()
-		filtered := m.filteredDepartures()
+		// Clamp cursor if the filtered list shrank
 		if len(filtered) == 0 {
 			m.departureCursor = 0
 		} else if m.departureCursor >= len(filtered) {
@@ -126,11 +140,24 @@ func (m Model) handleDeparturesResult(msg departuresResultMsg) (tea.Model, tea.C
 func (m Model) handleJourneyResult(msg journeyResultMsg) (tea.Model, tea.Cmd) {
 	m.journeyLoading = false
 	m.journeyErr = msg.err
+	// Without board refreshes the journey tells whether the API is reachable
+	if m.journeyOnlyActive() {
+		m = m.recordRefreshResult(msg.err)
+	}
+	var cmd tea.Cmd
 	if msg.err == nil {
 		wasShowing := m.showJourney && m.journey != nil
+		prev := m.journey
 		m.journey = msg.journey
 		m.showJourney = true
 
+		// Bookmarks belong to a single journey
+		if msg.journeyID != m.bookmarkJourneyID {
+			m = m.clearBookmark()
+		}
+		m = m.checkBookmarkApproach()
+		m, cmd = m.checkPlatformChange(prev)
+
 		// ALWAYS clamp scroll position after setting journey (strengthened reactive clamping)
 		if m.journey != nil && len(m.journey.Stops) > 0 {
 			if m.journeyScroll >= len(m.journey.Stops) {
@@ -144,24 +171,66 @@ func (m Model) handleJourneyResult(msg journeyResultMsg) (tea.Model, tea.Cmd) {
 		if wasShowing && m.journeyManualScroll {
 			// User manually scrolled — position already clamped above
 		} else {
-			// New journey or no manual scroll — auto-scroll to current station.
+			// A new journey opens at the board station it was picked from;
+			// refreshes without manual scroll follow the current station.
 			m.journeyManualScroll = false
-			currentIdx := output.FindCurrentStopIndex(m.journey.Stops, time.Now())
-			if currentIdx >= 0 {
+			boardIdx := -1
+			if !wasShowing {
+				boardIdx = findBoardStationIdx(m.journey.Stops, m.selectedStation)
+			}
+			if boardIdx >= 0 {
+				m.journeyScroll = boardIdx
+			} else if currentIdx := output.FindCurrentStopIndex(m.journey.Stops, m.now()); currentIdx >= 0 {
 				m.journeyScroll = currentIdx
 			} else {
 				m.journeyScroll = 0
 			}
 		}
 	}
-	return m, nil
+	return m, cmd
 }
 
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Global keys
+	m.notice = ""
+
 	switch msg.String() {
 	case "ctrl+c":
-		return m, tea.Quit
+		return m, m.quit()
+	}
+
+	if m.paletteOpen {
+		return m.handlePaletteKeys(msg)
+	}
+	if m.stopFilterOpen {
+		return m.handleStopFilterKeys(msg)
+	}
+
+	switch msg.String() {
+	case "ctrl+p":
+		return m.openPalette()
+	case "x":
+		if m.platformAlert != "" && m.focus != focusSearch {
+			return m.dismissPlatformAlert(), nil
+		}
+	case ":":
+		if m.focus != focusSearch {
+			return m.openPalette()
+		}
+	case "+", "=", "-":
+		if m.focus != focusSearch {
+			if msg.String() == "-" {
+				return m.shiftBoardTime(-1)
+			}
+			return m.shiftBoardTime(1)
+		}
+	case "<", ">":
+		if m.focus != focusSearch {
+			if msg.String() == "<" {
+				return m.adjustBoardVias(-1)
+			}
+			return m.adjustBoardVias(1)
+		}
 	}
 
 	switch m.focus {
@@ -243,7 +312,7 @@ func (m Model) handleStationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	switch msg.String() {
 	case "q":
-		return m, tea.Quit
+		return m, m.quit()
 
 	case "tab":
 		if len(m.departures) > 0 {
@@ -314,21 +383,25 @@ func (m Model) handleStationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "enter":
 		if len(m.stations) > 0 {
-			station := m.stations[m.stationCursor]
-			m.selectedStation = &station
-			m.departuresLoading = true
-			m.departuresErr = nil
-			m.departures = nil
-			m.departureCursor = 0
-			m.showJourney = false
-			m.journey = nil
-			return m, fetchBoard(m.client, station, m.selectedModes(), m.boardMode)
+			return m.selectStation(m.stations[m.stationCursor])
 		}
 	}
 
 	return m, nil
 }
 
+// selectStation shows the board of station, closing any open journey.
+func (m Model) selectStation(station models.Location) (tea.Model, tea.Cmd) {
+	m.selectedStation = &station
+	m.departuresLoading = true
+	m.departuresErr = nil
+	m.departures = nil
+	m.departureCursor = 0
+	m.showJourney = false
+	m.journey = nil
+	return m, fetchBoard(m.client, station, m.boardQuery())
+}
+
 func (m Model) handleDepartureKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	deps := m.filteredDepartures()
 
@@ -344,7 +417,7 @@ func (m Model) handleDepartureKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	switch msg.String() {
 	case "q":
-		return m, tea.Quit
+		return m, m.quit()
 
 	case "tab":
 		if len(m.destinationList) > 0 {
@@ -362,6 +435,10 @@ func (m Model) handleDepartureKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "esc":
+		if m.pinnedLine != "" {
+			m.pinnedLine = ""
+			return m, nil
+		}
 		if m.showJourney {
 			m.showJourney = false
 			m.journey = nil
@@ -380,13 +457,13 @@ func (m Model) handleDepartureKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.departureCursor < len(deps)-1 {
 			m.departureCursor++
 		}
-		return m, nil
+		return m.schedulePrefetch()
 
 	case "k", "up":
 		if m.departureCursor > 0 {
 			m.departureCursor--
 		}
-		return m, nil
+		return m.schedulePrefetch()
 
 	case "pgdown":
 		if len(deps) > 0 {
@@ -400,7 +477,7 @@ func (m Model) handleDepartureKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.departureCursor = len(deps) - 1
 			}
 		}
-		return m, nil
+		return m.schedulePrefetch()
 
 	case "pgup":
 		if len(deps) > 0 {
@@ -413,27 +490,44 @@ func (m Model) handleDepartureKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.departureCursor = 0
 			}
 		}
-		return m, nil
+		return m.schedulePrefetch()
 
 	case "home":
 		m.departureCursor = 0
-		return m, nil
+		return m.schedulePrefetch()
 
 	case "end":
 		if len(deps) > 0 {
 			m.departureCursor = len(deps) - 1
 		}
-		return m, nil
+		return m.schedulePrefetch()
+
+	case "s":
+		return m.cycleSort(), nil
+
+	case "g":
+		return m.toggleGrouping(), nil
+
+	case "p":
+		m.prefetch = !m.prefetch
+		return m.schedulePrefetch()
+
+	case "*":
+		return m.togglePin(), nil
 
 	case "enter":
 		if len(deps) > 0 {
 			dep := deps[m.departureCursor]
 			if dep.JourneyID != "" {
+				m = m.cancelPrefetch()
 				m.selectedJourneyID = dep.JourneyID
-				m.journeyLoading = true
 				m.journeyErr = nil
 				m.journey = nil
-				return m, fetchJourney(m.client, dep.JourneyID)
+				if journey, ok := m.cachedJourney(dep.JourneyID); ok {
+					return m.handleJourneyResult(journeyResultMsg{journeyID: dep.JourneyID, journey: journey})
+				}
+				m.journeyLoading = true
+				return m, fetchJourney(m.client, dep.JourneyID, m.routeMapVisible())
 			}
 		}
 	}
@@ -441,28 +535,188 @@ func (m Model) handleDepartureKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m Model) handleAutoRefreshKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case " ", "enter":
-		m.autoRefresh = !m.autoRefresh
-		if m.autoRefresh {
-			// Do immediate update when enabling auto-refresh
-			var cmds []tea.Cmd
-			cmds = append(cmds, autoRefreshTick(), countdownTick())
-
-			// Immediately refresh board if a station is selected
-			if m.selectedStation != nil {
-				cmds = append(cmds, fetchBoard(m.client, *m.selectedStation, m.selectedModes(), m.boardMode))
+// checkPlatformChange compares the platform at the watched stop (the
+// bookmark, else the board station) with the previous copy of the journey and
+// raises a sticky alert with a terminal bell when it changed. The alert is
+// cleared once the train has left that stop.
+func (m Model) checkPlatformChange(prev *models.Journey) (Model, tea.Cmd) {
+	if m.journey == nil {
+		return m, nil
+	}
+	stops := m.journey.Stops
+
+	if prev == nil {
+		// A different journey was opened
+		m = m.dismissPlatformAlert()
+	} else if m.platformAlertStop != "" {
+		now := m.now()
+		for i, stop := range stops {
+			if stopKey(stop) != m.platformAlertStop {
+				continue
 			}
-
-			// Immediately refresh journey if one is displayed
-			if m.showJourney && m.selectedJourneyID != "" {
-				cmds = append(cmds, fetchJourney(m.client, m.selectedJourneyID))
+			left := stop.Dep != nil && now.After(*stop.Dep)
+			if left || output.FindCurrentStopIndex(stops, now) > i {
+				m = m.dismissPlatformAlert()
 			}
+			break
+		}
+	}
+
+	idx := m.bookmarkIdx()
+	if idx < 0 {
+		idx = findBoardStationIdx(stops, m.selectedStation)
+	}
+	if prev == nil || idx < 0 {
+		return m, nil
+	}
+
+	stop := stops[idx]
+	if stop.Dep != nil && m.now().After(*stop.Dep) {
+		return m, nil
+	}
+	key := stopKey(stop)
+	for _, old := range prev.Stops {
+		if stopKey(old) != key {
+			continue
+		}
+		was, now := old.EffectivePlatform(), stop.EffectivePlatform()
+		if was == "" || now == "" || was == now {
+			return m, nil
+		}
+		m.platformAlert = fmt.Sprintf("Platform change at %s: now Pl. %s (was %s)", stop.Name, now, was)
+		m.platformAlertStop = key
+		return m, ringBell()
+	}
+	return m, nil
+}
+
+// dismissPlatformAlert clears the platform change banner.
+func (m Model) dismissPlatformAlert() Model {
+	m.platformAlert = ""
+	m.platformAlertStop = ""
+	return m
+}
+
+// arrangeDepartures applies the current sort mode and transit grouping in place.
+func (m Model) arrangeDepartures() {
+	sortDepartures(m.departures, m.departureSort)
+	if m.groupByMode {
+		groupByTransit(m.departures)
+	}
+}
+
+// cycleSort switches to the next departure sort mode, keeping the cursor on the same journey.
+func (m Model) cycleSort() Model {
+	m.departureSort = m.departureSort.next()
+	return m.rearrange()
+}
+
+// toggleGrouping switches rail/local grouping, keeping the cursor on the same journey.
+func (m Model) toggleGrouping() Model {
+	m.groupByMode = !m.groupByMode
+	return m.rearrange()
+}
 
-			return m, tea.Batch(cmds...)
+// rearrange re-sorts the departures and moves the cursor to follow the
+// previously highlighted journey.
+func (m Model) rearrange() Model {
+	var cursorID string
+	deps := m.filteredDepartures()
+	if m.departureCursor >= 0 && m.departureCursor < len(deps) {
+		cursorID = deps[m.departureCursor].JourneyID
+	}
+	m.arrangeDepartures()
+	if cursorID != "" {
+		if i := nearestJourneyIdx(m.filteredDepartures(), cursorID, m.departureCursor); i >= 0 {
+			m.departureCursor = i
 		}
+	}
+	return m
+}
+
+// nearestJourneyIdx returns the index of the departure with the given journey
+// ID closest to prev, or -1. Split services can list several entries with the
+// same ID, and the nearest one keeps the cursor from jumping.
+func nearestJourneyIdx(deps []models.Departure, journeyID string, prev int) int {
+	best, bestDist := -1, 0
+	for i, dep := range deps {
+		if dep.JourneyID != journeyID {
+			continue
+		}
+		dist := i - prev
+		if dist < 0 {
+			dist = -dist
+		}
+		if best < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
+
+// toggleAutoRefresh turns auto-refresh on or off, refreshing immediately when enabled.
+func (m Model) toggleAutoRefresh() (tea.Model, tea.Cmd) {
+	m.autoRefresh = !m.autoRefresh
+	if !m.autoRefresh {
 		return m, nil
+	}
+	// Do immediate update when enabling auto-refresh
+	m.refreshFailures, m.refreshBlocked = 0, false
+	m, tick := m.scheduleRefresh()
+	m2, cmd := m.reload()
+	return m2, tea.Batch(tick, countdownTick(), cmd)
+}
+
+// reload silently re-fetches the board and the displayed journey, keeping
+// existing data visible until new data arrives.
+func (m Model) reload() (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	// Refresh board if a station is selected
+	if m.selectedStation != nil {
+		cmds = append(cmds, fetchBoard(m.client, *m.selectedStation, m.boardQuery()))
+	}
+
+	// Refresh journey if one is displayed
+	if m.showJourney && m.selectedJourneyID != "" {
+		cmds = append(cmds, fetchJourney(m.client, m.selectedJourneyID, m.routeMapVisible()))
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// toggleJourneyOnlyRefresh switches auto-refresh between refetching board and
+// journey and refetching only the open journey.
+func (m Model) toggleJourneyOnlyRefresh() Model {
+	m.journeyOnlyRefresh = !m.journeyOnlyRefresh
+	return m
+}
+
+// journeyOnlyActive reports whether auto-refresh skips the board and only
+// refetches the open journey. Without an open journey the board refreshes as usual.
+func (m Model) journeyOnlyActive() bool {
+	return m.journeyOnlyRefresh && m.showJourney && m.selectedJourneyID != ""
+}
+
+func (m Model) handleAutoRefreshKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "h", "left":
+		if m.refreshCursor > 0 {
+			m.refreshCursor--
+		}
+		return m, nil
+
+	case "l", "right":
+		if m.refreshCursor < 1 {
+			m.refreshCursor++
+		}
+		return m, nil
+
+	case " ", "enter":
+		if m.refreshCursor == 1 {
+			return m.toggleJourneyOnlyRefresh(), nil
+		}
+		return m.toggleAutoRefresh()
 
 	case "tab":
 		if len(m.stations) > 0 {
@@ -483,7 +737,7 @@ func (m Model) handleAutoRefreshKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "q":
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	return m, nil
@@ -494,22 +748,17 @@ func (m Model) handleAutoRefreshTick() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	var cmds []tea.Cmd
+	// Schedule the next tick, later while the API is failing
+	m, tick := m.scheduleRefresh()
 
-	// Schedule next tick
-	cmds = append(cmds, autoRefreshTick())
-
-	// Silently refresh board — keep existing data visible until new data arrives
-	if m.selectedStation != nil {
-		cmds = append(cmds, fetchBoard(m.client, *m.selectedStation, m.selectedModes(), m.boardMode))
+	// Tracking a single train: leave the board alone
+	if m.journeyOnlyActive() {
+		return m, tea.Batch(tick, fetchJourney(m.client, m.selectedJourneyID, m.routeMapVisible()))
 	}
 
-	// Silently refresh journey if one is displayed
-	if m.showJourney && m.selectedJourneyID != "" {
-		cmds = append(cmds, fetchJourney(m.client, m.selectedJourneyID))
-	}
-
-	return m, tea.Batch(cmds...)
+	// Silently refresh board and journey
+	m2, cmd := m.reload()
+	return m2, tea.Batch(tick, cmd)
 }
 
 func (m Model) handleCountdownTick() (tea.Model, tea.Cmd) {
@@ -533,13 +782,16 @@ func (m Model) handleJourneyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	switch msg.String() {
 	case "q":
-		return m, tea.Quit
+		return m, m.quit()
 
 	case "tab", "/":
 		m.focus = focusSearch
 		m.searchInput.Focus()
 		return m, nil
 
+	case "f":
+		return m.openStopFilter()
+
 	case "shift+tab":
 		if len(m.destinationList) > 0 {
 			m.focus = focusDestinations
@@ -608,6 +860,21 @@ func (m Model) handleJourneyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.journeyManualScroll = true
 		}
 		return m, nil
+
+	case "m":
+		return m.toggleBookmark(), nil
+
+	case "v":
+		return m.toggleStripMap(), nil
+
+	case "'":
+		if idx := m.bookmarkIdx(); idx >= 0 {
+			m.journeyScroll = idx
+			m.journeyManualScroll = true
+		} else {
+			m.notice = "No bookmarked stop"
+		}
+		return m, nil
 	}
 
 	return m, nil
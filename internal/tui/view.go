@@ -3,7 +3,6 @@ package tui
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mobil-koeln/moko-cli/internal/models"
@@ -21,6 +20,10 @@ func (m Model) View() string {
 	searchBar := m.renderSearchBar()
 	filterBar := m.renderFilterBar()
 	statusBar := m.renderStatusBar()
+	if m.platformAlert != "" {
+		// The sticky banner sits directly below the filter bar
+		filterBar = lipgloss.JoinVertical(lipgloss.Left, filterBar, m.renderPlatformAlert())
+	}
 
 	headerHeight := lipgloss.Height(header)
 	searchHeight := lipgloss.Height(searchBar)
@@ -64,6 +67,9 @@ func (m Model) View() string {
 		Render(rightPanel)
 
 	panels := lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
+	if m.paletteOpen {
+		panels = m.overlayPalette(m.width, lipgloss.Height(panels))
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, header, searchBar, filterBar, panels, statusBar)
 }
@@ -229,9 +235,15 @@ func (m Model) renderRightPanel(width, height int) string {
 		}
 
 		journeyView := m.renderJourneyDetail(journeyWidth, contentHeight)
-		currentIdx := output.FindCurrentStopIndex(m.journey.Stops, time.Now())
+		currentIdx := output.FindCurrentStopIndex(m.journey.Stops, m.now())
 		boardStationIdx := findBoardStationIdx(m.journey.Stops, m.selectedStation)
-		mapView := renderRouteMap(m.journey.Stops, currentIdx, m.journeyScroll, boardStationIdx, mapWidth, contentHeight)
+		// Journeys without coordinates fall back to the strip
+		var mapView string
+		if m.stripMap || !hasGeodata(m.journey.Stops, m.journey.Polyline) {
+			mapView = renderStripMap(m.journey.Stops, currentIdx, m.journeyScroll, boardStationIdx, mapWidth, contentHeight)
+		} else {
+			mapView = renderRouteMap(m.journey.Stops, m.journey.Polyline, currentIdx, m.journeyScroll, boardStationIdx, mapWidth, contentHeight)
+		}
 
 		journeyBox := lipgloss.NewStyle().Width(journeyWidth).Height(contentHeight).Render(journeyView)
 		mapBox := lipgloss.NewStyle().Width(mapWidth).Height(contentHeight).Render(mapView)
@@ -264,6 +276,16 @@ func (m Model) renderDepartureList(width, height int) string {
 	if m.focus == focusDepartures {
 		title = "▶ " + title // Add indicator when focused
 	}
+	title += " [sort: " + m.departureSort.label() + "]"
+	if m.groupByMode {
+		title += " [grouped]"
+	}
+	if m.prefetch {
+		title += " [prefetch]"
+	}
+	if m.pinnedLine != "" {
+		title += " [pin: " + m.pinnedLine + "]"
+	}
 	// Show filter status in title when some destinations are inactive
 	if len(m.destinationFilters) > 0 {
 		active := 0
@@ -296,7 +318,10 @@ func (m Model) renderDepartureList(width, height int) string {
 	// Reserve space for scrollbar
 	contentWidth := width - 2
 
-	maxVisible := height - 2
+	// The pinned line stays above the scrolling list
+	pinned := m.renderPinned(contentWidth)
+
+	maxVisible := height - 2 - len(pinned)
 	if maxVisible < 1 {
 		maxVisible = 1
 	}
@@ -340,6 +365,9 @@ func (m Model) renderDepartureList(width, height int) string {
 		}
 	}
 
+	if len(pinned) > 0 {
+		titleStr += "\n" + strings.Join(pinned, "\n")
+	}
 	return titleStr + "\n" + b.String()
 }
 
@@ -354,15 +382,8 @@ func renderDepartureLine(dep models.Departure, width int, selected bool) string
 	// Delay
 	delayStr := formatDelay(dep.Delay)
 
-	// Line name (truncate to 10)
-	line := dep.Line
-	if line == "" {
-		line = dep.TrainShort
-	}
-	if len(line) > 10 {
-		line = line[:10]
-	}
-	lineStr := fmt.Sprintf("%-10s", line)
+	// Line name: category + number (truncate to 10)
+	lineStr := renderLineLabel(dep, 10, dep.IsCancelled)
 
 	// Platform
 	platform := dep.EffectivePlatform()
@@ -379,6 +400,19 @@ func renderDepartureLine(dep models.Departure, width int, selected bool) string
 	// Calculate remaining width for destination
 	fixedWidth := 5 + 1 + 4 + 2 + 10 + 2 + 7 // time+sp+delay+sp+line+sp+platform
 	maxDest := width - fixedWidth - 4        // 4 for cursor indicator + padding
+	badge := ""
+	if dep.OnDemand && !dep.IsCancelled {
+		badge = " " + styleBadge.Render(output.OnDemandBadge)
+		maxDest -= len(output.OnDemandBadge) + 1
+	}
+	if endpoint := output.EndpointBadge(dep); endpoint != "" {
+		badge += " " + styleMuted.Render(endpoint)
+		maxDest -= len(endpoint) + 1
+	}
+	if occ, occWidth := renderOccupancy(dep); occ != "" && !dep.IsCancelled {
+		badge += " " + occ
+		maxDest -= occWidth + 1
+	}
 	if maxDest > 0 && len(dest) > maxDest {
 		dest = dest[:maxDest]
 	}
@@ -388,7 +422,7 @@ func renderDepartureLine(dep models.Departure, width int, selected bool) string
 		entry = fmt.Sprintf("%s %s  %s  %s %s",
 			styleTime.Render(timeStr),
 			delayStr,
-			styleCanceled.Render(lineStr),
+			lineStr,
 			stylePlatform.Render(platformStr),
 			styleCanceled.Render(dest+" [X]"),
 		)
@@ -396,9 +430,9 @@ func renderDepartureLine(dep models.Departure, width int, selected bool) string
 		entry = fmt.Sprintf("%s %s  %s  %s %s",
 			styleTime.Render(timeStr),
 			delayStr,
-			styleLine.Render(lineStr),
+			lineStr,
 			stylePlatform.Render(platformStr),
-			dest,
+			dest+badge,
 		)
 	}
 
@@ -408,6 +442,61 @@ func renderDepartureLine(dep models.Departure, width int, selected bool) string
 	return " " + entry
 }
 
+// renderOccupancy renders the occupancy forecast of a departure as "1▂ 2▆"
+// with green to red bars, and returns its display width
+func renderOccupancy(dep models.Departure) (string, int) {
+	var parts []string
+	width := 0
+	for _, class := range []struct {
+		label string
+		level models.Occupancy
+	}{{"1", dep.OccupancyFirst}, {"2", dep.OccupancySecond}} {
+		glyph := output.OccupancyGlyph(class.level, false)
+		if glyph == "" {
+			continue
+		}
+		style := styleDelayHigh
+		switch class.level {
+		case models.OccupancyLow:
+			style = styleOnTime
+		case models.OccupancyMedium:
+			style = styleDelay
+		}
+		parts = append(parts, styleMuted.Render(class.label)+style.Render(glyph))
+		width += 2
+	}
+	if len(parts) == 0 {
+		return "", 0
+	}
+	return strings.Join(parts, " "), width + len(parts) - 1
+}
+
+// renderLineLabel renders the product category and line number of a departure
+// as separately styled parts, truncated and padded to width characters.
+// Cancelled departures render both parts in the cancelled style.
+func renderLineLabel(dep models.Departure, width int, cancelled bool) string {
+	category, number, padding := output.LineLabel(dep, width)
+
+	catStyle, numStyle := styleCategory, styleLine
+	if dep.TransitGroup() == models.TransitLocal {
+		catStyle = styleLocal
+	}
+	if cancelled {
+		catStyle, numStyle = styleCanceled, styleCanceled
+	}
+
+	styled := numStyle.Render(number)
+	if category != "" {
+		styled = catStyle.Render(category) + " " + styled
+	}
+	return styled + padding
+}
+
+// renderPlatformAlert renders the sticky platform change banner.
+func (m Model) renderPlatformAlert() string {
+	return styleAlert.Width(m.width).Render(" ⚠ " + m.platformAlert + "  (x: dismiss)")
+}
+
 // renderStatusBar renders context-aware keyboard hints at the bottom.
 func (m Model) renderStatusBar() string {
 	var hints string
@@ -419,15 +508,15 @@ func (m Model) renderStatusBar() string {
 	case focusBoard:
 		hints = "h/l:move  Space:select  Tab:next  Shift+Tab:back  Esc:search  q:quit"
 	case focusAutoRefresh:
-		hints = "Space:toggle  Tab:next  Shift+Tab:back  Esc:search  q:quit"
+		hints = "h/l:move  Space:toggle  Tab:next  Shift+Tab:back  Esc:search  q:quit"
 	case focusStations:
 		hints = "j/k:nav  PgUp/PgDn:page  Home/End:jump  Enter:select  Tab/Shift+Tab:nav  /:search  q:quit"
 	case focusDepartures:
-		hints = "j/k:nav  PgUp/PgDn:page  Home/End:jump  Enter:journey  Tab/Shift+Tab:nav  Esc:back  q:quit"
+		hints = "j/k:nav  PgUp/PgDn:page  Home/End:jump  Enter:journey  s:sort  g:group  p:prefetch  *:pin  +/-:time  </>:vias  Tab/Shift+Tab:nav  Esc:back  q:quit"
 	case focusDestinations:
 		hints = "j/k:nav  Space:toggle  a:all  Tab:next  Shift+Tab:back  Esc:search  q:quit"
 	case focusJourney:
-		hints = "j/k:scroll  PgUp/PgDn:page  Home/End:jump  Tab/Shift+Tab:nav  Esc:back  q:quit"
+		hints = "j/k:scroll  PgUp/PgDn:page  Home/End:jump  f:find stop  m:mark stop  ':go to mark  v:map style  Tab/Shift+Tab:nav  Esc:back  q:quit"
 	}
 
 	// Add scroll position indicator
@@ -445,10 +534,30 @@ func (m Model) renderStatusBar() string {
 		}
 	}
 
+	if m.paletteOpen {
+		hints = "Type to filter  ↑/↓:select  Enter:run  Esc:close"
+		indicator = ""
+	} else if m.stopFilterOpen {
+		hints = m.stopFilterInput.View() + "  Enter:keep  Esc:clear"
+		if m.stopFilterNoMatch {
+			hints += "  " + styleDelay.Render("no match")
+		}
+	}
+
 	statusText := " " + hints
+	switch {
+	case m.paletteOpen, m.stopFilterOpen:
+	case m.focus == focusSearch:
+		statusText += "  Ctrl+P:commands"
+	default:
+		statusText += "  ::commands"
+	}
 	if indicator != "" {
 		statusText += "  │  " + indicator
 	}
+	if m.notice != "" {
+		statusText += "  │  " + m.notice
+	}
 
 	return styleStatusBar.Width(m.width).Render(statusText)
 }
@@ -463,9 +572,10 @@ func scrollIndicator(cursor, total int) string {
 
 // renderJourneyLegend renders a one-line colour legend for the journey stop list.
 func renderJourneyLegend(width int) string {
-	redSquare := styleCurrentStop.Render(" ")
+	redSquare := styleCurrentStop.Render(markerCurrent)
 	greenSquare := styleBoardStation.Render(" ")
-	legend := " " + redSquare + " Current Station   " + greenSquare + " Journey Station"
+	yellowSquare := styleBookmark.Render(markerBookmark)
+	legend := " " + redSquare + " Current Station   " + greenSquare + " Journey Station   " + yellowSquare + " Bookmarked Stop"
 	return styleMuted.Width(width).Render(legend)
 }
 
@@ -1,11 +1,14 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/mobil-koeln/moko-cli/internal/api"
 	"github.com/mobil-koeln/moko-cli/internal/models"
+	"github.com/mobil-koeln/moko-cli/internal/output"
 	"github.com/mobil-koeln/moko-cli/internal/testutil"
 )
 
@@ -125,6 +128,38 @@ func TestRenderDepartureList(t *testing.T) {
 	testutil.AssertTrue(t, len(output) > 0)
 }
 
+func TestRenderDepartureLine_OnDemand(t *testing.T) {
+	depTime := time.Now()
+	dep := models.Departure{Type: "Bus", Line: "Bus 271", Destination: "Much", Dep: &depTime, OnDemand: true}
+
+	testutil.AssertContains(t, renderDepartureLine(dep, 80, false), output.OnDemandBadge)
+
+	dep.IsCancelled = true
+	testutil.AssertFalse(t, strings.Contains(renderDepartureLine(dep, 80, false), output.OnDemandBadge))
+}
+
+func TestRenderDepartureLine_Occupancy(t *testing.T) {
+	depTime := time.Now()
+	dep := models.Departure{Type: "ICE", Line: "ICE 623", Destination: "München Hbf", Dep: &depTime,
+		OccupancyFirst: models.OccupancyLow, OccupancySecond: models.OccupancyVeryHigh}
+
+	line := renderDepartureLine(dep, 80, false)
+	testutil.AssertContains(t, line, "▂")
+	testutil.AssertContains(t, line, "█")
+	testutil.AssertTrue(t, lipgloss.Width(line) <= 80)
+
+	occ, width := renderOccupancy(dep)
+	testutil.AssertEqual(t, width, lipgloss.Width(occ))
+
+	dep.OccupancyFirst = models.OccupancyUnknown
+	_, width = renderOccupancy(dep)
+	testutil.AssertEqual(t, width, 2)
+
+	dep.OccupancySecond = models.OccupancyUnknown
+	occ, _ = renderOccupancy(dep)
+	testutil.AssertEqual(t, occ, "")
+}
+
 func TestRenderRightPanel(t *testing.T) {
 	client, _ := api.NewClient()
 	m := New(client)